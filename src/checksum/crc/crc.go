@@ -0,0 +1,299 @@
+// Package crc implements cyclic redundancy checks of 8 to 64 bits with any
+// polynomial, in the parameter model of Williams ("A Painless Guide to CRC
+// Error Detection Algorithms", 1993), such as CRC-32 and CRC-64.
+//
+// Checksums are computed 8 bytes at a time with slicing-by-8 tables, and the
+// CRCs of two strings can be combined into the CRC of their concatenation.
+//
+// CRCs detect accidental errors, they are not MACs and provide no security
+// against deliberate modifications.
+package crc
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+)
+
+// Params describes a CRC.
+type Params struct {
+	// Width of the CRC in bits, from 8 to 64
+	Width int
+	// Poly is the generator polynomial in normal form, most significant
+	// bit first, without the x^Width term
+	Poly uint64
+	// Init is the initial value of the register
+	Init uint64
+	// RefIn reflects the bits of each input byte
+	RefIn bool
+	// RefOut reflects the final value
+	RefOut bool
+	// XorOut is xored to the final value
+	XorOut uint64
+}
+
+// Predefined CRCs, named as in the CRC catalogue of Greg Cook
+// (https://reveng.sourceforge.io/crc-catalogue/).
+var (
+	// CRC-32/ISO-HDLC, used by Ethernet, zip and PNG
+	IEEE = Params{Width: 32, Poly: 0x04c11db7, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffff}
+	// CRC-32/ISCSI (CRC-32C), used by iSCSI, SCTP and ext4
+	Castagnoli = Params{Width: 32, Poly: 0x1edc6f41, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffff}
+	// CRC-32/KOOPMAN (CRC-32K)
+	Koopman = Params{Width: 32, Poly: 0x741b8cd7, Init: 0xffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffff}
+	// CRC-64/GO-ISO, the polynomial of ISO 3309
+	ISO = Params{Width: 64, Poly: 0x000000000000001b, Init: 0xffffffffffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffffffffffff}
+	// CRC-64/XZ, the polynomial of ECMA-182
+	ECMA = Params{Width: 64, Poly: 0x42f0e1eba9ea3693, Init: 0xffffffffffffffff, RefIn: true, RefOut: true, XorOut: 0xffffffffffffffff}
+)
+
+// Table holds the slicing-by-8 tables of a CRC.
+type Table struct {
+	p    Params
+	mask uint64
+	// Register shift of non reflected CRCs, which are computed left-aligned
+	// in 64 bits
+	shift int
+	t     [8][256]uint64
+}
+
+// MakeTable returns the tables of the CRC p.
+// Returns an error if the width is not between 8 and 64 bits.
+func MakeTable(p Params) (*Table, error) {
+	if p.Width < 8 || p.Width > 64 {
+		return nil, errors.New("checksum/crc: width must be from 8 to 64 bits")
+	}
+
+	t := &Table{
+		p:    p,
+		mask: ^uint64(0) >> (64 - p.Width),
+	}
+
+	// Table of one byte
+	if p.RefIn {
+		poly := reflect(p.Poly, p.Width)
+		for b := range t.t[0] {
+			r := uint64(b)
+			for i := 0; i < 8; i++ {
+				r = r>>1 ^ poly&-(r&1)
+			}
+			t.t[0][b] = r
+		}
+	} else {
+		t.shift = 64 - p.Width
+		poly := p.Poly << t.shift
+		for b := range t.t[0] {
+			r := uint64(b) << 56
+			for i := 0; i < 8; i++ {
+				r = r<<1 ^ poly&-(r>>63)
+			}
+			t.t[0][b] = r
+		}
+	}
+
+	// Tables of bytes followed by k zero bytes
+	for k := 1; k < len(t.t); k++ {
+		for b := range t.t[k] {
+			t.t[k][b] = t.updateByte(t.t[k-1][b], 0, 0)
+		}
+	}
+
+	return t, nil
+}
+
+// reflect reverses the order of the w lower bits of v.
+func reflect(v uint64, w int) uint64 {
+	return bits.Reverse64(v) >> (64 - w)
+}
+
+// updateByte processes one byte b with the k-th table.
+func (t *Table) updateByte(r uint64, b byte, k int) uint64 {
+	if t.p.RefIn {
+		return t.t[k][byte(r)^b] ^ r>>8
+	}
+	return t.t[k][byte(r>>56)^b] ^ r<<8
+}
+
+// update processes p on the register r.
+func (t *Table) update(r uint64, p []byte) uint64 {
+	// Slicing-by-8, each byte of the xor of the register and the next 8
+	// bytes is followed by a known number of bytes
+	for ; len(p) >= 8; p = p[8:] {
+		var x uint64
+		if t.p.RefIn {
+			x = r ^ binary.LittleEndian.Uint64(p)
+		} else {
+			x = r ^ binary.BigEndian.Uint64(p)
+		}
+
+		r = 0
+		for i := 0; i < 8; i++ {
+			var b byte
+			if t.p.RefIn {
+				b = byte(x >> (8 * i))
+			} else {
+				b = byte(x >> (56 - 8*i))
+			}
+			r ^= t.t[7-i][b]
+		}
+	}
+
+	for _, b := range p {
+		r = t.updateByte(r, b, 0)
+	}
+
+	return r
+}
+
+// initial returns the initial register.
+func (t *Table) initial() uint64 {
+	if t.p.RefIn {
+		return reflect(t.p.Init&t.mask, t.p.Width)
+	}
+	return (t.p.Init & t.mask) << t.shift
+}
+
+// final returns the CRC value of the register r.
+func (t *Table) final(r uint64) uint64 {
+	v := r >> t.shift
+	if t.p.RefIn != t.p.RefOut {
+		v = reflect(v, t.p.Width)
+	}
+	return (v ^ t.p.XorOut) & t.mask
+}
+
+// register returns the register of the CRC value crc, the inverse of final.
+func (t *Table) register(crc uint64) uint64 {
+	v := (crc ^ t.p.XorOut) & t.mask
+	if t.p.RefIn != t.p.RefOut {
+		v = reflect(v, t.p.Width)
+	}
+	return v << t.shift
+}
+
+// Update returns the CRC of the data whose CRC is crc, followed by p.
+func Update(crc uint64, t *Table, p []byte) uint64 {
+	return t.final(t.update(t.register(crc), p))
+}
+
+// Checksum returns the CRC of data.
+func Checksum(data []byte, t *Table) uint64 {
+	return t.final(t.update(t.initial(), data))
+}
+
+// Combine returns the CRC of the concatenation of two strings, from the CRC
+// crc1 of the first one, and the CRC crc2 and length len2 of the second one.
+func Combine(t *Table, crc1, crc2 uint64, len2 uint64) uint64 {
+	// The register is linear: after the second string, it is the register
+	// of the second string, xored with the difference of the initial
+	// registers, followed by len2 zero bytes.
+	// Appending n zero bits multiplies by x^n modulo the polynomial.
+	d := t.normal(t.register(crc1) ^ t.initial())
+	d = mulMod(d, xPowMod(8*len2, t.p.Poly, t.p.Width), t.p.Poly, t.p.Width)
+
+	return t.final(t.fromNormal(d) ^ t.register(crc2))
+}
+
+// normal returns the register r in normal form, right-aligned.
+func (t *Table) normal(r uint64) uint64 {
+	if t.p.RefIn {
+		return reflect(r, t.p.Width)
+	}
+	return r >> t.shift
+}
+
+// fromNormal is the inverse of normal.
+func (t *Table) fromNormal(v uint64) uint64 {
+	if t.p.RefIn {
+		return reflect(v, t.p.Width)
+	}
+	return v << t.shift
+}
+
+// mulMod returns a * b modulo x^w + poly, in GF(2)[x].
+func mulMod(a, b, poly uint64, w int) uint64 {
+	var res uint64
+	top := uint64(1) << (w - 1)
+	mask := ^uint64(0) >> (64 - w)
+
+	for i := w - 1; i >= 0; i-- {
+		// res = res * x
+		res = (res<<1)&mask ^ poly&-(res&top>>(w-1))
+		if (b>>i)&1 == 1 {
+			res ^= a
+		}
+	}
+
+	return res
+}
+
+// xPowMod returns x^n modulo x^w + poly.
+func xPowMod(n uint64, poly uint64, w int) uint64 {
+	// x mod P
+	x := uint64(2)
+	res := uint64(1)
+
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			res = mulMod(res, x, poly, w)
+		}
+		x = mulMod(x, x, poly, w)
+	}
+
+	return res
+}
+
+type digest struct {
+	t *Table
+	r uint64
+}
+
+// digest32 is a digest of at most 32 bits, which also implements
+// hash.Hash32
+type digest32 struct {
+	digest
+}
+
+// New returns a new hash computing the CRC of t.
+// The digest is the CRC in big-endian, on Width / 8 bytes rounded up.
+// If the width is at most 32 bits, it also implements hash.Hash32.
+func New(t *Table) hash.Hash64 {
+	if t.p.Width <= 32 {
+		return &digest32{digest{t: t, r: t.initial()}}
+	}
+	return &digest{t: t, r: t.initial()}
+}
+
+func (d *digest) Write(p []byte) (n int, err error) {
+	d.r = d.t.update(d.r, p)
+	return len(p), nil
+}
+
+func (d *digest) Sum64() uint64 {
+	return d.t.final(d.r)
+}
+
+func (d *digest32) Sum32() uint32 {
+	return uint32(d.Sum64())
+}
+
+func (d *digest) Sum(b []byte) []byte {
+	s := d.Sum64()
+	for i := d.Size() - 1; i >= 0; i-- {
+		b = append(b, byte(s>>(8*i)))
+	}
+	return b
+}
+
+func (d *digest) Reset() {
+	d.r = d.t.initial()
+}
+
+func (d *digest) Size() int {
+	return (d.t.p.Width + 7) / 8
+}
+
+func (d *digest) BlockSize() int {
+	return 1
+}
@@ -0,0 +1,143 @@
+package crc
+
+import (
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"math/rand"
+	"testing"
+)
+
+var check = []byte("123456789")
+
+// Check values of the CRC catalogue
+var catalogue = []struct {
+	name  string
+	p     Params
+	check uint64
+}{
+	{"CRC-32/ISO-HDLC", IEEE, 0xcbf43926},
+	{"CRC-32/ISCSI", Castagnoli, 0xe3069283},
+	{"CRC-32/KOOPMAN", Koopman, 0x2d3dd0ae},
+	{"CRC-64/GO-ISO", ISO, 0xb90956c775a41001},
+	{"CRC-64/XZ", ECMA, 0x995dc9bbdf1939fa},
+	{"CRC-32/BZIP2", Params{Width: 32, Poly: 0x04c11db7, Init: 0xffffffff, XorOut: 0xffffffff}, 0xfc891918},
+	{"CRC-32/MPEG-2", Params{Width: 32, Poly: 0x04c11db7, Init: 0xffffffff}, 0x0376e6e7},
+	{"CRC-64/ECMA-182", Params{Width: 64, Poly: 0x42f0e1eba9ea3693}, 0x6c40df5f0b497347},
+	{"CRC-64/WE", Params{Width: 64, Poly: 0x42f0e1eba9ea3693, Init: 0xffffffffffffffff, XorOut: 0xffffffffffffffff}, 0x62ec59e3f1a4f00a},
+	{"CRC-16/ARC", Params{Width: 16, Poly: 0x8005, RefIn: true, RefOut: true}, 0xbb3d},
+	{"CRC-16/IBM-3740", Params{Width: 16, Poly: 0x1021, Init: 0xffff}, 0x29b1},
+	{"CRC-16/RIELLO", Params{Width: 16, Poly: 0x1021, Init: 0xb2aa, RefIn: true, RefOut: true}, 0x63d0},
+	{"CRC-8/SMBUS", Params{Width: 8, Poly: 0x07}, 0xf4},
+	{"CRC-12/UMTS", Params{Width: 12, Poly: 0x80f, RefOut: true}, 0xdaf},
+	{"CRC-24/OPENPGP", Params{Width: 24, Poly: 0x864cfb, Init: 0xb704ce}, 0x21cf02},
+	{"CRC-40/GSM", Params{Width: 40, Poly: 0x0004820009, XorOut: 0xffffffffff}, 0xd4164fc646},
+}
+
+func TestCatalogue(t *testing.T) {
+	for _, c := range catalogue {
+		table, err := MakeTable(c.p)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if res := Checksum(check, table); res != c.check {
+			t.Errorf("%s: Not equal %x!=%x", c.name, res, c.check)
+		}
+
+		// Byte by byte
+		h := New(table)
+		for i := range check {
+			h.Write(check[i : i+1])
+		}
+		if res := h.Sum64(); res != c.check {
+			t.Errorf("%s: Not equal %x!=%x", c.name, res, c.check)
+		}
+	}
+}
+
+func TestGo(t *testing.T) {
+	goTables32 := map[*Params]*crc32.Table{
+		&IEEE:       crc32.IEEETable,
+		&Castagnoli: crc32.MakeTable(crc32.Castagnoli),
+		&Koopman:    crc32.MakeTable(crc32.Koopman),
+	}
+	goTables64 := map[*Params]*crc64.Table{
+		&ISO:  crc64.MakeTable(crc64.ISO),
+		&ECMA: crc64.MakeTable(crc64.ECMA),
+	}
+
+	data := make([]byte, 1000)
+	rand.Read(data)
+
+	for l := 0; l < len(data); l += 37 {
+		for p, goTable := range goTables32 {
+			table, _ := MakeTable(*p)
+			if res, exp := Checksum(data[:l], table), uint64(crc32.Checksum(data[:l], goTable)); res != exp {
+				t.Errorf("Not equal %x!=%x", res, exp)
+			}
+		}
+		for p, goTable := range goTables64 {
+			table, _ := MakeTable(*p)
+			if res, exp := Checksum(data[:l], table), crc64.Checksum(data[:l], goTable); res != exp {
+				t.Errorf("Not equal %x!=%x", res, exp)
+			}
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	table, _ := MakeTable(IEEE)
+	h := New(table)
+	h.Write(check)
+
+	if res := h.(hash.Hash32).Sum32(); res != 0xcbf43926 {
+		t.Errorf("Not equal %x!=cbf43926", res)
+	}
+	if res := h.Sum(nil); string(res) != "\xcb\xf4\x39\x26" {
+		t.Errorf("Not equal %x!=cbf43926", res)
+	}
+
+	h.Reset()
+	h.Write(check[:4])
+	h.Write(check[4:])
+	if res := h.Sum64(); res != 0xcbf43926 {
+		t.Errorf("Not equal %x!=cbf43926", res)
+	}
+
+	// A CRC-64 would be truncated by Sum32
+	table, _ = MakeTable(ECMA)
+	if _, ok := New(table).(hash.Hash32); ok {
+		t.Error("CRC-64 implements hash.Hash32")
+	}
+}
+
+func TestUpdateCombine(t *testing.T) {
+	data := make([]byte, 300)
+	rand.Read(data)
+
+	for _, c := range catalogue {
+		table, _ := MakeTable(c.p)
+		exp := Checksum(data, table)
+
+		for _, split := range []int{0, 1, 8, 100, 299, 300} {
+			crc1 := Checksum(data[:split], table)
+			crc2 := Checksum(data[split:], table)
+
+			if res := Update(crc1, table, data[split:]); res != exp {
+				t.Errorf("%s update %d: Not equal %x!=%x", c.name, split, res, exp)
+			}
+			if res := Combine(table, crc1, crc2, uint64(len(data)-split)); res != exp {
+				t.Errorf("%s combine %d: Not equal %x!=%x", c.name, split, res, exp)
+			}
+		}
+	}
+}
+
+func TestInvalidWidth(t *testing.T) {
+	for _, w := range []int{0, 7, 65} {
+		if _, err := MakeTable(Params{Width: w, Poly: 1}); err == nil {
+			t.Errorf("width %d accepted", w)
+		}
+	}
+}
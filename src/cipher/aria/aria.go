@@ -0,0 +1,261 @@
+package aria
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"log"
+	"math/bits"
+	"strconv"
+)
+
+// RFC 5794
+
+const BlockSize int = 16
+
+// Constants of the key schedule, the fractional part of 1/pi
+var c = [3][BlockSize]byte{
+	{0x51, 0x7c, 0xc1, 0xb7, 0x27, 0x22, 0x0a, 0x94, 0xfe, 0x13, 0xab, 0xe8, 0xfa, 0x9a, 0x6e, 0xe0},
+	{0x6d, 0xb1, 0x4a, 0xcc, 0x9e, 0x21, 0xc8, 0x20, 0xff, 0x28, 0xb1, 0xd5, 0xef, 0x5d, 0xe2, 0xb0},
+	{0xdb, 0x92, 0x37, 0x1d, 0x21, 0x26, 0xe9, 0x70, 0x03, 0x24, 0x97, 0x75, 0x04, 0xe8, 0xc9, 0x0e},
+}
+
+// Output byte i of the diffusion layer A is the xor of the input bytes
+// diffusion[i]
+var diffusion = [BlockSize][7]int{
+	{3, 4, 6, 8, 9, 13, 14},
+	{2, 5, 7, 8, 9, 12, 15},
+	{1, 4, 6, 10, 11, 12, 15},
+	{0, 5, 7, 10, 11, 13, 14},
+	{0, 2, 5, 8, 11, 14, 15},
+	{1, 3, 4, 9, 10, 14, 15},
+	{0, 2, 7, 9, 10, 12, 13},
+	{1, 3, 6, 8, 11, 12, 13},
+	{0, 1, 4, 7, 10, 13, 15},
+	{0, 1, 5, 6, 11, 12, 14},
+	{2, 3, 5, 6, 8, 13, 15},
+	{2, 3, 4, 7, 9, 12, 14},
+	{1, 2, 6, 7, 9, 11, 12},
+	{0, 3, 6, 7, 8, 10, 13},
+	{0, 3, 4, 5, 9, 11, 14},
+	{1, 2, 4, 5, 8, 10, 15},
+}
+
+// The S-boxes SB1 to SB4
+var sb = makeSBoxes()
+
+type aria struct {
+	// Round keys of encryption and decryption, rounds+1 of them
+	ek, dk [17][BlockSize]byte
+	rounds int
+}
+
+// KeySizeError is returned by New when the key has an invalid size.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "aria: invalid key size " + strconv.Itoa(int(k))
+}
+
+// New creates a new ARIA cipher.
+// key is 128, 192 or 256 bits.
+func New(key []byte) (cipher.Block, error) {
+	a := new(aria)
+
+	// Order of the constants CK1, CK2, CK3
+	var ck [3]int
+	switch len(key) {
+	case 16:
+		a.rounds, ck = 12, [3]int{0, 1, 2}
+	case 24:
+		a.rounds, ck = 14, [3]int{1, 2, 0}
+	case 32:
+		a.rounds, ck = 16, [3]int{2, 0, 1}
+	default:
+		return nil, KeySizeError(len(key))
+	}
+
+	var kl, kr [BlockSize]byte
+	copy(kl[:], key)
+	copy(kr[:], key[BlockSize:])
+
+	var w [4][BlockSize]byte
+	w[0] = kl
+	w[1] = xor(fo(w[0], &c[ck[0]]), kr)
+	w[2] = xor(fe(w[1], &c[ck[1]]), w[0])
+	w[3] = xor(fo(w[2], &c[ck[2]]), w[1])
+
+	// ek_4j+i+1 = W_i xor (W_i+1 rotated by rot[j])
+	rot := [5]int{19, 31, 128 - 61, 128 - 31, 128 - 19}
+	for i := 0; i <= a.rounds; i++ {
+		a.ek[i] = xor(w[i%4], rotateRight(w[(i+1)%4], rot[i/4]))
+	}
+
+	a.dk[0] = a.ek[a.rounds]
+	for i := 1; i < a.rounds; i++ {
+		a.dk[i] = diffuse(a.ek[a.rounds-i])
+	}
+	a.dk[a.rounds] = a.ek[0]
+
+	return a, nil
+}
+
+func (a *aria) BlockSize() int {
+	return BlockSize
+}
+
+func (a *aria) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	a.crypt(dst, src, &a.ek)
+}
+
+func (a *aria) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	a.crypt(dst, src, &a.dk)
+}
+
+// crypt applies the rounds with the round keys k, encryption and
+// decryption only differ by their keys
+func (a *aria) crypt(dst, src []byte, k *[17][BlockSize]byte) {
+	var p [BlockSize]byte
+	copy(p[:], src)
+
+	for i := 0; i < a.rounds-1; i++ {
+		if i%2 == 0 {
+			p = fo(p, &k[i])
+		} else {
+			p = fe(p, &k[i])
+		}
+	}
+
+	p = xor(sl2(xor(p, k[a.rounds-1])), k[a.rounds])
+	copy(dst, p[:])
+}
+
+// fo is the odd round function
+func fo(d [BlockSize]byte, rk *[BlockSize]byte) [BlockSize]byte {
+	return diffuse(sl1(xor(d, *rk)))
+}
+
+// fe is the even round function
+func fe(d [BlockSize]byte, rk *[BlockSize]byte) [BlockSize]byte {
+	return diffuse(sl2(xor(d, *rk)))
+}
+
+// sl1 is the substitution layer of type 1
+func sl1(x [BlockSize]byte) [BlockSize]byte {
+	for i := range x {
+		x[i] = sb[i%4][x[i]]
+	}
+	return x
+}
+
+// sl2 is the substitution layer of type 2, with the inverses of the
+// S-boxes of sl1
+func sl2(x [BlockSize]byte) [BlockSize]byte {
+	for i := range x {
+		x[i] = sb[(i+2)%4][x[i]]
+	}
+	return x
+}
+
+// diffuse is the diffusion layer A, an involution
+func diffuse(x [BlockSize]byte) [BlockSize]byte {
+	var y [BlockSize]byte
+	for i, d := range diffusion {
+		for _, j := range d {
+			y[i] ^= x[j]
+		}
+	}
+	return y
+}
+
+func xor(x, y [BlockSize]byte) [BlockSize]byte {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// rotateRight rotates the 128 bits x right by n bits, 0 < n < 128
+func rotateRight(x [BlockSize]byte, n int) [BlockSize]byte {
+	hi, lo := binary.BigEndian.Uint64(x[:]), binary.BigEndian.Uint64(x[8:])
+	if n >= 64 {
+		hi, lo = lo, hi
+		n -= 64
+	}
+	if n > 0 {
+		hi, lo = hi>>n|lo<<(64-n), lo>>n|hi<<(64-n)
+	}
+
+	binary.BigEndian.PutUint64(x[:], hi)
+	binary.BigEndian.PutUint64(x[8:], lo)
+	return x
+}
+
+// makeSBoxes computes SB1 = B1 x^-1 + 0x63 (the S-box of AES) and
+// SB2 = B2 x^247 + 0xe2 in GF(2^8), and their inverses SB3 and SB4
+func makeSBoxes() [4][256]byte {
+	var sb [4][256]byte
+	for x := 0; x < 256; x++ {
+		// The columns of B1 are the rotations of 0x1f
+		y := gfPow(byte(x), 254)
+		var s1 byte = 0x63
+		for j := 0; j < 8; j++ {
+			if y>>j&1 == 1 {
+				s1 ^= bits.RotateLeft8(0x1f, j)
+			}
+		}
+
+		b2 := [8]byte{0xac, 0xc5, 0x12, 0xcf, 0x5b, 0x5f, 0x85, 0xee}
+		y = gfPow(byte(x), 247)
+		var s2 byte = 0xe2
+		for j := 0; j < 8; j++ {
+			if y>>j&1 == 1 {
+				s2 ^= b2[j]
+			}
+		}
+
+		sb[0][x], sb[1][x] = s1, s2
+		sb[2][s1], sb[3][s2] = byte(x), byte(x)
+	}
+	return sb
+}
+
+// gfPow returns x^e in GF(2^8) modulo x^8 + x^4 + x^3 + x + 1
+func gfPow(x byte, e int) byte {
+	res := byte(1)
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			res = gfMul(res, x)
+		}
+		x = gfMul(x, x)
+	}
+	return res
+}
+
+func gfMul(a, b byte) byte {
+	var res byte
+	for ; b != 0; b >>= 1 {
+		if b&1 == 1 {
+			res ^= a
+		}
+		hi := a & 0x80
+		a <<= 1
+		if hi != 0 {
+			a ^= 0x1b
+		}
+	}
+	return res
+}
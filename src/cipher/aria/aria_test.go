@@ -0,0 +1,68 @@
+package aria
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+// RFC 5794 Appendix A.1 to A.3
+func TestVectors(t *testing.T) {
+	vectors := []struct {
+		keyHex, ptHex, ctHex string
+	}{
+		{"000102030405060708090a0b0c0d0e0f", "00112233445566778899aabbccddeeff", "d718fbd6ab644c739da95f3be6451778"},
+		{"000102030405060708090a0b0c0d0e0f1011121314151617", "00112233445566778899aabbccddeeff", "26449c1805dbe7aa25a468ce263a9e79"},
+		{"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "00112233445566778899aabbccddeeff", "f92bd7c79fb72e2f2b8f80c1972d24fc"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		pt, _ := hex.DecodeString(v.ptHex)
+		c, err := New(key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, BlockSize)
+		c.Encrypt(res, pt)
+		if resHex := hex.EncodeToString(res); resHex != v.ctHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ctHex)
+		}
+
+		c.Decrypt(res, res)
+		if resHex := hex.EncodeToString(res); resHex != v.ptHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ptHex)
+		}
+	}
+}
+
+func TestSBoxes(t *testing.T) {
+	// First bytes of the tables of RFC 5794 2.4.2
+	exp := [4][4]byte{
+		{0x63, 0x7c, 0x77, 0x7b},
+		{0xe2, 0x4e, 0x54, 0xfc},
+		{0x52, 0x09, 0x6a, 0xd5},
+		{0x30, 0x68, 0x99, 0x1b},
+	}
+	for i := range exp {
+		if !bytes.Equal(sb[i][:4], exp[i][:]) {
+			t.Errorf("SB%d: Not equal %x!=%x", i+1, sb[i][:4], exp[i])
+		}
+	}
+}
+
+func TestDiffusion(t *testing.T) {
+	var x [BlockSize]byte
+	rand.Read(x[:])
+	if y := diffuse(diffuse(x)); y != x {
+		t.Errorf("A is not an involution: %x!=%x", y, x)
+	}
+}
+
+func TestKeySize(t *testing.T) {
+	if _, err := New(make([]byte, 20)); err == nil {
+		t.Error("invalid key size accepted")
+	}
+}
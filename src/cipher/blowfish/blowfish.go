@@ -0,0 +1,198 @@
+package blowfish
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"log"
+	"strconv"
+)
+
+// Blowfish, c.f. "Description of a New Variable-Length Key, 64-Bit Block
+// Cipher (Blowfish)" (Schneier 1993)
+
+const BlockSize int = 8
+
+// Limits of the key size, in bytes, accepted by New
+const (
+	MinKeySize = 1
+	MaxKeySize = 56
+)
+
+// Keys passed to NewSalted and ExpandKey may be longer, as in bcrypt
+const maxExpandKeySize = 72
+
+// Cipher is a Blowfish instance. It implements cipher.Block.
+type Cipher struct {
+	p              [18]uint32
+	s0, s1, s2, s3 [256]uint32
+}
+
+// KeySizeError is returned by the constructors when the key has an
+// invalid size.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "blowfish: invalid key size " + strconv.Itoa(int(k))
+}
+
+// ErrEmptySalt is returned by NewSalted when the salt is empty.
+var ErrEmptySalt = errors.New("blowfish: empty salt")
+
+// New creates a new Blowfish cipher.
+// key is between 1 and 56 bytes.
+func New(key []byte) (cipher.Block, error) {
+	if len(key) < MinKeySize || len(key) > MaxKeySize {
+		return nil, KeySizeError(len(key))
+	}
+
+	c := newInitial()
+	c.expandKey(key, nil)
+
+	return c, nil
+}
+
+// NewSalted creates a Blowfish cipher whose key schedule also mixes salt
+// into the encryptions of the subkeys, as the first step of the expensive
+// key schedule of bcrypt, c.f. "A Future-Adaptable Password Scheme" (Provos,
+// Mazieres 1999).
+// key is between 1 and 72 bytes, salt must not be empty.
+func NewSalted(key, salt []byte) (*Cipher, error) {
+	if len(key) < MinKeySize || len(key) > maxExpandKeySize {
+		return nil, KeySizeError(len(key))
+	}
+	if len(salt) == 0 {
+		return nil, ErrEmptySalt
+	}
+
+	c := newInitial()
+	c.expandKey(key, salt)
+
+	return c, nil
+}
+
+// ExpandKey mixes key into the current state of c, with the standard
+// Blowfish key schedule.
+// It is the repeated step of the expensive key schedule of bcrypt.
+// key is between 1 and 72 bytes.
+func (c *Cipher) ExpandKey(key []byte) {
+	if len(key) < MinKeySize || len(key) > maxExpandKeySize {
+		log.Panic(KeySizeError(len(key)).Error())
+	}
+
+	c.expandKey(key, nil)
+}
+
+// newInitial returns a Blowfish state with the initial P-array and S-boxes
+func newInitial() *Cipher {
+	return &Cipher{
+		p:  initP,
+		s0: initS0,
+		s1: initS1,
+		s2: initS2,
+		s3: initS3,
+	}
+}
+
+// cycle reads the next 32-bit word from b, cycling through b.
+// pos is updated.
+func cycle(b []byte, pos *int) uint32 {
+	var w uint32
+	for i := 0; i < 4; i++ {
+		w = w<<8 | uint32(b[*pos])
+		*pos = (*pos + 1) % len(b)
+	}
+	return w
+}
+
+// expandKey mixes key into the state, and salt into the encryptions of the
+// subkeys.
+// With a nil salt, it is the standard Blowfish key schedule.
+func (c *Cipher) expandKey(key, salt []byte) {
+	j := 0
+	for i := range c.p {
+		c.p[i] ^= cycle(key, &j)
+	}
+
+	var l, r uint32
+	k := 0
+	next := func(dst []uint32) {
+		for i := 0; i < len(dst); i += 2 {
+			if salt != nil {
+				l ^= cycle(salt, &k)
+				r ^= cycle(salt, &k)
+			}
+			l, r = c.encrypt(l, r)
+			dst[i], dst[i+1] = l, r
+		}
+	}
+
+	next(c.p[:])
+	next(c.s0[:])
+	next(c.s1[:])
+	next(c.s2[:])
+	next(c.s3[:])
+}
+
+// f is the Feistel function
+func (c *Cipher) f(x uint32) uint32 {
+	return ((c.s0[x>>24] + c.s1[(x>>16)&0xff]) ^ c.s2[(x>>8)&0xff]) + c.s3[x&0xff]
+}
+
+// encrypt encrypts the block (l, r)
+func (c *Cipher) encrypt(l, r uint32) (uint32, uint32) {
+	for i := 0; i < 16; i += 2 {
+		l ^= c.p[i]
+		r ^= c.f(l)
+		r ^= c.p[i+1]
+		l ^= c.f(r)
+	}
+	l ^= c.p[16]
+	r ^= c.p[17]
+
+	return r, l
+}
+
+// decrypt decrypts the block (l, r), using the P-array in reverse order
+func (c *Cipher) decrypt(l, r uint32) (uint32, uint32) {
+	for i := 17; i > 1; i -= 2 {
+		l ^= c.p[i]
+		r ^= c.f(l)
+		r ^= c.p[i-1]
+		l ^= c.f(r)
+	}
+	l ^= c.p[1]
+	r ^= c.p[0]
+
+	return r, l
+}
+
+func (c *Cipher) BlockSize() int {
+	return BlockSize
+}
+
+func (c *Cipher) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	l, r := c.encrypt(binary.BigEndian.Uint32(src), binary.BigEndian.Uint32(src[4:]))
+	binary.BigEndian.PutUint32(dst, l)
+	binary.BigEndian.PutUint32(dst[4:], r)
+}
+
+func (c *Cipher) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	l, r := c.decrypt(binary.BigEndian.Uint32(src), binary.BigEndian.Uint32(src[4:]))
+	binary.BigEndian.PutUint32(dst, l)
+	binary.BigEndian.PutUint32(dst[4:], r)
+}
@@ -0,0 +1,271 @@
+package camellia
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"log"
+	"math/bits"
+	"strconv"
+)
+
+// RFC 3713
+
+const BlockSize int = 16
+
+var sigma = [6]uint64{
+	0xa09e667f3bcc908b,
+	0xb67ae8584caa73b2,
+	0xc6ef372fe94f82be,
+	0x54ff53a5f1d36f1c,
+	0x10e527fade682d1d,
+	0xb05688c2b3e6c1fd,
+}
+
+var sbox1 = [256]uint8{
+	112, 130, 44, 236, 179, 39, 192, 229, 228, 133, 87, 53, 234, 12, 174, 65,
+	35, 239, 107, 147, 69, 25, 165, 33, 237, 14, 79, 78, 29, 101, 146, 189,
+	134, 184, 175, 143, 124, 235, 31, 206, 62, 48, 220, 95, 94, 197, 11, 26,
+	166, 225, 57, 202, 213, 71, 93, 61, 217, 1, 90, 214, 81, 86, 108, 77,
+	139, 13, 154, 102, 251, 204, 176, 45, 116, 18, 43, 32, 240, 177, 132, 153,
+	223, 76, 203, 194, 52, 126, 118, 5, 109, 183, 169, 49, 209, 23, 4, 215,
+	20, 88, 58, 97, 222, 27, 17, 28, 50, 15, 156, 22, 83, 24, 242, 34,
+	254, 68, 207, 178, 195, 181, 122, 145, 36, 8, 232, 168, 96, 252, 105, 80,
+	170, 208, 160, 125, 161, 137, 98, 151, 84, 91, 30, 149, 224, 255, 100, 210,
+	16, 196, 0, 72, 163, 247, 117, 219, 138, 3, 230, 218, 9, 63, 221, 148,
+	135, 92, 131, 2, 205, 74, 144, 51, 115, 103, 246, 243, 157, 127, 191, 226,
+	82, 155, 216, 38, 200, 55, 198, 59, 129, 150, 111, 75, 19, 190, 99, 46,
+	233, 121, 167, 140, 159, 110, 188, 142, 41, 245, 249, 182, 47, 253, 180, 89,
+	120, 152, 6, 106, 231, 70, 113, 186, 212, 37, 171, 66, 136, 162, 141, 250,
+	114, 7, 185, 85, 248, 238, 172, 10, 54, 73, 42, 104, 60, 56, 241, 164,
+	64, 40, 211, 123, 187, 201, 67, 193, 21, 227, 173, 244, 119, 199, 128, 158,
+}
+
+// sp combines the S-boxes with the P-function: sp[i][b] is the output of
+// F for the byte b at position i (from the most significant byte) and
+// zeros elsewhere.
+var sp = makeSP()
+
+type camellia struct {
+	kw [4]uint64
+	k  [24]uint64
+	ke [6]uint64
+	// 18 rounds for 128-bit keys, 24 otherwise
+	rounds int
+}
+
+// KeySizeError is returned by New when the key has an invalid size.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "camellia: invalid key size " + strconv.Itoa(int(k))
+}
+
+// New creates a new Camellia cipher.
+// key is 128, 192 or 256 bits.
+func New(key []byte) (cipher.Block, error) {
+	var kl, kr [2]uint64
+	switch len(key) {
+	case 16:
+		kl[0], kl[1] = binary.BigEndian.Uint64(key), binary.BigEndian.Uint64(key[8:])
+	case 24:
+		kl[0], kl[1] = binary.BigEndian.Uint64(key), binary.BigEndian.Uint64(key[8:])
+		kr[0] = binary.BigEndian.Uint64(key[16:])
+		kr[1] = ^kr[0]
+	case 32:
+		kl[0], kl[1] = binary.BigEndian.Uint64(key), binary.BigEndian.Uint64(key[8:])
+		kr[0], kr[1] = binary.BigEndian.Uint64(key[16:]), binary.BigEndian.Uint64(key[24:])
+	default:
+		return nil, KeySizeError(len(key))
+	}
+
+	// KA and KB
+	d1, d2 := kl[0]^kr[0], kl[1]^kr[1]
+	d2 ^= f(d1, sigma[0])
+	d1 ^= f(d2, sigma[1])
+	d1 ^= kl[0]
+	d2 ^= kl[1]
+	d2 ^= f(d1, sigma[2])
+	d1 ^= f(d2, sigma[3])
+	ka := [2]uint64{d1, d2}
+
+	d1, d2 = ka[0]^kr[0], ka[1]^kr[1]
+	d2 ^= f(d1, sigma[4])
+	d1 ^= f(d2, sigma[5])
+	kb := [2]uint64{d1, d2}
+
+	c := new(camellia)
+
+	if len(key) == 16 {
+		c.rounds = 18
+		c.kw[0], c.kw[1] = rotate(kl, 0)
+		c.k[0], c.k[1] = rotate(ka, 0)
+		c.k[2], c.k[3] = rotate(kl, 15)
+		c.k[4], c.k[5] = rotate(ka, 15)
+		c.ke[0], c.ke[1] = rotate(ka, 30)
+		c.k[6], c.k[7] = rotate(kl, 45)
+		c.k[8], _ = rotate(ka, 45)
+		_, c.k[9] = rotate(kl, 60)
+		c.k[10], c.k[11] = rotate(ka, 60)
+		c.ke[2], c.ke[3] = rotate(kl, 77)
+		c.k[12], c.k[13] = rotate(kl, 94)
+		c.k[14], c.k[15] = rotate(ka, 94)
+		c.k[16], c.k[17] = rotate(kl, 111)
+		c.kw[2], c.kw[3] = rotate(ka, 111)
+	} else {
+		c.rounds = 24
+		c.kw[0], c.kw[1] = rotate(kl, 0)
+		c.k[0], c.k[1] = rotate(kb, 0)
+		c.k[2], c.k[3] = rotate(kr, 15)
+		c.k[4], c.k[5] = rotate(ka, 15)
+		c.ke[0], c.ke[1] = rotate(kr, 30)
+		c.k[6], c.k[7] = rotate(kb, 30)
+		c.k[8], c.k[9] = rotate(kl, 45)
+		c.k[10], c.k[11] = rotate(ka, 45)
+		c.ke[2], c.ke[3] = rotate(kl, 60)
+		c.k[12], c.k[13] = rotate(kr, 60)
+		c.k[14], c.k[15] = rotate(kb, 60)
+		c.k[16], c.k[17] = rotate(kl, 77)
+		c.ke[4], c.ke[5] = rotate(ka, 77)
+		c.k[18], c.k[19] = rotate(kr, 94)
+		c.k[20], c.k[21] = rotate(ka, 94)
+		c.k[22], c.k[23] = rotate(kl, 111)
+		c.kw[2], c.kw[3] = rotate(kb, 111)
+	}
+
+	return c, nil
+}
+
+func (c *camellia) BlockSize() int {
+	return BlockSize
+}
+
+func (c *camellia) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	c.crypt(dst, src, false)
+}
+
+func (c *camellia) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	c.crypt(dst, src, true)
+}
+
+// crypt applies the Feistel network, with the subkeys in reverse order
+// to decrypt
+func (c *camellia) crypt(dst, src []byte, decrypt bool) {
+	kw1, kw2, kw3, kw4 := c.kw[0], c.kw[1], c.kw[2], c.kw[3]
+	if decrypt {
+		kw1, kw2, kw3, kw4 = kw3, kw4, kw1, kw2
+	}
+	k := func(i int) uint64 {
+		if decrypt {
+			return c.k[c.rounds-1-i]
+		}
+		return c.k[i]
+	}
+	// 4 FL subkeys for 18 rounds, 6 for 24 rounds
+	nke := c.rounds/3 - 2
+	ke := func(i int) uint64 {
+		if decrypt {
+			return c.ke[nke-1-i]
+		}
+		return c.ke[i]
+	}
+
+	d1 := binary.BigEndian.Uint64(src) ^ kw1
+	d2 := binary.BigEndian.Uint64(src[8:]) ^ kw2
+
+	for i := 0; i < c.rounds; i += 2 {
+		// FL and FL^-1 layer every 6 rounds
+		if i > 0 && i%6 == 0 {
+			d1 = fl(d1, ke(i/3-2))
+			d2 = flInv(d2, ke(i/3-1))
+		}
+
+		d2 ^= f(d1, k(i))
+		d1 ^= f(d2, k(i+1))
+	}
+
+	binary.BigEndian.PutUint64(dst, d2^kw3)
+	binary.BigEndian.PutUint64(dst[8:], d1^kw4)
+}
+
+// f is the F-function
+func f(x, k uint64) uint64 {
+	x ^= k
+	return sp[0][x>>56] ^ sp[1][x>>48&0xff] ^ sp[2][x>>40&0xff] ^ sp[3][x>>32&0xff] ^
+		sp[4][x>>24&0xff] ^ sp[5][x>>16&0xff] ^ sp[6][x>>8&0xff] ^ sp[7][x&0xff]
+}
+
+func fl(x, k uint64) uint64 {
+	x1, x2 := uint32(x>>32), uint32(x)
+	k1, k2 := uint32(k>>32), uint32(k)
+	x2 ^= bits.RotateLeft32(x1&k1, 1)
+	x1 ^= x2 | k2
+	return uint64(x1)<<32 | uint64(x2)
+}
+
+func flInv(y, k uint64) uint64 {
+	y1, y2 := uint32(y>>32), uint32(y)
+	k1, k2 := uint32(k>>32), uint32(k)
+	y1 ^= y2 | k2
+	y2 ^= bits.RotateLeft32(y1&k1, 1)
+	return uint64(y1)<<32 | uint64(y2)
+}
+
+// rotate returns the two halves of the 128 bits x rotated left by n bits
+func rotate(x [2]uint64, n uint) (uint64, uint64) {
+	if n >= 64 {
+		x[0], x[1] = x[1], x[0]
+		n -= 64
+	}
+	if n == 0 {
+		return x[0], x[1]
+	}
+	return x[0]<<n | x[1]>>(64-n), x[1]<<n | x[0]>>(64-n)
+}
+
+func makeSP() [8][256]uint64 {
+	var sbox2, sbox3, sbox4 [256]uint8
+	for x := 0; x < 256; x++ {
+		sbox2[x] = bits.RotateLeft8(sbox1[x], 1)
+		sbox3[x] = bits.RotateLeft8(sbox1[x], -1)
+		sbox4[x] = sbox1[bits.RotateLeft8(uint8(x), 1)]
+	}
+	s := [8]*[256]uint8{&sbox1, &sbox2, &sbox3, &sbox4, &sbox2, &sbox3, &sbox4, &sbox1}
+
+	// Byte i of the input of P appears in the output bytes p[i], from the
+	// first one
+	p := [8][]int{
+		{0, 1, 2, 4, 7},
+		{1, 2, 3, 4, 5},
+		{0, 2, 3, 5, 6},
+		{0, 1, 3, 6, 7},
+		{1, 2, 3, 5, 6, 7},
+		{0, 2, 3, 4, 6, 7},
+		{0, 1, 3, 4, 5, 7},
+		{0, 1, 2, 4, 5, 6},
+	}
+
+	var res [8][256]uint64
+	for i := range res {
+		for x := 0; x < 256; x++ {
+			t := uint64(s[i][x])
+			for _, j := range p[i] {
+				res[i][x] |= t << (56 - 8*j)
+			}
+		}
+	}
+	return res
+}
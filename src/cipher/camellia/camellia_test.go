@@ -0,0 +1,75 @@
+package camellia
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+func TestVectors(t *testing.T) {
+	vectors := []struct {
+		keyHex, ptHex, ctHex string
+	}{
+		// RFC 3713 Appendix A
+		{"0123456789abcdeffedcba9876543210", "0123456789abcdeffedcba9876543210", "67673138549669730857065648eabe43"},
+		{"0123456789abcdeffedcba98765432100011223344556677", "0123456789abcdeffedcba9876543210", "b4993401b3e996f84ee5cee7d79b09b9"},
+		{"0123456789abcdeffedcba987654321000112233445566778899aabbccddeeff", "0123456789abcdeffedcba9876543210", "9acc237dff16d76c20ef7c919e3a7509"},
+		// NESSIE set 1 vector 0 and set 2 vector 0
+		{"80000000000000000000000000000000", "00000000000000000000000000000000", "6c227f749319a3aa7da235a9bba05a2c"},
+		{"00000000000000000000000000000000", "80000000000000000000000000000000", "07923a39eb0a817d1c4d87bdb82d1f1c"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		pt, _ := hex.DecodeString(v.ptHex)
+		c, err := New(key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, BlockSize)
+		c.Encrypt(res, pt)
+		if resHex := hex.EncodeToString(res); resHex != v.ctHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ctHex)
+		}
+
+		c.Decrypt(res, res)
+		if resHex := hex.EncodeToString(res); resHex != v.ptHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ptHex)
+		}
+	}
+}
+
+func TestSBox(t *testing.T) {
+	var seen [256]bool
+	for _, b := range sbox1 {
+		if seen[b] {
+			t.Fatalf("SBOX1 is not a permutation, %d repeated", b)
+		}
+		seen[b] = true
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	for _, keyLen := range []int{16, 24, 32} {
+		key := make([]byte, keyLen)
+		rand.Read(key)
+		m := make([]byte, BlockSize)
+		rand.Read(m)
+
+		c, _ := New(key)
+		res := make([]byte, BlockSize)
+		c.Encrypt(res, m)
+		c.Decrypt(res, res)
+		if !bytes.Equal(res, m) {
+			t.Errorf("%d: Not equal %x!=%x", keyLen, res, m)
+		}
+	}
+}
+
+func TestKeySize(t *testing.T) {
+	if _, err := New(make([]byte, 20)); err == nil {
+		t.Error("invalid key size accepted")
+	}
+}
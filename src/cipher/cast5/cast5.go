@@ -0,0 +1,175 @@
+package cast5
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"log"
+	"math/bits"
+	"strconv"
+)
+
+// RFC 2144
+
+const BlockSize int = 8
+
+// Limits of the key size, in bytes. Shorter keys are padded with zeros.
+const (
+	MinKeySize = 5
+	MaxKeySize = 16
+)
+
+// Keys of at most 80 bits use 12 rounds
+const shortKeySize = 10
+
+// Indices, in the 16 bytes of x or z, of the bytes given to S5 to S8 and
+// to the fifth S-box for each subkey, c.f. 2.4
+var keyIndices = [4][4][5]int{
+	// K1 to K4, from z
+	{{0x8, 0x9, 0x7, 0x6, 0x2}, {0xa, 0xb, 0x5, 0x4, 0x6}, {0xc, 0xd, 0x3, 0x2, 0x9}, {0xe, 0xf, 0x1, 0x0, 0xc}},
+	// K5 to K8, from x
+	{{0x3, 0x2, 0xc, 0xd, 0x8}, {0x1, 0x0, 0xe, 0xf, 0xd}, {0x7, 0x6, 0x8, 0x9, 0x3}, {0x5, 0x4, 0xa, 0xb, 0x7}},
+	// K9 to K12, from z
+	{{0x3, 0x2, 0xc, 0xd, 0x9}, {0x1, 0x0, 0xe, 0xf, 0xc}, {0x7, 0x6, 0x8, 0x9, 0x2}, {0x5, 0x4, 0xa, 0xb, 0x6}},
+	// K13 to K16, from x
+	{{0x8, 0x9, 0x7, 0x6, 0x3}, {0xa, 0xb, 0x5, 0x4, 0x7}, {0xc, 0xd, 0x3, 0x2, 0x8}, {0xe, 0xf, 0x1, 0x0, 0xd}},
+}
+
+type cast5 struct {
+	// Masking and rotation subkeys
+	km     [16]uint32
+	kr     [16]uint8
+	rounds int
+}
+
+// KeySizeError is returned by New when the key has an invalid size.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "cast5: invalid key size " + strconv.Itoa(int(k))
+}
+
+// New creates a new CAST5 (CAST-128) cipher.
+// key is between 40 and 128 bits.
+func New(key []byte) (cipher.Block, error) {
+	if len(key) < MinKeySize || len(key) > MaxKeySize {
+		return nil, KeySizeError(len(key))
+	}
+
+	c := &cast5{rounds: 16}
+	if len(key) <= shortKeySize {
+		c.rounds = 12
+	}
+
+	var x, z [16]byte
+	copy(x[:], key)
+
+	// The subkeys are computed 4 at a time, alternately from z computed from
+	// x and from x computed from z
+	var k [32]uint32
+	for step := 0; step < 8; step++ {
+		src := &x
+		if step%2 == 0 {
+			z = xToZ(&x)
+			src = &z
+		} else {
+			x = zToX(&z)
+		}
+
+		for i, idx := range keyIndices[step%4] {
+			k[4*step+i] = sbox[4][src[idx[0]]] ^ sbox[5][src[idx[1]]] ^
+				sbox[6][src[idx[2]]] ^ sbox[7][src[idx[3]]] ^ sbox[4+i][src[idx[4]]]
+		}
+	}
+
+	copy(c.km[:], k[:16])
+	for i := range c.kr {
+		c.kr[i] = uint8(k[16+i] & 0x1f)
+	}
+
+	return c, nil
+}
+
+func (c *cast5) BlockSize() int {
+	return BlockSize
+}
+
+func (c *cast5) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	l, r := binary.BigEndian.Uint32(src), binary.BigEndian.Uint32(src[4:])
+	for i := 0; i < c.rounds; i++ {
+		l, r = r, l^c.f(r, i)
+	}
+
+	binary.BigEndian.PutUint32(dst, r)
+	binary.BigEndian.PutUint32(dst[4:], l)
+}
+
+func (c *cast5) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	l, r := binary.BigEndian.Uint32(src), binary.BigEndian.Uint32(src[4:])
+	for i := c.rounds - 1; i >= 0; i-- {
+		l, r = r, l^c.f(r, i)
+	}
+
+	binary.BigEndian.PutUint32(dst, r)
+	binary.BigEndian.PutUint32(dst[4:], l)
+}
+
+// f is the round function of round i, of type 1, 2 or 3 depending on i
+func (c *cast5) f(d uint32, i int) uint32 {
+	km, kr := c.km[i], int(c.kr[i])
+
+	switch i % 3 {
+	case 0:
+		x := bits.RotateLeft32(km+d, kr)
+		return ((sbox[0][x>>24] ^ sbox[1][x>>16&0xff]) - sbox[2][x>>8&0xff]) + sbox[3][x&0xff]
+	case 1:
+		x := bits.RotateLeft32(km^d, kr)
+		return ((sbox[0][x>>24] - sbox[1][x>>16&0xff]) + sbox[2][x>>8&0xff]) ^ sbox[3][x&0xff]
+	default:
+		x := bits.RotateLeft32(km-d, kr)
+		return ((sbox[0][x>>24] + sbox[1][x>>16&0xff]) ^ sbox[2][x>>8&0xff]) - sbox[3][x&0xff]
+	}
+}
+
+// xToZ computes z0..zF from x0..xF
+func xToZ(x *[16]byte) [16]byte {
+	var z [16]byte
+	put := func(i int, w uint32) {
+		binary.BigEndian.PutUint32(z[i:], w)
+	}
+
+	put(0x0, binary.BigEndian.Uint32(x[0x0:])^sbox[4][x[0xd]]^sbox[5][x[0xf]]^sbox[6][x[0xc]]^sbox[7][x[0xe]]^sbox[6][x[0x8]])
+	put(0x4, binary.BigEndian.Uint32(x[0x8:])^sbox[4][z[0x0]]^sbox[5][z[0x2]]^sbox[6][z[0x1]]^sbox[7][z[0x3]]^sbox[7][x[0xa]])
+	put(0x8, binary.BigEndian.Uint32(x[0xc:])^sbox[4][z[0x7]]^sbox[5][z[0x6]]^sbox[6][z[0x5]]^sbox[7][z[0x4]]^sbox[4][x[0x9]])
+	put(0xc, binary.BigEndian.Uint32(x[0x4:])^sbox[4][z[0xa]]^sbox[5][z[0x9]]^sbox[6][z[0xb]]^sbox[7][z[0x8]]^sbox[5][x[0xb]])
+
+	return z
+}
+
+// zToX computes x0..xF from z0..zF
+func zToX(z *[16]byte) [16]byte {
+	var x [16]byte
+	put := func(i int, w uint32) {
+		binary.BigEndian.PutUint32(x[i:], w)
+	}
+
+	put(0x0, binary.BigEndian.Uint32(z[0x8:])^sbox[4][z[0x5]]^sbox[5][z[0x7]]^sbox[6][z[0x4]]^sbox[7][z[0x6]]^sbox[6][z[0x0]])
+	put(0x4, binary.BigEndian.Uint32(z[0x0:])^sbox[4][x[0x0]]^sbox[5][x[0x2]]^sbox[6][x[0x1]]^sbox[7][x[0x3]]^sbox[7][z[0x2]])
+	put(0x8, binary.BigEndian.Uint32(z[0x4:])^sbox[4][x[0x7]]^sbox[5][x[0x6]]^sbox[6][x[0x5]]^sbox[7][x[0x4]]^sbox[4][z[0x1]])
+	put(0xc, binary.BigEndian.Uint32(z[0xc:])^sbox[4][x[0xa]]^sbox[5][x[0x9]]^sbox[6][x[0xb]]^sbox[7][x[0x8]]^sbox[5][z[0x3]])
+
+	return x
+}
@@ -0,0 +1,68 @@
+package cast5
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// RFC 2144 Appendix B.1
+func TestVectors(t *testing.T) {
+	vectors := []struct {
+		keyHex, ptHex, ctHex string
+	}{
+		{"0123456712345678234567893456789a", "0123456789abcdef", "238b4fe5847e44b2"},
+		{"01234567123456782345", "0123456789abcdef", "eb6a711a2c02271b"},
+		{"0123456712", "0123456789abcdef", "7ac816d16e9b302e"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		pt, _ := hex.DecodeString(v.ptHex)
+		c, err := New(key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, BlockSize)
+		c.Encrypt(res, pt)
+		if resHex := hex.EncodeToString(res); resHex != v.ctHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ctHex)
+		}
+
+		c.Decrypt(res, res)
+		if resHex := hex.EncodeToString(res); resHex != v.ptHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ptHex)
+		}
+	}
+}
+
+// RFC 2144 Appendix B.2, one million encryptions of a and b
+func TestMaintenance(t *testing.T) {
+	a, _ := hex.DecodeString("0123456712345678234567893456789a")
+	b, _ := hex.DecodeString("0123456712345678234567893456789a")
+
+	for i := 0; i < 1000000; i++ {
+		c, _ := New(b)
+		c.Encrypt(a[:8], a[:8])
+		c.Encrypt(a[8:], a[8:])
+		c, _ = New(a)
+		c.Encrypt(b[:8], b[:8])
+		c.Encrypt(b[8:], b[8:])
+	}
+
+	if resHex, exp := hex.EncodeToString(a), "eea9d0a249fd3ba6b3436fb89d6dca92"; resHex != exp {
+		t.Errorf("a: Not equal %s!=%s", resHex, exp)
+	}
+	if resHex, exp := hex.EncodeToString(b), "b2c95eb00c31ad7180ac05b8e83d696e"; resHex != exp {
+		t.Errorf("b: Not equal %s!=%s", resHex, exp)
+	}
+}
+
+func TestKeySize(t *testing.T) {
+	if _, err := New(make([]byte, 4)); err == nil {
+		t.Error("short key accepted")
+	}
+	if _, err := New(make([]byte, 17)); err == nil {
+		t.Error("long key accepted")
+	}
+}
@@ -0,0 +1,203 @@
+package des
+
+import (
+	"encoding/binary"
+	"errors"
+	"log"
+)
+
+// Bitsliced DES, c.f. "A Fast New DES Implementation in Software" (Biham
+// 1997).
+//
+// 64 blocks are processed at once: the state is made of 64 slices, slice j
+// holding bit j (from 0, starting with the first bit) of every block, block
+// i being at bit 63-i of the slice. Permutations become free renamings of
+// the slices and the S-boxes are evaluated as gate circuits in
+// bitslice_sbox.go, c.f. "Reducing the Gate Count of Bitslice DES" (Kwan
+// 2000).
+
+// Lanes is the number of blocks processed at once by the bitsliced DES.
+const Lanes int = 64
+
+// Slices is a bitsliced state of 64 blocks or keys, as produced by
+// Transpose.
+type Slices [64]uint64
+
+var bitslicedSBoxes = [8]func(a1, a2, a3, a4, a5, a6 uint64) (o1, o2, o3, o4 uint64){
+	sbox1, sbox2, sbox3, sbox4, sbox5, sbox6, sbox7, sbox8,
+}
+
+// ksIndex[i][j] is the bit of the 64 bits key used as bit j of the subkey
+// of round i
+var ksIndex = makeKSIndex()
+
+func makeKSIndex() [16][48]uint8 {
+	var res [16][48]uint8
+
+	// The key schedule only moves bits around, so follow each key bit
+	for b := 0; b < 64; b++ {
+		keys := ks(1 << (63 - b))
+		for i, k := range keys {
+			for j := 0; j < 48; j++ {
+				if k>>(47-j)&1 == 1 {
+					res[i][j] = uint8(b)
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// Bitsliced is a DES cipher processing 64 blocks at once, each block lane
+// having its own key.
+type Bitsliced struct {
+	keys Slices
+}
+
+// NewBitsliced creates a bitsliced DES with the same 64 bits key in every
+// lane.
+func NewBitsliced(key []byte) (*Bitsliced, error) {
+	if len(key) != 8 {
+		return nil, KeySizeError(len(key))
+	}
+
+	b := new(Bitsliced)
+	k := binary.BigEndian.Uint64(key)
+	for j := range b.keys {
+		// All ones if bit j is set
+		b.keys[j] = -(k >> (63 - j) & 1)
+	}
+
+	return b, nil
+}
+
+// NewBitslicedKeys creates a bitsliced DES where lane i uses keys[i].
+// There can be up to 64 keys, the remaining lanes use the zero key.
+func NewBitslicedKeys(keys [][]byte) (*Bitsliced, error) {
+	if len(keys) == 0 || len(keys) > Lanes {
+		return nil, errors.New("des: between 1 and 64 keys are needed")
+	}
+
+	b := new(Bitsliced)
+	for i, key := range keys {
+		if len(key) != 8 {
+			return nil, KeySizeError(len(key))
+		}
+		b.keys[i] = binary.BigEndian.Uint64(key)
+	}
+	Transpose(&b.keys)
+
+	return b, nil
+}
+
+// Transpose converts between 64 blocks as big-endian words and their
+// bitsliced form, it is its own inverse.
+// c.f. Hacker's Delight 7-3
+func Transpose(s *Slices) {
+	m := uint64(0x00000000ffffffff)
+	for j := 32; j != 0; j, m = j>>1, m^(m<<(j>>1)) {
+		for k := 0; k < 64; k = (k + j + 1) &^ j {
+			t := (s[k] ^ (s[k+j] >> j)) & m
+			s[k] ^= t
+			s[k+j] ^= t << j
+		}
+	}
+}
+
+// EncryptSlices encrypts the 64 bitsliced blocks of s in place.
+func (b *Bitsliced) EncryptSlices(s *Slices) {
+	b.crypt(s, false)
+}
+
+// DecryptSlices decrypts the 64 bitsliced blocks of s in place.
+func (b *Bitsliced) DecryptSlices(s *Slices) {
+	b.crypt(s, true)
+}
+
+// EncryptBlocks encrypts the blocks of src into dst, by batches of 64
+// blocks: block i uses the key of lane i mod 64.
+// The length of src must be a multiple of the block size.
+func (b *Bitsliced) EncryptBlocks(dst, src []byte) {
+	b.cryptBlocks(dst, src, false)
+}
+
+// DecryptBlocks decrypts the blocks of src into dst, by batches of 64
+// blocks: block i uses the key of lane i mod 64.
+// The length of src must be a multiple of the block size.
+func (b *Bitsliced) DecryptBlocks(dst, src []byte) {
+	b.cryptBlocks(dst, src, true)
+}
+
+func (b *Bitsliced) cryptBlocks(dst, src []byte, decrypt bool) {
+	if len(src)%BlockSize != 0 {
+		log.Panic("des: input not full blocks")
+	}
+	if len(dst) < len(src) {
+		log.Panic("des: output smaller than input")
+	}
+
+	for len(src) > 0 {
+		var s Slices
+		n := len(src) / BlockSize
+		if n > Lanes {
+			n = Lanes
+		}
+
+		for i := 0; i < n; i++ {
+			s[i] = binary.BigEndian.Uint64(src[BlockSize*i:])
+		}
+		Transpose(&s)
+		b.crypt(&s, decrypt)
+		Transpose(&s)
+		for i := 0; i < n; i++ {
+			binary.BigEndian.PutUint64(dst[BlockSize*i:], s[i])
+		}
+
+		src = src[BlockSize*n:]
+		dst = dst[BlockSize*n:]
+	}
+}
+
+// crypt applies DES to the bitsliced blocks
+func (b *Bitsliced) crypt(s *Slices, decrypt bool) {
+	// IP
+	var l, r [32]uint64
+	for i := range l {
+		l[i] = s[ip[i]-1]
+		r[i] = s[ip[32+i]-1]
+	}
+
+	for round := 0; round < 16; round++ {
+		k := &ksIndex[round]
+		if decrypt {
+			k = &ksIndex[15-round]
+		}
+
+		// Output of the S-boxes, before P
+		var out [32]uint64
+		for n, sbox := range bitslicedSBoxes {
+			// E and subkey addition
+			var a [6]uint64
+			for j := range a {
+				a[j] = r[eTable[6*n+j]-1] ^ b.keys[k[6*n+j]]
+			}
+
+			out[4*n], out[4*n+1], out[4*n+2], out[4*n+3] = sbox(a[0], a[1], a[2], a[3], a[4], a[5])
+		}
+
+		// P and Feistel
+		for i := range l {
+			l[i] ^= out[p[i]-1]
+		}
+		l, r = r, l
+	}
+
+	// IP^-1 of R16 || L16
+	var pre Slices
+	copy(pre[:32], r[:])
+	copy(pre[32:], l[:])
+	for i := range s {
+		s[i] = pre[ipInv[i]-1]
+	}
+}
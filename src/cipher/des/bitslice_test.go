@@ -0,0 +1,200 @@
+package des
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+func TestTranspose(t *testing.T) {
+	var s, orig Slices
+	for i := range s {
+		var b [8]byte
+		rand.Read(b[:])
+		s[i] = uint64(b[0])<<56 | uint64(b[3])<<32 | uint64(b[7])
+	}
+	orig = s
+
+	Transpose(&s)
+	for i := range s {
+		for j := range s {
+			if s[j]>>(63-i)&1 != orig[i]>>(63-j)&1 {
+				t.Fatalf("bit %d of block %d misplaced", j, i)
+			}
+		}
+	}
+
+	Transpose(&s)
+	if s != orig {
+		t.Error("Transpose is not an involution")
+	}
+}
+
+// The circuits must match the S-box tables on all 64 inputs
+func TestSBoxCircuits(t *testing.T) {
+	// Lane b evaluates the S-boxes on the 6 bits b
+	var a [6]uint64
+	for b := 0; b < 64; b++ {
+		for k := range a {
+			a[k] |= uint64(b>>(5-k)&1) << (63 - b)
+		}
+	}
+
+	for n, s := range bitslicedSBoxes {
+		var o [4]uint64
+		o[0], o[1], o[2], o[3] = s(a[0], a[1], a[2], a[3], a[4], a[5])
+
+		for b := 0; b < 64; b++ {
+			bi, bj := getIj(uint8(b))
+			exp := getSij(bi, bj, sTables[n])
+
+			var res uint8
+			for k := range o {
+				res = res<<1 | uint8(o[k]>>(63-b)&1)
+			}
+
+			if res != exp {
+				t.Errorf("S%d(%06b) %d != %d", n+1, b, res, exp)
+			}
+		}
+	}
+}
+
+func TestBitslicedVector(t *testing.T) {
+	// Same vector as TestEncrypt, in every lane
+	key, _ := hex.DecodeString("133457799bbcdff1")
+	pt := bytes.Repeat([]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}, Lanes)
+	exp := bytes.Repeat([]byte{0x85, 0xe8, 0x13, 0x54, 0x0f, 0x0a, 0xb4, 0x05}, Lanes)
+
+	b, err := NewBitsliced(key)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ct := make([]byte, len(pt))
+	b.EncryptBlocks(ct, pt)
+	if !bytes.Equal(ct, exp) {
+		t.Errorf("Not equal %x!=%x", ct[:8], exp[:8])
+	}
+
+	b.DecryptBlocks(ct, ct)
+	if !bytes.Equal(ct, pt) {
+		t.Errorf("Not equal %x!=%x", ct[:8], pt[:8])
+	}
+}
+
+func TestBitslicedOneKey(t *testing.T) {
+	key := make([]byte, 8)
+	rand.Read(key)
+	// More than one batch, the last one being partial
+	src := make([]byte, 100*BlockSize)
+	rand.Read(src)
+
+	b, _ := NewBitsliced(key)
+	dst := make([]byte, len(src))
+	b.EncryptBlocks(dst, src)
+
+	d, _ := New(key)
+	exp := make([]byte, BlockSize)
+	for i := 0; i < len(src); i += BlockSize {
+		d.Encrypt(exp, src[i:])
+		if !bytes.Equal(dst[i:i+BlockSize], exp) {
+			t.Fatalf("block %d: Not equal %x!=%x", i/BlockSize, dst[i:i+BlockSize], exp)
+		}
+	}
+
+	b.DecryptBlocks(dst, dst)
+	if !bytes.Equal(dst, src) {
+		t.Error("decryption failed")
+	}
+}
+
+func TestBitslicedManyKeys(t *testing.T) {
+	keys := make([][]byte, Lanes)
+	for i := range keys {
+		keys[i] = make([]byte, 8)
+		rand.Read(keys[i])
+	}
+	src := make([]byte, Lanes*BlockSize)
+	rand.Read(src)
+
+	b, err := NewBitslicedKeys(keys)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	dst := make([]byte, len(src))
+	b.EncryptBlocks(dst, src)
+
+	exp := make([]byte, BlockSize)
+	for i, key := range keys {
+		d, _ := New(key)
+		d.Encrypt(exp, src[BlockSize*i:])
+		if !bytes.Equal(dst[BlockSize*i:BlockSize*(i+1)], exp) {
+			t.Fatalf("lane %d: Not equal %x!=%x", i, dst[BlockSize*i:BlockSize*(i+1)], exp)
+		}
+	}
+
+	b.DecryptBlocks(dst, dst)
+	if !bytes.Equal(dst, src) {
+		t.Error("decryption failed")
+	}
+}
+
+func TestBitslicedKeySearch(t *testing.T) {
+	// Try 64 candidate keys on a known plaintext at once
+	pt := []byte("8 bytes!")
+	keys := make([][]byte, Lanes)
+	for i := range keys {
+		keys[i] = []byte{0x13, 0x34, 0x57, 0x79, 0x9b, 0xbc, 0xdf, byte(i << 1)}
+	}
+	d, _ := New(keys[42])
+	ct := make([]byte, BlockSize)
+	d.Encrypt(ct, pt)
+
+	var s Slices
+	for i := range s {
+		s[i] = uint64(pt[0])<<56 | uint64(pt[1])<<48 | uint64(pt[2])<<40 | uint64(pt[3])<<32 |
+			uint64(pt[4])<<24 | uint64(pt[5])<<16 | uint64(pt[6])<<8 | uint64(pt[7])
+	}
+	Transpose(&s)
+
+	b, _ := NewBitslicedKeys(keys)
+	b.EncryptSlices(&s)
+
+	// Lanes whose every bit matches the ciphertext
+	match := ^uint64(0)
+	for j := range s {
+		bit := -(uint64(ct[j/8]>>(7-j%8)) & 1)
+		match &= ^(s[j] ^ bit)
+	}
+
+	if match != 1<<(63-42) {
+		t.Errorf("matching lanes %064b", match)
+	}
+}
+
+func TestBitslicedInvalidKeys(t *testing.T) {
+	if _, err := NewBitsliced(make([]byte, 7)); err == nil {
+		t.Error("key of 7 bytes accepted")
+	}
+	if _, err := NewBitslicedKeys(nil); err == nil {
+		t.Error("no keys accepted")
+	}
+	if _, err := NewBitslicedKeys(make([][]byte, Lanes+1)); err == nil {
+		t.Error("65 keys accepted")
+	}
+	if _, err := NewBitslicedKeys([][]byte{make([]byte, 9)}); err == nil {
+		t.Error("key of 9 bytes accepted")
+	}
+}
+
+func BenchmarkBitsliced(b *testing.B) {
+	c, _ := NewBitsliced([]byte("12345678"))
+	buf := make([]byte, Lanes*BlockSize)
+	b.SetBytes(int64(len(buf)))
+
+	for i := 0; i < b.N; i++ {
+		c.EncryptBlocks(buf, buf)
+	}
+}
@@ -3,7 +3,10 @@ package des
 import (
 	"crypto/cipher"
 	"encoding/binary"
+	"errors"
 	"log"
+	"math/bits"
+	"strconv"
 )
 
 // FIPS 46-3
@@ -147,19 +150,78 @@ const Mask64 uint64 = 0xffffffffffffffff
 const Mask32 uint64 = 0xffffffff
 const Mask56 = 0xffffffffffffff
 
+// spBox combines each S-box with the P permutation: spBox[i][b] is P applied
+// to the output of S_i+1 on the 6 bits b, at its place in the 32 bits word.
+var spBox = makeSPBox()
+
 type des struct {
-	keys [16]uint64
+	// Subkeys split in the chunks of 6 bits matching expand
+	keys [16][2]uint32
+	// salt is the mask of the bits of the first expand word swapped with
+	// the bits 16 positions lower, and likewise for the second word
+	salt [2]uint32
 }
 
+// KeySizeError is returned by the constructors when the key has an
+// invalid size.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "des: invalid key size " + strconv.Itoa(int(k))
+}
+
+// ErrDegenerateKey is returned by NewTriple when K1 = K2 or K2 = K3, so
+// that 3-DES reduces to single DES.
+var ErrDegenerateKey = errors.New("des: 3-DES key reduces to single DES")
+
 // New creates a new DES cipher.
 // key is 64 bits.
-func New(key []byte) cipher.Block {
-	keyU := binary.BigEndian.Uint64(key)
+// Weak keys and parity are not checked, c.f. IsWeakKey and CheckParity.
+func New(key []byte) (cipher.Block, error) {
+	if len(key) != 8 {
+		return nil, KeySizeError(len(key))
+	}
 
-	// Get subkeys
-	keys := ks(keyU)
+	d := new(des)
+	d.setKey(key)
 
-	return &des{keys}
+	return d, nil
+}
+
+// NewSalted creates a DES cipher whose E expansion is perturbed by a 12 bits
+// salt, as in the traditional Unix crypt(3).
+// If bit i of salt is set, bits i and i+24 of the output of E (numbered from
+// 0, starting with the first bit) are swapped.
+// key is 64 bits.
+func NewSalted(key []byte, salt uint16) (cipher.Block, error) {
+	b, err := New(key)
+	if err != nil {
+		return nil, err
+	}
+	d := b.(*des)
+
+	// Bits 0 to 5 of E are the first chunk of the first word, bits 6 to 11
+	// the first chunk of the second word, c.f. expand
+	for i := 0; i < 12; i++ {
+		if (salt>>i)&1 == 1 {
+			d.salt[i/6] |= 1 << (15 - i%6)
+		}
+	}
+
+	return d, nil
+}
+
+// setKey computes the subkeys
+func (d *des) setKey(key []byte) {
+	keys := ks(binary.BigEndian.Uint64(key))
+
+	for i, k := range keys {
+		// Chunk j of the 48 bits subkey goes to the place of chunk j of E
+		for j := 0; j < 8; j++ {
+			chunk := uint32(k>>(42-6*j)) & 0x3f
+			d.keys[i][j%2] |= chunk << (26 - 8*(j/2))
+		}
+	}
 }
 
 func (d *des) BlockSize() int {
@@ -174,33 +236,9 @@ func (d *des) Encrypt(dst, src []byte) {
 		log.Panic("cipher: dst too short")
 	}
 
-	// Apply permutation
-	srcUint := binary.BigEndian.Uint64(src)
-	preoutput := applyPermutation(srcUint, 64, ip[:])
-
-	// Split into l and r
-	l := uint32((preoutput >> 32) & Mask32)
-	r := uint32(preoutput & Mask32)
-
-	var lp, rp uint32 = 0, 0
-
-	// Do Feistel cipher
-	for _, key := range d.keys {
-		lp = r
-		rp = l ^ f(r, key)
-
-		l = lp
-		r = rp
-
-	}
-
-	// Put l and r together
-	res := (uint64(r) << 32) | uint64(l)
-
-	// Apply the IP inverse permutation
-	res = applyPermutation(res, 64, ipInv[:])
-
-	binary.BigEndian.PutUint64(dst, res)
+	l, r := initialPermutation(binary.BigEndian.Uint64(src))
+	l, r = d.rounds(l, r, false)
+	binary.BigEndian.PutUint64(dst, finalPermutation(r, l))
 }
 
 func (d *des) Decrypt(dst, src []byte) {
@@ -211,53 +249,65 @@ func (d *des) Decrypt(dst, src []byte) {
 		log.Panic("cipher: dst too short")
 	}
 
-	// Apply permutation
-	srcUint := binary.BigEndian.Uint64(src)
-	preoutput := applyPermutation(srcUint, 64, ip[:])
-
-	// Split into l and r
-	rp := uint32((preoutput >> 32) & Mask32)
-	lp := uint32(preoutput & Mask32)
-
-	var l, r uint32 = 0, 0
-
-	// Do feistel
-	for i := range d.keys {
-		key := d.keys[len(d.keys)-1-i]
-
-		r = lp
-		l = rp ^ f(lp, key)
+	l, r := initialPermutation(binary.BigEndian.Uint64(src))
+	l, r = d.rounds(l, r, true)
+	binary.BigEndian.PutUint64(dst, finalPermutation(r, l))
+}
 
-		lp = l
-		rp = r
+// rounds applies the 16 Feistel rounds to l and r, with the subkeys in
+// reverse order to decrypt.
+// The halves are not swapped after the last round.
+func (d *des) rounds(l, r uint32, decrypt bool) (uint32, uint32) {
+	for i := 0; i < 16; i++ {
+		k := &d.keys[i]
+		if decrypt {
+			k = &d.keys[15-i]
+		}
 
+		l, r = r, l^d.f(r, k)
 	}
 
-	res := (uint64(l) << 32) | uint64(r)
-
-	res = applyPermutation(res, 64, ipInv[:])
-
-	binary.BigEndian.PutUint64(dst, res)
+	return l, r
 }
 
 type tdes struct {
-	des1, des2, des3 cipher.Block
+	des1, des2, des3 des
 }
 
 // NewTriple creates a new 3-DES cipher.
-// key contains 3 64-bit keys.
-func NewTriple(key []byte) cipher.Block {
-	des1 := New(key[:8])
-	des2 := New(key[8:16])
-	des3 := New(key[16:])
+// key contains 3 64-bit keys K1 || K2 || K3 (keying option 1), or 2 64-bit
+// keys K1 || K2 with K3 = K1 (keying option 2).
+// Returns ErrDegenerateKey if K1 = K2 or K2 = K3, ignoring the parity bits,
+// which includes keying option 3 (K1 = K2 = K3).
+// c.f. SP 800-67 3.1
+func NewTriple(key []byte) (cipher.Block, error) {
+	if len(key) != 16 && len(key) != 24 {
+		return nil, KeySizeError(len(key))
+	}
 
-	return &tdes{des1, des2, des3}
+	k1, k2, k3 := key[:8], key[8:16], key[:8]
+	if len(key) == 24 {
+		k3 = key[16:]
+	}
+	if equalKeys(k1, k2) || equalKeys(k2, k3) {
+		return nil, ErrDegenerateKey
+	}
+
+	d := new(tdes)
+	d.des1.setKey(k1)
+	d.des2.setKey(k2)
+	d.des3.setKey(k3)
+
+	return d, nil
 }
 
 func (d *tdes) BlockSize() int {
 	return BlockSize
 }
 
+// The final permutation of a DES cancels the initial permutation of the
+// next one, so 3-DES only swaps the halves between them.
+
 func (d *tdes) Encrypt(dst, src []byte) {
 	if len(src) < BlockSize {
 		log.Panic("cipher: src too short")
@@ -266,9 +316,11 @@ func (d *tdes) Encrypt(dst, src []byte) {
 		log.Panic("cipher: dst too short")
 	}
 
-	d.des1.Encrypt(dst, src)
-	d.des2.Decrypt(dst, dst)
-	d.des3.Encrypt(dst, dst)
+	l, r := initialPermutation(binary.BigEndian.Uint64(src))
+	l, r = d.des1.rounds(l, r, false)
+	l, r = d.des2.rounds(r, l, true)
+	l, r = d.des3.rounds(r, l, false)
+	binary.BigEndian.PutUint64(dst, finalPermutation(r, l))
 }
 
 func (d *tdes) Decrypt(dst, src []byte) {
@@ -279,9 +331,102 @@ func (d *tdes) Decrypt(dst, src []byte) {
 		log.Panic("cipher: dst too short")
 	}
 
-	d.des3.Decrypt(dst, src)
-	d.des2.Encrypt(dst, dst)
-	d.des1.Decrypt(dst, dst)
+	l, r := initialPermutation(binary.BigEndian.Uint64(src))
+	l, r = d.des3.rounds(l, r, true)
+	l, r = d.des2.rounds(r, l, false)
+	l, r = d.des1.rounds(r, l, true)
+	binary.BigEndian.PutUint64(dst, finalPermutation(r, l))
+}
+
+// initialPermutation applies IP to the block and splits it into L0 and R0,
+// with a sequence of swaps of bit groups between the halves.
+func initialPermutation(block uint64) (uint32, uint32) {
+	l, r := uint32(block>>32), uint32(block)
+
+	t := ((l >> 4) ^ r) & 0x0f0f0f0f
+	r ^= t
+	l ^= t << 4
+	t = ((l >> 16) ^ r) & 0x0000ffff
+	r ^= t
+	l ^= t << 16
+	t = ((r >> 2) ^ l) & 0x33333333
+	l ^= t
+	r ^= t << 2
+	t = ((r >> 8) ^ l) & 0x00ff00ff
+	l ^= t
+	r ^= t << 8
+	t = ((l >> 1) ^ r) & 0x55555555
+	r ^= t
+	l ^= t << 1
+
+	return l, r
+}
+
+// finalPermutation applies IP^-1 to the block l || r, with the swaps of
+// initialPermutation in reverse order.
+func finalPermutation(l, r uint32) uint64 {
+	t := ((l >> 1) ^ r) & 0x55555555
+	r ^= t
+	l ^= t << 1
+	t = ((r >> 8) ^ l) & 0x00ff00ff
+	l ^= t
+	r ^= t << 8
+	t = ((r >> 2) ^ l) & 0x33333333
+	l ^= t
+	r ^= t << 2
+	t = ((l >> 16) ^ r) & 0x0000ffff
+	r ^= t
+	l ^= t << 16
+	t = ((l >> 4) ^ r) & 0x0f0f0f0f
+	r ^= t
+	l ^= t << 4
+
+	return uint64(l)<<32 | uint64(r)
+}
+
+// expand computes the E expansion of r as two words holding the 6 bits
+// chunks 0, 2, 4, 6 and 1, 3, 5, 7, at bits 26, 18, 10 and 2.
+// Chunk i is made of the bits 4i to 4i+5 of r, numbered from 1 and
+// wrapping around.
+func expand(r uint32) (uint32, uint32) {
+	return bits.RotateLeft32(r, -1), bits.RotateLeft32(r, 3)
+}
+
+// f is the f function in the DES algorithm.
+// r is 32 bits.
+// k is a subkey split as in expand.
+func (d *des) f(r uint32, k *[2]uint32) uint32 {
+	e0, e1 := expand(r)
+
+	// Swap the salted bits
+	swap := ((e0 >> 16) ^ e0) & d.salt[0]
+	e0 ^= swap | (swap << 16)
+	swap = ((e1 >> 16) ^ e1) & d.salt[1]
+	e1 ^= swap | (swap << 16)
+
+	e0 ^= k[0]
+	e1 ^= k[1]
+
+	return spBox[0][e0>>26&0x3f] | spBox[2][e0>>18&0x3f] |
+		spBox[4][e0>>10&0x3f] | spBox[6][e0>>2&0x3f] |
+		spBox[1][e1>>26&0x3f] | spBox[3][e1>>18&0x3f] |
+		spBox[5][e1>>10&0x3f] | spBox[7][e1>>2&0x3f]
+}
+
+// makeSPBox computes spBox from the S-boxes and P
+func makeSPBox() [8][64]uint32 {
+	var res [8][64]uint32
+
+	for i := range res {
+		for b := range res[i] {
+			bi, bj := getIj(uint8(b))
+			s := uint64(getSij(bi, bj, sTables[i]))
+
+			res[i][b] = uint32(applyPermutation(s<<(4*(7-i)), 32, p[:]))
+		}
+	}
+
+	return res
 }
 
 // applyPermutation applies a permutation on the bits of src
@@ -338,39 +483,6 @@ func ks(key uint64) [16]uint64 {
 	return [16]uint64(keys)
 }
 
-// f is the f function in the DES algorithm.
-// r is 32 bits.
-// k is a 48 bit key.
-func f(r uint32, k uint64) uint32 {
-	// Compute e
-	e := applyPermutation(uint64(r), 32, eTable[:])
-
-	//fmt.Printf("E    = %048b\n", e)
-
-	added := e ^ k
-
-	//fmt.Printf("E+K  = %048b\n", added)
-	var outS uint32 = 0
-
-	// For each block of 6 bits
-	for i := 0; i < 8; i++ {
-		b := (added >> (6 * (8 - uint64(i) - 1))) & 0b111111
-		bi, bj := getIj(uint8(b))
-		sRes := getSij(bi, bj, sTables[i])
-
-		outS |= uint32(sRes) << (4 * (8 - uint32(i) - 1))
-	}
-
-	//fmt.Printf("outS = %032b\n", outS)
-
-	res := applyPermutation(uint64(outS), 32, p[:])
-
-	//fmt.Printf("f    = %032b\n", res)
-
-	//fmt.Println()
-	return uint32(res)
-}
-
 // getSij gets the 4 bits value from a s matrix
 func getSij(i, j uint8, s [64]uint8) uint8 {
 	return s[i*16+j]
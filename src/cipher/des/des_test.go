@@ -1,6 +1,7 @@
 package des
 
 import (
+	"bytes"
 	godes "crypto/des"
 	"crypto/rand"
 	"encoding/binary"
@@ -78,7 +79,7 @@ func TestEncrypt(t *testing.T) {
 
 	cB := make([]byte, 8)
 
-	des := New(keyB)
+	des, _ := New(keyB)
 	des.Encrypt(cB, mB)
 
 	c := binary.BigEndian.Uint64(cB)
@@ -100,7 +101,7 @@ func TestDecrypt(t *testing.T) {
 
 	mB := make([]byte, 8)
 
-	des := New(keyB)
+	des, _ := New(keyB)
 	des.Decrypt(mB, cB)
 
 	m := binary.BigEndian.Uint64(mB)
@@ -117,7 +118,7 @@ func TestEncryptGo(t *testing.T) {
 	binary.BigEndian.PutUint64(keyB, key)
 
 	goDes, _ := godes.NewCipher(keyB)
-	des := New(keyB)
+	des, _ := New(keyB)
 
 	m := make([]byte, 8)
 	_, err := rand.Read(m)
@@ -145,7 +146,7 @@ func TestDecryptGo(t *testing.T) {
 	binary.BigEndian.PutUint64(keyB, key)
 
 	goDes, _ := godes.NewCipher(keyB)
-	des := New(keyB)
+	des, _ := New(keyB)
 
 	m := make([]byte, 8)
 	_, err := rand.Read(m)
@@ -183,7 +184,10 @@ func TestTDES(t *testing.T) {
 	}
 
 	goTdes, _ := godes.NewTripleDESCipher(keyB)
-	tdes := NewTriple(keyB)
+	tdes, err := NewTriple(keyB)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 
 	goDst := make([]byte, 8)
 	dst := make([]byte, 8)
@@ -240,3 +244,266 @@ func TestGetSij(t *testing.T) {
 		t.Error()
 	}
 }
+
+func TestSalted(t *testing.T) {
+	keyB := make([]byte, 8)
+	m := make([]byte, 8)
+	if _, err := rand.Read(keyB); err != nil {
+		t.Fatalf("key error")
+	}
+	if _, err := rand.Read(m); err != nil {
+		t.Fatalf("m error")
+	}
+
+	// A zero salt is plain DES
+	goDes, _ := godes.NewCipher(keyB)
+	goDst := make([]byte, 8)
+	goDes.Encrypt(goDst, m)
+
+	dst := make([]byte, 8)
+	unsalted, _ := NewSalted(keyB, 0)
+	unsalted.Encrypt(dst, m)
+	if !bytes.Equal(dst, goDst) {
+		t.Fatalf("Not the same")
+	}
+
+	// Salted decryption is the inverse of salted encryption
+	salted, _ := NewSalted(keyB, 0xabc)
+	salted.Encrypt(dst, m)
+	if bytes.Equal(dst, goDst) {
+		t.Fatalf("Salt ignored")
+	}
+	salted.Decrypt(dst, dst)
+	if !bytes.Equal(dst, m) {
+		t.Fatalf("Not the same")
+	}
+}
+
+func TestKeySizes(t *testing.T) {
+	for _, size := range []int{0, 7, 9, 16} {
+		if _, err := New(make([]byte, size)); err != KeySizeError(size) {
+			t.Errorf("DES key of %d bytes: %v", size, err)
+		}
+	}
+
+	for _, size := range []int{8, 15, 32} {
+		if _, err := NewTriple(make([]byte, size)); err != KeySizeError(size) {
+			t.Errorf("3-DES key of %d bytes: %v", size, err)
+		}
+	}
+}
+
+func TestTwoKeyTDES(t *testing.T) {
+	keyB := make([]byte, 16)
+	m := make([]byte, 8)
+	if _, err := rand.Read(keyB); err != nil {
+		t.Fatalf("key error")
+	}
+	if _, err := rand.Read(m); err != nil {
+		t.Fatalf("m error")
+	}
+
+	// Keying option 2 is option 1 with K3 = K1
+	goTdes, _ := godes.NewTripleDESCipher(append(keyB, keyB[:8]...))
+	tdes, err := NewTriple(keyB)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	goDst := make([]byte, 8)
+	dst := make([]byte, 8)
+	goTdes.Encrypt(goDst, m)
+	tdes.Encrypt(dst, m)
+	if !bytes.Equal(dst, goDst) {
+		t.Fatalf("Not the same")
+	}
+
+	tdes.Decrypt(dst, dst)
+	if !bytes.Equal(dst, m) {
+		t.Fatalf("Not the same")
+	}
+}
+
+func TestDegenerateTDES(t *testing.T) {
+	k1 := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	k2 := []byte{0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01}
+	// k1 with the parity bits flipped
+	k1p := []byte{0x00, 0x22, 0x44, 0x66, 0x88, 0xaa, 0xcc, 0xee}
+
+	keys := [][]byte{
+		append(append(append([]byte{}, k1...), k1...), k1...),
+		append(append(append([]byte{}, k1...), k1p...), k2...),
+		append(append(append([]byte{}, k2...), k1...), k1p...),
+		append(append([]byte{}, k1...), k1p...),
+	}
+	for _, key := range keys {
+		if _, err := NewTriple(key); err != ErrDegenerateKey {
+			t.Errorf("%x: %v", key, err)
+		}
+	}
+
+	if _, err := NewTriple(append(append(append([]byte{}, k1...), k2...), k1...)); err != nil {
+		t.Errorf("K1 = K3 rejected: %v", err)
+	}
+}
+
+func TestWeakKeys(t *testing.T) {
+	m := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	dst := make([]byte, 8)
+
+	// Encrypting twice with a weak key is the identity
+	for _, k := range weakKeys {
+		key := binary.BigEndian.AppendUint64(nil, k)
+		if !IsWeakKey(key) || IsSemiWeakKey(key) {
+			t.Errorf("%x not weak", key)
+		}
+
+		d, _ := New(key)
+		d.Encrypt(dst, m)
+		d.Encrypt(dst, dst)
+		if !bytes.Equal(dst, m) {
+			t.Errorf("%x not weak", key)
+		}
+	}
+
+	// Encrypting with both keys of a semi-weak pair is the identity
+	for i := 0; i < len(semiWeakKeys); i += 2 {
+		key1 := binary.BigEndian.AppendUint64(nil, semiWeakKeys[i])
+		key2 := binary.BigEndian.AppendUint64(nil, semiWeakKeys[i+1])
+		if !IsSemiWeakKey(key1) || !IsSemiWeakKey(key2) || IsWeakKey(key1) {
+			t.Errorf("%x, %x not semi-weak", key1, key2)
+		}
+
+		d1, _ := New(key1)
+		d2, _ := New(key2)
+		d1.Encrypt(dst, m)
+		d2.Encrypt(dst, dst)
+		if !bytes.Equal(dst, m) {
+			t.Errorf("%x, %x not semi-weak", key1, key2)
+		}
+	}
+
+	// Parity bits are ignored
+	if !IsWeakKey(make([]byte, 8)) {
+		t.Error("0000000000000000 not weak")
+	}
+	if IsWeakKey(m) || IsSemiWeakKey(m) {
+		t.Errorf("%x weak", m)
+	}
+}
+
+func TestParity(t *testing.T) {
+	key := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	if !CheckParity(key) {
+		t.Errorf("%x has odd parity", key)
+	}
+
+	key = []byte{0x00, 0x22, 0x44, 0x66, 0x88, 0xaa, 0xcc, 0xee}
+	if CheckParity(key) {
+		t.Errorf("%x has even parity", key)
+	}
+
+	SetParity(key)
+	if !bytes.Equal(key, []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}) {
+		t.Errorf("Not equal %x", key)
+	}
+}
+
+func TestPermutations(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		var b [8]byte
+		rand.Read(b[:])
+		block := binary.BigEndian.Uint64(b[:])
+
+		l, r := initialPermutation(block)
+		exp := applyPermutation(block, 64, ip[:])
+		if res := uint64(l)<<32 | uint64(r); res != exp {
+			t.Errorf("IP %x != %x", res, exp)
+		}
+
+		exp = applyPermutation(block, 64, ipInv[:])
+		if res := finalPermutation(uint32(block>>32), uint32(block)); res != exp {
+			t.Errorf("IP^-1 %x != %x", res, exp)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		var b [4]byte
+		rand.Read(b[:])
+		r := binary.BigEndian.Uint32(b[:])
+
+		// Gather the chunks of expand in the order of E
+		e0, e1 := expand(r)
+		var res uint64
+		for j := 0; j < 8; j++ {
+			w := e0
+			if j%2 == 1 {
+				w = e1
+			}
+			res = res<<6 | uint64(w>>(26-8*(j/2))&0x3f)
+		}
+
+		if exp := applyPermutation(uint64(r), 32, eTable[:]); res != exp {
+			t.Errorf("E %x != %x", res, exp)
+		}
+	}
+}
+
+func BenchmarkEncrypt(b *testing.B) {
+	c, _ := New([]byte("12345678"))
+	buf := make([]byte, BlockSize)
+	b.SetBytes(int64(BlockSize))
+
+	for i := 0; i < b.N; i++ {
+		c.Encrypt(buf, buf)
+	}
+}
+
+func BenchmarkDecrypt(b *testing.B) {
+	c, _ := New([]byte("12345678"))
+	buf := make([]byte, BlockSize)
+	b.SetBytes(int64(BlockSize))
+
+	for i := 0; i < b.N; i++ {
+		c.Decrypt(buf, buf)
+	}
+}
+
+func BenchmarkTDESEncrypt(b *testing.B) {
+	c, _ := NewTriple([]byte("123456789012345678901234"))
+	buf := make([]byte, BlockSize)
+	b.SetBytes(int64(BlockSize))
+
+	for i := 0; i < b.N; i++ {
+		c.Encrypt(buf, buf)
+	}
+}
+
+func BenchmarkGoEncrypt(b *testing.B) {
+	c, _ := godes.NewCipher([]byte("12345678"))
+	buf := make([]byte, BlockSize)
+	b.SetBytes(int64(BlockSize))
+
+	for i := 0; i < b.N; i++ {
+		c.Encrypt(buf, buf)
+	}
+}
+
+func BenchmarkGoTDESEncrypt(b *testing.B) {
+	c, _ := godes.NewTripleDESCipher([]byte("123456789012345678901234"))
+	buf := make([]byte, BlockSize)
+	b.SetBytes(int64(BlockSize))
+
+	for i := 0; i < b.N; i++ {
+		c.Encrypt(buf, buf)
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	key := []byte("12345678")
+	for i := 0; i < b.N; i++ {
+		New(key)
+	}
+}
@@ -0,0 +1,66 @@
+package des
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"log"
+)
+
+// DESX, c.f. "How to Protect DES Against Exhaustive Key Search" (Kilian,
+// Rogaway 1996)
+
+// DESXKeySize is the size of a DESX key K || K1 || K2: the DES key, the
+// pre-whitening key and the post-whitening key.
+// Only 184 of the 192 bits are used, the DES parity bits are ignored.
+const DESXKeySize int = 24
+
+type desx struct {
+	b      cipher.Block
+	k1, k2 [BlockSize]byte
+}
+
+// NewDESX creates a new DESX cipher, computing
+// C = K2 xor DES_K(P xor K1).
+// key is 24 bytes, laid out as in RSA's DESX and OpenSSL's desx-cbc.
+func NewDESX(key []byte) (cipher.Block, error) {
+	if len(key) != DESXKeySize {
+		return nil, KeySizeError(len(key))
+	}
+
+	b, _ := New(key[:8])
+	d := &desx{b: b}
+	copy(d.k1[:], key[8:16])
+	copy(d.k2[:], key[16:])
+
+	return d, nil
+}
+
+func (d *desx) BlockSize() int {
+	return BlockSize
+}
+
+func (d *desx) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	subtle.XORBytes(dst, src[:BlockSize], d.k1[:])
+	d.b.Encrypt(dst, dst)
+	subtle.XORBytes(dst, dst[:BlockSize], d.k2[:])
+}
+
+func (d *desx) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	subtle.XORBytes(dst, src[:BlockSize], d.k2[:])
+	d.b.Decrypt(dst, dst)
+	subtle.XORBytes(dst, dst[:BlockSize], d.k1[:])
+}
@@ -0,0 +1,70 @@
+package des
+
+import (
+	godes "crypto/des"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"testing"
+)
+
+func TestDESX(t *testing.T) {
+	// The reference vectors of RSA Data Security were only distributed with
+	// its commercial toolkits and are not publicly available. These ones
+	// were computed with OpenSSL desx-cbc on a single block with a zero IV,
+	// OpenSSL using the key layout of RSA's DESX.
+	vectors := []struct {
+		keyHex, ptHex, ctHex string
+	}{
+		{"0123456789abcdeff1e0d3c2b5a49786fedcba9876543210", "0123456789abcdef", "e6768bfd0a80ec81"},
+		{"000000000000000000000000000000000000000000000000", "0000000000000000", "8ca64de9c1b123a7"},
+		{"ffffffffffffffffffffffffffffffffffffffffffffffff", "ffffffffffffffff", "355550b2150e2451"},
+		{"0123456789abcdef0123456789abcdef0123456789abcdef", "4e6f772069732074", "97e091c155b7ccf8"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		pt, _ := hex.DecodeString(v.ptHex)
+		c, err := NewDESX(key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, BlockSize)
+		c.Encrypt(res, pt)
+		if resHex := hex.EncodeToString(res); resHex != v.ctHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ctHex)
+		}
+
+		c.Decrypt(res, res)
+		if resHex := hex.EncodeToString(res); resHex != v.ptHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ptHex)
+		}
+	}
+}
+
+func TestDESXGo(t *testing.T) {
+	tries := 100
+
+	for tr := 0; tr < tries; tr++ {
+		key := make([]byte, DESXKeySize)
+		rand.Read(key)
+		m := make([]byte, BlockSize)
+		rand.Read(m)
+
+		// K2 xor DES_K(P xor K1) with crypto/des
+		goDes, _ := godes.NewCipher(key[:8])
+		exp := make([]byte, BlockSize)
+		subtle.XORBytes(exp, m, key[8:16])
+		goDes.Encrypt(exp, exp)
+		subtle.XORBytes(exp, exp, key[16:])
+
+		res := make([]byte, BlockSize)
+		c, _ := NewDESX(key)
+		c.Encrypt(res, m)
+
+		if hex.EncodeToString(res) != hex.EncodeToString(exp) {
+			t.Errorf("Not equal %x!=%x", res, exp)
+		}
+	}
+}
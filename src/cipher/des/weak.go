@@ -0,0 +1,87 @@
+package des
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"math/bits"
+)
+
+// Weak keys, for which encryption and decryption are the same
+// c.f. SP 800-67 3.3.2
+var weakKeys = [...]uint64{
+	0x0101010101010101,
+	0xfefefefefefefefe,
+	0xe0e0e0e0f1f1f1f1,
+	0x1f1f1f1f0e0e0e0e,
+}
+
+// Semi-weak keys, by pairs such that encrypting with one key is decrypting
+// with the other
+// c.f. SP 800-67 3.3.2
+var semiWeakKeys = [...]uint64{
+	0x01fe01fe01fe01fe, 0xfe01fe01fe01fe01,
+	0x1fe01fe00ef10ef1, 0xe01fe01ff10ef10e,
+	0x01e001e001f101f1, 0xe001e001f101f101,
+	0x1ffe1ffe0efe0efe, 0xfe1ffe1ffe0efe0e,
+	0x011f011f010e010e, 0x1f011f010e010e01,
+	0xe0fee0fef1fef1fe, 0xfee0fee0fef1fef1,
+}
+
+// Mask of the key bits, without the parity bits
+const keyBits uint64 = 0xfefefefefefefefe
+
+// equalKeys reports whether two 64 bits keys are equal, ignoring the parity
+// bits, in constant time
+func equalKeys(a, b []byte) bool {
+	diff := (binary.BigEndian.Uint64(a) ^ binary.BigEndian.Uint64(b)) & keyBits
+	return subtle.ConstantTimeEq(int32(uint32(diff>>32)|uint32(diff)), 0) == 1
+}
+
+// inKeys reports whether the 64 bits key is one of keys, ignoring the
+// parity bits
+func inKeys(key []byte, keys []uint64) bool {
+	if len(key) != 8 {
+		return false
+	}
+
+	found := false
+	for _, k := range keys {
+		if equalKeys(key, binary.BigEndian.AppendUint64(nil, k)) {
+			found = true
+		}
+	}
+
+	return found
+}
+
+// IsWeakKey reports whether key is one of the 4 DES weak keys, ignoring the
+// parity bits.
+func IsWeakKey(key []byte) bool {
+	return inKeys(key, weakKeys[:])
+}
+
+// IsSemiWeakKey reports whether key is one of the 12 DES semi-weak keys,
+// ignoring the parity bits.
+func IsSemiWeakKey(key []byte) bool {
+	return inKeys(key, semiWeakKeys[:])
+}
+
+// CheckParity reports whether every byte of key has odd parity, the least
+// significant bit of each byte being its parity bit.
+func CheckParity(key []byte) bool {
+	for _, b := range key {
+		if bits.OnesCount8(b)%2 == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SetParity sets the parity bit of every byte of key so that it has odd
+// parity.
+func SetParity(key []byte) {
+	for i, b := range key {
+		key[i] = b&0xfe | byte(bits.OnesCount8(b>>1)+1)&1
+	}
+}
@@ -0,0 +1,159 @@
+package idea
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"log"
+	"strconv"
+)
+
+// IDEA, c.f. "On the Design and Security of Block Ciphers" (Lai 1992)
+
+const BlockSize int = 8
+
+const KeySize int = 16
+
+// 8 rounds of 6 subkeys, and 4 subkeys for the output transformation
+const numKeys = 52
+
+type idea struct {
+	ek, dk [numKeys]uint16
+}
+
+// KeySizeError is returned by New when the key has an invalid size.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "idea: invalid key size " + strconv.Itoa(int(k))
+}
+
+// New creates a new IDEA cipher.
+// key is 128 bits.
+func New(key []byte) (cipher.Block, error) {
+	if len(key) != KeySize {
+		return nil, KeySizeError(len(key))
+	}
+
+	c := new(idea)
+
+	// The subkeys are the successive 16 bits words of the key, rotated
+	// left by 25 bits every 8 words
+	hi, lo := binary.BigEndian.Uint64(key), binary.BigEndian.Uint64(key[8:])
+	for i := 0; i < numKeys; i++ {
+		if i > 0 && i%8 == 0 {
+			hi, lo = hi<<25|lo>>39, lo<<25|hi>>39
+		}
+		j := i % 8
+		if j < 4 {
+			c.ek[i] = uint16(hi >> (48 - 16*j))
+		} else {
+			c.ek[i] = uint16(lo >> (48 - 16*(j-4)))
+		}
+	}
+
+	// The decryption subkeys are the inverses of the encryption subkeys in
+	// reverse order, the middle additive keys being swapped except in the
+	// first and last rounds
+	for r := 0; r <= 8; r++ {
+		e := c.ek[6*(8-r):]
+		d := c.dk[6*r:]
+
+		d[0] = mulInv(e[0])
+		d[3] = mulInv(e[3])
+		if r == 0 || r == 8 {
+			d[1], d[2] = -e[1], -e[2]
+		} else {
+			d[1], d[2] = -e[2], -e[1]
+		}
+
+		if r < 8 {
+			d[4], d[5] = c.ek[6*(7-r)+4], c.ek[6*(7-r)+5]
+		}
+	}
+
+	return c, nil
+}
+
+func (c *idea) BlockSize() int {
+	return BlockSize
+}
+
+func (c *idea) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	crypt(dst, src, &c.ek)
+}
+
+func (c *idea) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	crypt(dst, src, &c.dk)
+}
+
+// crypt applies the 8 rounds and the output transformation with the
+// subkeys k
+func crypt(dst, src []byte, k *[numKeys]uint16) {
+	x1 := binary.BigEndian.Uint16(src)
+	x2 := binary.BigEndian.Uint16(src[2:])
+	x3 := binary.BigEndian.Uint16(src[4:])
+	x4 := binary.BigEndian.Uint16(src[6:])
+
+	for r := 0; r < 8; r++ {
+		rk := k[6*r:]
+
+		x1 = mul(x1, rk[0])
+		x2 += rk[1]
+		x3 += rk[2]
+		x4 = mul(x4, rk[3])
+
+		// MA structure
+		t0 := mul(x1^x3, rk[4])
+		t1 := mul(t0+(x2^x4), rk[5])
+		t0 += t1
+
+		x1 ^= t1
+		x4 ^= t0
+		x2, x3 = x3^t1, x2^t0
+	}
+
+	// The output transformation undoes the swap of the last round
+	binary.BigEndian.PutUint16(dst, mul(x1, k[48]))
+	binary.BigEndian.PutUint16(dst[2:], x3+k[49])
+	binary.BigEndian.PutUint16(dst[4:], x2+k[50])
+	binary.BigEndian.PutUint16(dst[6:], mul(x4, k[51]))
+}
+
+// mul multiplies a and b modulo 2^16 + 1, 0 representing 2^16
+func mul(a, b uint16) uint16 {
+	x, y := uint32(a), uint32(b)
+	if x == 0 {
+		x = 1 << 16
+	}
+	if y == 0 {
+		y = 1 << 16
+	}
+
+	return uint16(uint64(x) * uint64(y) % (1<<16 + 1))
+}
+
+// mulInv returns the inverse of a modulo 2^16 + 1, computed as a^(2^16 - 1)
+func mulInv(a uint16) uint16 {
+	res := uint16(1)
+	for e := 1<<16 - 1; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			res = mul(res, a)
+		}
+		a = mul(a, a)
+	}
+	return res
+}
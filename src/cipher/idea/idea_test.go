@@ -0,0 +1,71 @@
+package idea
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+func TestVectors(t *testing.T) {
+	// The first vector is from Lai's thesis, the others were checked with
+	// OpenSSL idea-ecb
+	vectors := []struct {
+		keyHex, ptHex, ctHex string
+	}{
+		{"00010002000300040005000600070008", "0000000100020003", "11fbed2b01986de5"},
+		{"00010002000300040005000600070008", "0123456789abcdef", "ee35adae506ab1f0"},
+		{"2bd6459f82c5b300952c49104881ff48", "0000000100020003", "1084d44727844a95"},
+		{"2bd6459f82c5b300952c49104881ff48", "0123456789abcdef", "ca247989d6cda399"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		pt, _ := hex.DecodeString(v.ptHex)
+		c, err := New(key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, BlockSize)
+		c.Encrypt(res, pt)
+		if resHex := hex.EncodeToString(res); resHex != v.ctHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ctHex)
+		}
+
+		c.Decrypt(res, res)
+		if resHex := hex.EncodeToString(res); resHex != v.ptHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ptHex)
+		}
+	}
+}
+
+func TestMulInv(t *testing.T) {
+	for _, a := range []uint16{0, 1, 2, 3, 0x8000, 0xffff} {
+		if res := mul(a, mulInv(a)); res != 1 {
+			t.Errorf("%d * %d^-1 = %d", a, a, res)
+		}
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	// Zero words in the key and the block exercise the 2^16 case of mul
+	key := make([]byte, KeySize)
+	rand.Read(key[8:])
+	m := make([]byte, BlockSize)
+	rand.Read(m[4:])
+
+	c, _ := New(key)
+	res := make([]byte, BlockSize)
+	c.Encrypt(res, m)
+	c.Decrypt(res, res)
+	if !bytes.Equal(res, m) {
+		t.Errorf("Not equal %x!=%x", res, m)
+	}
+}
+
+func TestKeySize(t *testing.T) {
+	if _, err := New(make([]byte, 8)); err == nil {
+		t.Error("invalid key size accepted")
+	}
+}
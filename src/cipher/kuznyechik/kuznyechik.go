@@ -0,0 +1,191 @@
+package kuznyechik
+
+import (
+	"crypto/cipher"
+	"log"
+	"strconv"
+)
+
+// GOST R 34.12-2015, c.f. also RFC 7801
+// Blocks and keys are byte strings in the order of the standard: the
+// first byte is a15, the most significant one.
+
+const BlockSize int = 16
+
+const KeySize int = 32
+
+var pi = [256]byte{
+	0xfc, 0xee, 0xdd, 0x11, 0xcf, 0x6e, 0x31, 0x16, 0xfb, 0xc4, 0xfa, 0xda, 0x23, 0xc5, 0x04, 0x4d,
+	0xe9, 0x77, 0xf0, 0xdb, 0x93, 0x2e, 0x99, 0xba, 0x17, 0x36, 0xf1, 0xbb, 0x14, 0xcd, 0x5f, 0xc1,
+	0xf9, 0x18, 0x65, 0x5a, 0xe2, 0x5c, 0xef, 0x21, 0x81, 0x1c, 0x3c, 0x42, 0x8b, 0x01, 0x8e, 0x4f,
+	0x05, 0x84, 0x02, 0xae, 0xe3, 0x6a, 0x8f, 0xa0, 0x06, 0x0b, 0xed, 0x98, 0x7f, 0xd4, 0xd3, 0x1f,
+	0xeb, 0x34, 0x2c, 0x51, 0xea, 0xc8, 0x48, 0xab, 0xf2, 0x2a, 0x68, 0xa2, 0xfd, 0x3a, 0xce, 0xcc,
+	0xb5, 0x70, 0x0e, 0x56, 0x08, 0x0c, 0x76, 0x12, 0xbf, 0x72, 0x13, 0x47, 0x9c, 0xb7, 0x5d, 0x87,
+	0x15, 0xa1, 0x96, 0x29, 0x10, 0x7b, 0x9a, 0xc7, 0xf3, 0x91, 0x78, 0x6f, 0x9d, 0x9e, 0xb2, 0xb1,
+	0x32, 0x75, 0x19, 0x3d, 0xff, 0x35, 0x8a, 0x7e, 0x6d, 0x54, 0xc6, 0x80, 0xc3, 0xbd, 0x0d, 0x57,
+	0xdf, 0xf5, 0x24, 0xa9, 0x3e, 0xa8, 0x43, 0xc9, 0xd7, 0x79, 0xd6, 0xf6, 0x7c, 0x22, 0xb9, 0x03,
+	0xe0, 0x0f, 0xec, 0xde, 0x7a, 0x94, 0xb0, 0xbc, 0xdc, 0xe8, 0x28, 0x50, 0x4e, 0x33, 0x0a, 0x4a,
+	0xa7, 0x97, 0x60, 0x73, 0x1e, 0x00, 0x62, 0x44, 0x1a, 0xb8, 0x38, 0x82, 0x64, 0x9f, 0x26, 0x41,
+	0xad, 0x45, 0x46, 0x92, 0x27, 0x5e, 0x55, 0x2f, 0x8c, 0xa3, 0xa5, 0x7d, 0x69, 0xd5, 0x95, 0x3b,
+	0x07, 0x58, 0xb3, 0x40, 0x86, 0xac, 0x1d, 0xf7, 0x30, 0x37, 0x6b, 0xe4, 0x88, 0xd9, 0xe7, 0x89,
+	0xe1, 0x1b, 0x83, 0x49, 0x4c, 0x3f, 0xf8, 0xfe, 0x8d, 0x53, 0xaa, 0x90, 0xca, 0xd8, 0x85, 0x61,
+	0x20, 0x71, 0x67, 0xa4, 0x2d, 0x2b, 0x09, 0x5b, 0xcb, 0x9b, 0x25, 0xd0, 0xbe, 0xe5, 0x6c, 0x52,
+	0x59, 0xa6, 0x74, 0xd2, 0xe6, 0xf4, 0xb4, 0xc0, 0xd1, 0x66, 0xaf, 0xc2, 0x39, 0x4b, 0x63, 0xb6,
+}
+
+var piInv = invert(&pi)
+
+// Coefficients of the linear function l, for a15 to a0
+var lCoeffs = [BlockSize]byte{148, 32, 133, 16, 194, 192, 1, 251, 1, 192, 194, 16, 133, 32, 148, 1}
+
+type block = [BlockSize]byte
+
+type kuznyechik struct {
+	k [10]block
+}
+
+// KeySizeError is returned by New when the key has an invalid size.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "kuznyechik: invalid key size " + strconv.Itoa(int(k))
+}
+
+// New creates a new Kuznyechik cipher.
+// key is 256 bits.
+func New(key []byte) (cipher.Block, error) {
+	if len(key) != KeySize {
+		return nil, KeySizeError(len(key))
+	}
+
+	c := new(kuznyechik)
+	copy(c.k[0][:], key)
+	copy(c.k[1][:], key[BlockSize:])
+
+	// The pairs of keys are computed with 8 Feistel rounds F[C_i]
+	for i := 1; i < 5; i++ {
+		k1, k0 := c.k[2*i-2], c.k[2*i-1]
+		for j := 1; j <= 8; j++ {
+			var ci block
+			ci[BlockSize-1] = byte(8*(i-1) + j)
+			ci = l(ci)
+
+			k1, k0 = xor(lsx(&ci, k1), k0), k1
+		}
+		c.k[2*i], c.k[2*i+1] = k1, k0
+	}
+
+	return c, nil
+}
+
+func (c *kuznyechik) BlockSize() int {
+	return BlockSize
+}
+
+func (c *kuznyechik) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	var a block
+	copy(a[:], src)
+	for i := 0; i < 9; i++ {
+		a = lsx(&c.k[i], a)
+	}
+	a = xor(a, c.k[9])
+	copy(dst, a[:])
+}
+
+func (c *kuznyechik) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	var a block
+	copy(a[:], src)
+	a = xor(a, c.k[9])
+	for i := 8; i >= 0; i-- {
+		a = lInv(a)
+		for j := range a {
+			a[j] = piInv[a[j]]
+		}
+		a = xor(a, c.k[i])
+	}
+	copy(dst, a[:])
+}
+
+// lsx is the round transformation LSX[k]
+func lsx(k *block, a block) block {
+	a = xor(a, *k)
+	for i := range a {
+		a[i] = pi[a[i]]
+	}
+	return l(a)
+}
+
+// l is the linear transformation L, 16 iterations of R
+func l(a block) block {
+	for i := 0; i < BlockSize; i++ {
+		x := lin(&a)
+		copy(a[1:], a[:BlockSize-1])
+		a[0] = x
+	}
+	return a
+}
+
+// lInv is the inverse of L, 16 iterations of the inverse of R
+func lInv(a block) block {
+	for i := 0; i < BlockSize; i++ {
+		x := a[0]
+		copy(a[:], a[1:])
+		a[BlockSize-1] = x
+		a[BlockSize-1] = lin(&a)
+	}
+	return a
+}
+
+// lin is the linear function l of a15 to a0
+func lin(a *block) byte {
+	var res byte
+	for i, c := range lCoeffs {
+		res ^= gfMul(a[i], c)
+	}
+	return res
+}
+
+func xor(x, y block) block {
+	for i := range x {
+		x[i] ^= y[i]
+	}
+	return x
+}
+
+// gfMul multiplies a and b in GF(2^8) modulo x^8 + x^7 + x^6 + x + 1
+func gfMul(a, b byte) byte {
+	var res byte
+	for ; b != 0; b >>= 1 {
+		if b&1 == 1 {
+			res ^= a
+		}
+		hi := a & 0x80
+		a <<= 1
+		if hi != 0 {
+			a ^= 0xc3
+		}
+	}
+	return res
+}
+
+func invert(s *[256]byte) [256]byte {
+	var inv [256]byte
+	for x, y := range s {
+		inv[y] = byte(x)
+	}
+	return inv
+}
@@ -0,0 +1,58 @@
+package kuznyechik
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// GOST R 34.12-2015 Appendix A.1
+func TestVectors(t *testing.T) {
+	key, _ := hex.DecodeString("8899aabbccddeeff0011223344556677fedcba98765432100123456789abcdef")
+	pt, _ := hex.DecodeString("1122334455667700ffeeddccbbaa9988")
+	exp := "7f679d90bebc24305a468d42b9d4edcd"
+
+	c, err := New(key)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	res := make([]byte, BlockSize)
+	c.Encrypt(res, pt)
+	if resHex := hex.EncodeToString(res); resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+
+	c.Decrypt(res, res)
+	if resHex, exp := hex.EncodeToString(res), hex.EncodeToString(pt); resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+
+	k3 := "db31485315694343228d6aef8cc78c44"
+	if resHex := hex.EncodeToString(c.(*kuznyechik).k[2][:]); resHex != k3 {
+		t.Errorf("K3: Not equal %s!=%s", resHex, k3)
+	}
+}
+
+// GOST R 34.12-2015 Appendix A.1.2
+func TestL(t *testing.T) {
+	var a block
+	b, _ := hex.DecodeString("64a59400000000000000000000000000")
+	copy(a[:], b)
+
+	exp := "d456584dd0e3e84cc3166e4b7fa2890d"
+	a = l(a)
+	if resHex := hex.EncodeToString(a[:]); resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+
+	a = lInv(a)
+	if resHex := hex.EncodeToString(a[:]); resHex != hex.EncodeToString(b) {
+		t.Errorf("Not equal %s!=%x", resHex, b)
+	}
+}
+
+func TestKeySize(t *testing.T) {
+	if _, err := New(make([]byte, 16)); err == nil {
+		t.Error("invalid key size accepted")
+	}
+}
@@ -0,0 +1,129 @@
+package magma
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"log"
+	"math/bits"
+	"strconv"
+)
+
+// GOST R 34.12-2015, c.f. also RFC 8891
+// Magma is GOST 28147-89 with the S-boxes fixed by the standard.
+
+const BlockSize int = 8
+
+const KeySize int = 32
+
+// Substitutions Pi0 to Pi7, Pi_i applying to the nibble i from the least
+// significant one
+var pi = [8][16]byte{
+	{0xc, 0x4, 0x6, 0x2, 0xa, 0x5, 0xb, 0x9, 0xe, 0x8, 0xd, 0x7, 0x0, 0x3, 0xf, 0x1},
+	{0x6, 0x8, 0x2, 0x3, 0x9, 0xa, 0x5, 0xc, 0x1, 0xe, 0x4, 0x7, 0xb, 0xd, 0x0, 0xf},
+	{0xb, 0x3, 0x5, 0x8, 0x2, 0xf, 0xa, 0xd, 0xe, 0x1, 0x7, 0x4, 0xc, 0x9, 0x6, 0x0},
+	{0xc, 0x8, 0x2, 0x1, 0xd, 0x4, 0xf, 0x6, 0x7, 0x0, 0xa, 0x5, 0x3, 0xe, 0x9, 0xb},
+	{0x7, 0xf, 0x5, 0xa, 0x8, 0x1, 0x6, 0xd, 0x0, 0x9, 0x3, 0xe, 0xb, 0x4, 0x2, 0xc},
+	{0x5, 0xd, 0xf, 0x6, 0x9, 0x2, 0xc, 0xa, 0xb, 0x7, 0x8, 0x1, 0x4, 0x3, 0xe, 0x0},
+	{0x8, 0xe, 0x2, 0x5, 0x6, 0x9, 0x1, 0xc, 0xf, 0x4, 0xb, 0x0, 0xd, 0xa, 0x3, 0x7},
+	{0x1, 0x7, 0xe, 0xd, 0x0, 0x5, 0x8, 0x3, 0x4, 0xf, 0xa, 0x6, 0x9, 0xc, 0xb, 0x2},
+}
+
+// sbox[i][b] is t applied to the byte b at position i from the least
+// significant one, and zeros elsewhere
+var sbox = makeSBox()
+
+type magma struct {
+	k [8]uint32
+}
+
+// KeySizeError is returned by New when the key has an invalid size.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "magma: invalid key size " + strconv.Itoa(int(k))
+}
+
+// New creates a new Magma cipher.
+// key is 256 bits.
+func New(key []byte) (cipher.Block, error) {
+	if len(key) != KeySize {
+		return nil, KeySizeError(len(key))
+	}
+
+	m := new(magma)
+	for i := range m.k {
+		m.k[i] = binary.BigEndian.Uint32(key[4*i:])
+	}
+
+	return m, nil
+}
+
+func (m *magma) BlockSize() int {
+	return BlockSize
+}
+
+// The iteration keys are K1..K8 three times, then K8..K1
+
+func (m *magma) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	a1, a0 := binary.BigEndian.Uint32(src), binary.BigEndian.Uint32(src[4:])
+	for i := 0; i < 32; i++ {
+		k := m.k[i%8]
+		if i >= 24 {
+			k = m.k[31-i]
+		}
+		a1, a0 = a0, g(k, a0)^a1
+	}
+
+	// The last round G* does not swap the halves
+	binary.BigEndian.PutUint32(dst, a0)
+	binary.BigEndian.PutUint32(dst[4:], a1)
+}
+
+func (m *magma) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	a1, a0 := binary.BigEndian.Uint32(src), binary.BigEndian.Uint32(src[4:])
+	for i := 0; i < 32; i++ {
+		k := m.k[(31-i)%8]
+		if i < 8 {
+			k = m.k[i]
+		}
+		a1, a0 = a0, g(k, a0)^a1
+	}
+
+	binary.BigEndian.PutUint32(dst, a0)
+	binary.BigEndian.PutUint32(dst[4:], a1)
+}
+
+// g is the round transformation g[k]
+func g(k, a uint32) uint32 {
+	return bits.RotateLeft32(t(a+k), 11)
+}
+
+// t is the substitution of the 8 nibbles of a
+func t(a uint32) uint32 {
+	return sbox[0][a&0xff] | sbox[1][a>>8&0xff] | sbox[2][a>>16&0xff] | sbox[3][a>>24]
+}
+
+func makeSBox() [4][256]uint32 {
+	var s [4][256]uint32
+	for i := range s {
+		for b := 0; b < 256; b++ {
+			lo, hi := pi[2*i][b&0xf], pi[2*i+1][b>>4]
+			s[i][b] = uint32(hi<<4|lo) << (8 * i)
+		}
+	}
+	return s
+}
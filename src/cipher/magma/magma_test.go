@@ -0,0 +1,51 @@
+package magma
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// GOST R 34.12-2015 Appendix A.2
+func TestVectors(t *testing.T) {
+	key, _ := hex.DecodeString("ffeeddccbbaa99887766554433221100f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff")
+	pt, _ := hex.DecodeString("fedcba9876543210")
+	exp := "4ee901e5c2d8ca3d"
+
+	c, err := New(key)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	res := make([]byte, BlockSize)
+	c.Encrypt(res, pt)
+	if resHex := hex.EncodeToString(res); resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+
+	c.Decrypt(res, res)
+	if resHex, exp := hex.EncodeToString(res), hex.EncodeToString(pt); resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+}
+
+// GOST R 34.12-2015 Appendix A.2.1
+func TestT(tt *testing.T) {
+	vectors := [][2]uint32{
+		{0xfdb97531, 0x2a196f34},
+		{0x2a196f34, 0xebd9f03a},
+		{0xebd9f03a, 0xb039bb3d},
+		{0xb039bb3d, 0x68695433},
+	}
+
+	for _, v := range vectors {
+		if res := t(v[0]); res != v[1] {
+			tt.Errorf("Not equal %08x!=%08x", res, v[1])
+		}
+	}
+}
+
+func TestKeySize(t *testing.T) {
+	if _, err := New(make([]byte, 16)); err == nil {
+		t.Error("invalid key size accepted")
+	}
+}
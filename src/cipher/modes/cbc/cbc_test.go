@@ -80,7 +80,10 @@ func TestCBC(t *testing.T) {
 	key := make([]byte, 0)
 	key = binary.BigEndian.AppendUint64(key, 123)
 	
-	blockCipher := des.New(key)
+	blockCipher, err := des.New(key)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 
 	// ptxt
 	ptxt := make([]byte, 0)
@@ -0,0 +1,166 @@
+package present
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"log"
+	"strconv"
+)
+
+// PRESENT, c.f. "PRESENT: An Ultra-Lightweight Block Cipher" (Bogdanov,
+// Knudsen, Leander, Paar, Poschmann, Robshaw, Seurin, Vikkelsoe 2007)
+
+const BlockSize int = 8
+
+const rounds = 31
+
+var sbox = [16]uint8{0xc, 0x5, 0x6, 0xb, 0x9, 0x0, 0xa, 0xd, 0x3, 0xe, 0xf, 0x8, 0x4, 0x7, 0x1, 0x2}
+
+// spBox[j][b] is the S-box and permutation layers applied to the byte b at
+// position j, from the least significant one, and zeros elsewhere.
+// invPBox[j][b] is the inverse permutation of b at position j, and invS
+// the inverse S-box on the two nibbles of a byte.
+var spBox, invPBox, invS = makeTables()
+
+type present struct {
+	k [rounds + 1]uint64
+}
+
+// KeySizeError is returned by New when the key has an invalid size.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "present: invalid key size " + strconv.Itoa(int(k))
+}
+
+// New creates a new PRESENT cipher.
+// key is 80 or 128 bits.
+func New(key []byte) (cipher.Block, error) {
+	c := new(present)
+
+	switch len(key) {
+	case 10:
+		// Key register k79..k64 and k63..k0
+		hi, lo := uint64(binary.BigEndian.Uint16(key)), binary.BigEndian.Uint64(key[2:])
+		for i := 0; i <= rounds; i++ {
+			c.k[i] = hi<<48 | lo>>16
+
+			// Rotation left by 61 bits, the S-box on k79..k76 and the
+			// counter on k19..k15
+			low := lo & (1<<19 - 1)
+			hi, lo = low>>3, lo>>19|hi<<45|low<<61
+			hi = hi&0x0fff | uint64(sbox[hi>>12])<<12
+			lo ^= uint64(i+1) << 15
+		}
+	case 16:
+		hi, lo := binary.BigEndian.Uint64(key), binary.BigEndian.Uint64(key[8:])
+		for i := 0; i <= rounds; i++ {
+			c.k[i] = hi
+
+			// Rotation left by 61 bits, the S-box on k127..k124 and
+			// k123..k120 and the counter on k66..k62
+			hi, lo = hi<<61|lo>>3, lo<<61|hi>>3
+			top := uint8(hi >> 56)
+			hi = hi&(1<<56-1) | uint64(sbox[top>>4]<<4|sbox[top&0xf])<<56
+			hi ^= uint64(i+1) >> 2
+			lo ^= uint64(i+1) << 62
+		}
+	default:
+		return nil, KeySizeError(len(key))
+	}
+
+	return c, nil
+}
+
+func (c *present) BlockSize() int {
+	return BlockSize
+}
+
+func (c *present) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	x := binary.BigEndian.Uint64(src)
+	for i := 0; i < rounds; i++ {
+		x ^= c.k[i]
+
+		var y uint64
+		for j := 0; j < 8; j++ {
+			y |= spBox[j][x>>(8*j)&0xff]
+		}
+		x = y
+	}
+	binary.BigEndian.PutUint64(dst, x^c.k[rounds])
+}
+
+func (c *present) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	x := binary.BigEndian.Uint64(src)
+	for i := rounds; i > 0; i-- {
+		x ^= c.k[i]
+
+		var y uint64
+		for j := 0; j < 8; j++ {
+			y |= invPBox[j][x>>(8*j)&0xff]
+		}
+		x = 0
+		for j := 0; j < 8; j++ {
+			x |= uint64(invS[y>>(8*j)&0xff]) << (8 * j)
+		}
+	}
+	binary.BigEndian.PutUint64(dst, x^c.k[0])
+}
+
+// permute applies the permutation layer: bit i moves to bit 16i mod 63,
+// and bit 63 stays in place
+func permute(x uint64) uint64 {
+	var y uint64
+	for i := 0; i < 64; i++ {
+		p := 16 * i % 63
+		if i == 63 {
+			p = 63
+		}
+		y |= (x >> i & 1) << p
+	}
+	return y
+}
+
+// invPermute is the inverse of permute
+func invPermute(x uint64) uint64 {
+	var y uint64
+	for i := 0; i < 64; i++ {
+		p := 16 * i % 63
+		if i == 63 {
+			p = 63
+		}
+		y |= (x >> p & 1) << i
+	}
+	return y
+}
+
+func makeTables() (sp, invP [8][256]uint64, invS [256]uint8) {
+	var inv [16]uint8
+	for a, b := range sbox {
+		inv[b] = uint8(a)
+	}
+
+	for b := 0; b < 256; b++ {
+		s := uint64(sbox[b>>4]<<4 | sbox[b&0xf])
+		for j := 0; j < 8; j++ {
+			sp[j][b] = permute(s << (8 * j))
+			invP[j][b] = invPermute(uint64(b) << (8 * j))
+		}
+		invS[b] = inv[b>>4]<<4 | inv[b&0xf]
+	}
+	return sp, invP, invS
+}
@@ -0,0 +1,58 @@
+package present
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestVectors(t *testing.T) {
+	vectors := []struct {
+		keyHex, ptHex, ctHex string
+	}{
+		// Appendix of the paper
+		{"00000000000000000000", "0000000000000000", "5579c1387b228445"},
+		{"ffffffffffffffffffff", "0000000000000000", "e72c46c0f5945049"},
+		{"00000000000000000000", "ffffffffffffffff", "a112ffc72f68417b"},
+		{"ffffffffffffffffffff", "ffffffffffffffff", "3333dcd3213210d2"},
+		// PRESENT-128
+		{"00000000000000000000000000000000", "0000000000000000", "96db702a2e6900af"},
+		{"0123456789abcdef0123456789abcdef", "0123456789abcdef", "0e9d28685e671dd6"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		pt, _ := hex.DecodeString(v.ptHex)
+		c, err := New(key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, BlockSize)
+		c.Encrypt(res, pt)
+		if resHex := hex.EncodeToString(res); resHex != v.ctHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ctHex)
+		}
+
+		c.Decrypt(res, res)
+		if resHex := hex.EncodeToString(res); resHex != v.ptHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ptHex)
+		}
+	}
+}
+
+func TestPermute(t *testing.T) {
+	x := uint64(0x0123456789abcdef)
+	if y := invPermute(permute(x)); y != x {
+		t.Errorf("Not equal %016x!=%016x", y, x)
+	}
+	// Bit 1 moves to bit 16
+	if y := permute(2); y != 1<<16 {
+		t.Errorf("Not equal %016x!=%016x", y, 1<<16)
+	}
+}
+
+func TestKeySize(t *testing.T) {
+	if _, err := New(make([]byte, 8)); err == nil {
+		t.Error("invalid key size accepted")
+	}
+}
@@ -0,0 +1,63 @@
+package rc2
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// RFC 2268 Section 5
+func TestVectors(t *testing.T) {
+	vectors := []struct {
+		keyHex string
+		t1     int
+		ptHex  string
+		ctHex  string
+	}{
+		{"0000000000000000", 63, "0000000000000000", "ebb773f993278eff"},
+		{"ffffffffffffffff", 64, "ffffffffffffffff", "278b27e42e2f0d49"},
+		{"3000000000000000", 64, "1000000000000001", "30649edf9be7d2c2"},
+		{"88", 64, "0000000000000000", "61a8a244adacccf0"},
+		{"88bca90e90875a", 64, "0000000000000000", "6ccf4308974c267f"},
+		{"88bca90e90875a7f0f79c384627bafb2", 64, "0000000000000000", "1a807d272bbe5db1"},
+		{"88bca90e90875a7f0f79c384627bafb2", 128, "0000000000000000", "2269552ab0f85ca6"},
+		{"88bca90e90875a7f0f79c384627bafb216f80a6f85920584c42fceb0be255daf1e", 129, "0000000000000000", "5b78d3a43dfff1f1"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		pt, _ := hex.DecodeString(v.ptHex)
+		c, err := NewWithEffectiveKeyBits(key, v.t1)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, BlockSize)
+		c.Encrypt(res, pt)
+		if resHex := hex.EncodeToString(res); resHex != v.ctHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ctHex)
+		}
+
+		c.Decrypt(res, res)
+		if resHex := hex.EncodeToString(res); resHex != v.ptHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ptHex)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	// New uses all the key bits
+	key, _ := hex.DecodeString("88bca90e90875a7f0f79c384627bafb2")
+	c, _ := New(key)
+	res := make([]byte, BlockSize)
+	c.Encrypt(res, res)
+	if resHex, exp := hex.EncodeToString(res), "2269552ab0f85ca6"; resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+
+	if _, err := New(nil); err == nil {
+		t.Error("empty key accepted")
+	}
+	if _, err := NewWithEffectiveKeyBits(key, 0); err == nil {
+		t.Error("0 effective key bits accepted")
+	}
+}
@@ -0,0 +1,200 @@
+package rc5
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// RC5-w/r/b, c.f. "The RC5 Encryption Algorithm" (Rivest 1994) and RFC 2040
+
+// Default parameters of New, RC5-32/12/b
+const (
+	DefaultWordSize = 32
+	DefaultRounds   = 12
+)
+
+// Maximum key size, in bytes, and number of rounds
+const (
+	MaxKeySize = 255
+	MaxRounds  = 255
+)
+
+// Magic constants P_w and Q_w, from e and the golden ratio
+var magic = map[uint][2]uint64{
+	16: {0xb7e1, 0x9e37},
+	32: {0xb7e15163, 0x9e3779b9},
+	64: {0xb7e151628aed2a6b, 0x9e3779b97f4a7c15},
+}
+
+// Words of w bits are held in uint64, reduced modulo 2^w
+type rc5 struct {
+	s      []uint64
+	w      uint
+	mask   uint64
+	rounds int
+}
+
+// KeySizeError is returned by the constructors when the key has an
+// invalid size.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "rc5: invalid key size " + strconv.Itoa(int(k))
+}
+
+// ErrWordSize is returned by NewCipher when the word size is not 16, 32
+// or 64 bits.
+var ErrWordSize = errors.New("rc5: word size must be 16, 32 or 64 bits")
+
+// New creates a new RC5-32/12/b cipher, with 64 bits blocks.
+// key is at most 255 bytes.
+func New(key []byte) (cipher.Block, error) {
+	return NewCipher(key, DefaultWordSize, DefaultRounds)
+}
+
+// NewCipher creates a new RC5-w/r/b cipher, with words of wordSize bits,
+// blocks of 2 words and the given number of rounds.
+// key is at most 255 bytes.
+func NewCipher(key []byte, wordSize, rounds int) (cipher.Block, error) {
+	if len(key) > MaxKeySize {
+		return nil, KeySizeError(len(key))
+	}
+	pq, ok := magic[uint(wordSize)]
+	if !ok {
+		return nil, ErrWordSize
+	}
+	if rounds < 0 || rounds > MaxRounds {
+		return nil, fmt.Errorf("rc5: invalid number of rounds %d", rounds)
+	}
+
+	c := &rc5{
+		w:      uint(wordSize),
+		mask:   1<<wordSize - 1,
+		rounds: rounds,
+	}
+	if wordSize == 64 {
+		c.mask = ^uint64(0)
+	}
+
+	// Key as little endian words, at least one
+	u := wordSize / 8
+	l := make([]uint64, (len(key)+u-1)/u)
+	if len(l) == 0 {
+		l = make([]uint64, 1)
+	}
+	for i := len(key) - 1; i >= 0; i-- {
+		l[i/u] = l[i/u]<<8 | uint64(key[i])
+	}
+
+	c.s = make([]uint64, 2*rounds+2)
+	c.s[0] = pq[0]
+	for i := 1; i < len(c.s); i++ {
+		c.s[i] = (c.s[i-1] + pq[1]) & c.mask
+	}
+
+	mixKey(c.s, l, c.w)
+
+	return c, nil
+}
+
+// mixKey mixes the key words l into the subkeys s, with words of w bits.
+// It is shared by RC5 and RC6.
+func mixKey(s, l []uint64, w uint) {
+	mask := uint64(1)<<w - 1
+	if w == 64 {
+		mask = ^uint64(0)
+	}
+
+	n := len(s)
+	if len(l) > n {
+		n = len(l)
+	}
+
+	var a, b uint64
+	i, j := 0, 0
+	for k := 0; k < 3*n; k++ {
+		a = rotl((s[i]+a+b)&mask, 3, w, mask)
+		s[i] = a
+		b = rotl((l[j]+a+b)&mask, a+b, w, mask)
+		l[j] = b
+		i = (i + 1) % len(s)
+		j = (j + 1) % len(l)
+	}
+}
+
+func (c *rc5) BlockSize() int {
+	return int(c.w) / 4
+}
+
+func (c *rc5) Encrypt(dst, src []byte) {
+	bs := c.BlockSize()
+	if len(src) < bs {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < bs {
+		log.Panic("cipher: dst too short")
+	}
+
+	a := (c.load(src) + c.s[0]) & c.mask
+	b := (c.load(src[bs/2:]) + c.s[1]) & c.mask
+	for i := 1; i <= c.rounds; i++ {
+		a = (rotl(a^b, b, c.w, c.mask) + c.s[2*i]) & c.mask
+		b = (rotl(b^a, a, c.w, c.mask) + c.s[2*i+1]) & c.mask
+	}
+
+	c.store(dst, a)
+	c.store(dst[bs/2:], b)
+}
+
+func (c *rc5) Decrypt(dst, src []byte) {
+	bs := c.BlockSize()
+	if len(src) < bs {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < bs {
+		log.Panic("cipher: dst too short")
+	}
+
+	a, b := c.load(src), c.load(src[bs/2:])
+	for i := c.rounds; i >= 1; i-- {
+		b = rotr((b-c.s[2*i+1])&c.mask, a, c.w, c.mask) ^ a
+		a = rotr((a-c.s[2*i])&c.mask, b, c.w, c.mask) ^ b
+	}
+
+	c.store(dst, (a-c.s[0])&c.mask)
+	c.store(dst[bs/2:], (b-c.s[1])&c.mask)
+}
+
+// load reads a little endian word
+func (c *rc5) load(b []byte) uint64 {
+	var x uint64
+	for i := int(c.w/8) - 1; i >= 0; i-- {
+		x = x<<8 | uint64(b[i])
+	}
+	return x
+}
+
+// store writes a little endian word
+func (c *rc5) store(b []byte, x uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], x)
+	copy(b[:c.w/8], buf[:])
+}
+
+// rotl rotates the w bits word x left by n mod w bits
+func rotl(x, n uint64, w uint, mask uint64) uint64 {
+	k := uint(n % uint64(w))
+	if k == 0 {
+		return x
+	}
+	return (x<<k | x>>(w-k)) & mask
+}
+
+// rotr rotates the w bits word x right by n mod w bits
+func rotr(x, n uint64, w uint, mask uint64) uint64 {
+	return rotl(x, uint64(w)-n%uint64(w), w, mask)
+}
@@ -0,0 +1,64 @@
+package rc5
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestVectors(t *testing.T) {
+	// RC5-32/12/16 vectors from Rivest's paper, each plaintext being the
+	// previous ciphertext, and vectors of draft-krovetz-rc6-rc5-vectors for
+	// the other parameters
+	vectors := []struct {
+		keyHex           string
+		wordSize, rounds int
+		ptHex, ctHex     string
+	}{
+		{"00000000000000000000000000000000", 32, 12, "0000000000000000", "21a5dbee154b8f6d"},
+		{"915f4619be41b2516355a50110a9ce91", 32, 12, "21a5dbee154b8f6d", "f7c013ac5b2b8952"},
+		{"783348e75aeb0f2fd7b169bb8dc16787", 32, 12, "f7c013ac5b2b8952", "2f42b3b70369fc92"},
+		{"dc49db1375a5584f6485b413b5f12baf", 32, 12, "2f42b3b70369fc92", "65c178b284d197cc"},
+		{"5269f149d41ba0152497574d7f153125", 32, 12, "65c178b284d197cc", "eb44e415da319824"},
+		{"0001020304050607", 16, 16, "00010203", "23a8d72e"},
+		{"000102030405060708090a0b0c0d0e0f", 32, 20, "0001020304050607", "2a0edc0e9431ff73"},
+		{"000102030405060708090a0b0c0d0e0f1011121314151617", 64, 24, "000102030405060708090a0b0c0d0e0f", "a46772820edbce0235abea32ae7178da"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		pt, _ := hex.DecodeString(v.ptHex)
+		c, err := NewCipher(key, v.wordSize, v.rounds)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if c.BlockSize() != len(pt) {
+			t.Fatalf("Block size %d!=%d", c.BlockSize(), len(pt))
+		}
+
+		res := make([]byte, len(pt))
+		c.Encrypt(res, pt)
+		if resHex := hex.EncodeToString(res); resHex != v.ctHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ctHex)
+		}
+
+		c.Decrypt(res, res)
+		if resHex := hex.EncodeToString(res); resHex != v.ptHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ptHex)
+		}
+	}
+}
+
+func TestParameters(t *testing.T) {
+	if _, err := NewCipher(nil, 32, 12); err != nil {
+		t.Error(err.Error())
+	}
+	if _, err := NewCipher(nil, 24, 12); err != ErrWordSize {
+		t.Errorf("word size 24: %v", err)
+	}
+	if _, err := NewCipher(nil, 32, MaxRounds+1); err == nil {
+		t.Error("too many rounds accepted")
+	}
+	if _, err := New(make([]byte, MaxKeySize+1)); err == nil {
+		t.Error("long key accepted")
+	}
+}
@@ -0,0 +1,109 @@
+package rc5
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"log"
+	"math/bits"
+)
+
+// RC6-32/20/b, c.f. "The RC6 Block Cipher" (Rivest, Robshaw, Sidney, Yin
+// 1998)
+
+const RC6BlockSize int = 16
+
+const rc6Rounds = 20
+
+type rc6 struct {
+	s [2*rc6Rounds + 4]uint32
+}
+
+// NewRC6 creates a new RC6-32/20/b cipher, with 128 bits blocks.
+// key is at most 255 bytes, usually 128, 192 or 256 bits.
+func NewRC6(key []byte) (cipher.Block, error) {
+	if len(key) > MaxKeySize {
+		return nil, KeySizeError(len(key))
+	}
+
+	l := make([]uint64, (len(key)+3)/4)
+	if len(l) == 0 {
+		l = make([]uint64, 1)
+	}
+	for i := len(key) - 1; i >= 0; i-- {
+		l[i/4] = l[i/4]<<8 | uint64(key[i])
+	}
+
+	pq := magic[32]
+	s := make([]uint64, 2*rc6Rounds+4)
+	s[0] = pq[0]
+	for i := 1; i < len(s); i++ {
+		s[i] = (s[i-1] + pq[1]) & 0xffffffff
+	}
+
+	mixKey(s, l, 32)
+
+	c := new(rc6)
+	for i := range c.s {
+		c.s[i] = uint32(s[i])
+	}
+
+	return c, nil
+}
+
+func (c *rc6) BlockSize() int {
+	return RC6BlockSize
+}
+
+func (c *rc6) Encrypt(dst, src []byte) {
+	if len(src) < RC6BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < RC6BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	a := binary.LittleEndian.Uint32(src)
+	b := binary.LittleEndian.Uint32(src[4:]) + c.s[0]
+	cc := binary.LittleEndian.Uint32(src[8:])
+	d := binary.LittleEndian.Uint32(src[12:]) + c.s[1]
+
+	for i := 1; i <= rc6Rounds; i++ {
+		t := bits.RotateLeft32(b*(2*b+1), 5)
+		u := bits.RotateLeft32(d*(2*d+1), 5)
+		a = bits.RotateLeft32(a^t, int(u&31)) + c.s[2*i]
+		cc = bits.RotateLeft32(cc^u, int(t&31)) + c.s[2*i+1]
+		a, b, cc, d = b, cc, d, a
+	}
+
+	binary.LittleEndian.PutUint32(dst, a+c.s[2*rc6Rounds+2])
+	binary.LittleEndian.PutUint32(dst[4:], b)
+	binary.LittleEndian.PutUint32(dst[8:], cc+c.s[2*rc6Rounds+3])
+	binary.LittleEndian.PutUint32(dst[12:], d)
+}
+
+func (c *rc6) Decrypt(dst, src []byte) {
+	if len(src) < RC6BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < RC6BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	a := binary.LittleEndian.Uint32(src) - c.s[2*rc6Rounds+2]
+	b := binary.LittleEndian.Uint32(src[4:])
+	cc := binary.LittleEndian.Uint32(src[8:]) - c.s[2*rc6Rounds+3]
+	d := binary.LittleEndian.Uint32(src[12:])
+
+	for i := rc6Rounds; i >= 1; i-- {
+		a, b, cc, d = d, a, b, cc
+		t := bits.RotateLeft32(b*(2*b+1), 5)
+		u := bits.RotateLeft32(d*(2*d+1), 5)
+		cc = bits.RotateLeft32(cc-c.s[2*i+1], -int(t&31)) ^ u
+		a = bits.RotateLeft32(a-c.s[2*i], -int(u&31)) ^ t
+	}
+
+	binary.LittleEndian.PutUint32(dst, a)
+	binary.LittleEndian.PutUint32(dst[4:], b-c.s[0])
+	binary.LittleEndian.PutUint32(dst[8:], cc)
+	binary.LittleEndian.PutUint32(dst[12:], d-c.s[1])
+}
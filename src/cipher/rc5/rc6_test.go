@@ -0,0 +1,40 @@
+package rc5
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Vectors of the RC6 paper
+func TestRC6(t *testing.T) {
+	vectors := []struct {
+		keyHex, ptHex, ctHex string
+	}{
+		{"00000000000000000000000000000000", "00000000000000000000000000000000", "8fc3a53656b1f778c129df4e9848a41e"},
+		{"0123456789abcdef0112233445566778", "02132435465768798a9bacbdcedfe0f1", "524e192f4715c6231f51f6367ea43f18"},
+		{"000000000000000000000000000000000000000000000000", "00000000000000000000000000000000", "6cd61bcb190b30384e8a3f168690ae82"},
+		{"0123456789abcdef0112233445566778899aabbccddeeff0", "02132435465768798a9bacbdcedfe0f1", "688329d019e505041e52e92af95291d4"},
+		{"0000000000000000000000000000000000000000000000000000000000000000", "00000000000000000000000000000000", "8f5fbd0510d15fa893fa3fda6e857ec2"},
+		{"0123456789abcdef0112233445566778899aabbccddeeff01032547698badcfe", "02132435465768798a9bacbdcedfe0f1", "c8241816f0d7e48920ad16a1674e5d48"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		pt, _ := hex.DecodeString(v.ptHex)
+		c, err := NewRC6(key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, RC6BlockSize)
+		c.Encrypt(res, pt)
+		if resHex := hex.EncodeToString(res); resHex != v.ctHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ctHex)
+		}
+
+		c.Decrypt(res, res)
+		if resHex := hex.EncodeToString(res); resHex != v.ptHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ptHex)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package serpent
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"log"
+	"math/bits"
+	"strconv"
+)
+
+// Serpent in bitslice mode, c.f. "Serpent: A Proposal for the Advanced
+// Encryption Standard" (Anderson, Biham, Knudsen 1998)
+// The words are read from the bytes in little endian order, as in the
+// NESSIE test vectors.
+
+const BlockSize int = 16
+
+// Limits of the key size, in bytes. Keys shorter than 256 bits are padded.
+const (
+	MinKeySize = 1
+	MaxKeySize = 32
+)
+
+const (
+	rounds = 32
+	phi    = 0x9e3779b9
+)
+
+var sTables = [8][16]uint8{
+	{3, 8, 15, 1, 10, 6, 5, 11, 14, 13, 4, 2, 7, 0, 9, 12},
+	{15, 12, 2, 7, 9, 0, 5, 10, 1, 11, 14, 8, 6, 13, 3, 4},
+	{8, 6, 7, 9, 3, 12, 10, 15, 13, 1, 14, 4, 0, 11, 5, 2},
+	{0, 15, 11, 8, 12, 9, 6, 3, 13, 1, 2, 4, 10, 7, 5, 14},
+	{1, 15, 8, 3, 12, 0, 11, 6, 2, 5, 4, 10, 9, 14, 7, 13},
+	{15, 5, 2, 11, 4, 10, 9, 12, 0, 3, 14, 8, 13, 6, 7, 1},
+	{7, 2, 12, 5, 8, 4, 6, 11, 14, 9, 1, 15, 13, 3, 10, 0},
+	{1, 13, 15, 0, 14, 8, 2, 11, 7, 4, 12, 10, 9, 3, 5, 6},
+}
+
+// The S-boxes and their inverses in algebraic normal form
+var sboxes, invSBoxes = makeSBoxes()
+
+// sbox is an S-box in algebraic normal form: bit m of anf[o] is set if
+// the monomial of the inputs in the set m appears in output bit o.
+type sbox struct {
+	anf [4]uint16
+}
+
+type serpent struct {
+	// Round subkeys, the last one is only used after the last round
+	k [rounds + 1][4]uint32
+}
+
+// KeySizeError is returned by New when the key has an invalid size.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "serpent: invalid key size " + strconv.Itoa(int(k))
+}
+
+// New creates a new Serpent cipher.
+// key is between 1 and 32 bytes, usually 128, 192 or 256 bits.
+func New(key []byte) (cipher.Block, error) {
+	if len(key) < MinKeySize || len(key) > MaxKeySize {
+		return nil, KeySizeError(len(key))
+	}
+
+	// Padding with a one bit after the most significant bit of the key
+	var padded [MaxKeySize]byte
+	copy(padded[:], key)
+	if len(key) < MaxKeySize {
+		padded[len(key)] = 1
+	}
+
+	// Prekeys w_-8 to w_131
+	var w [8 + 4*(rounds+1)]uint32
+	for i := 0; i < 8; i++ {
+		w[i] = binary.LittleEndian.Uint32(padded[4*i:])
+	}
+	for i := 8; i < len(w); i++ {
+		x := w[i-8] ^ w[i-5] ^ w[i-3] ^ w[i-1] ^ phi ^ uint32(i-8)
+		w[i] = bits.RotateLeft32(x, 11)
+	}
+
+	s := new(serpent)
+	for i := range s.k {
+		var k [4]uint32
+		copy(k[:], w[8+4*i:])
+		s.k[i] = sboxes[(rounds+3-i)%8].apply(k)
+	}
+
+	return s, nil
+}
+
+func (s *serpent) BlockSize() int {
+	return BlockSize
+}
+
+func (s *serpent) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	x := load(src)
+	for i := 0; i < rounds; i++ {
+		xorKey(&x, &s.k[i])
+		x = sboxes[i%8].apply(x)
+		if i < rounds-1 {
+			x = linear(x)
+		}
+	}
+	xorKey(&x, &s.k[rounds])
+	store(dst, x)
+}
+
+func (s *serpent) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	x := load(src)
+	xorKey(&x, &s.k[rounds])
+	for i := rounds - 1; i >= 0; i-- {
+		if i < rounds-1 {
+			x = invLinear(x)
+		}
+		x = invSBoxes[i%8].apply(x)
+		xorKey(&x, &s.k[i])
+	}
+	store(dst, x)
+}
+
+func load(b []byte) [4]uint32 {
+	return [4]uint32{
+		binary.LittleEndian.Uint32(b),
+		binary.LittleEndian.Uint32(b[4:]),
+		binary.LittleEndian.Uint32(b[8:]),
+		binary.LittleEndian.Uint32(b[12:]),
+	}
+}
+
+func store(b []byte, x [4]uint32) {
+	for i, w := range x {
+		binary.LittleEndian.PutUint32(b[4*i:], w)
+	}
+}
+
+func xorKey(x, k *[4]uint32) {
+	for i := range x {
+		x[i] ^= k[i]
+	}
+}
+
+// apply applies the S-box to the 32 columns of x, bit j of x[i] being
+// bit i of the input of column j.
+func (s *sbox) apply(x [4]uint32) [4]uint32 {
+	// m[i] is the product of the inputs in the set i
+	var m [16]uint32
+	m[0] = 0xffffffff
+	for i := 1; i < 16; i++ {
+		low := bits.TrailingZeros(uint(i))
+		m[i] = m[i&(i-1)] & x[low]
+	}
+
+	var y [4]uint32
+	for o, anf := range s.anf {
+		for ; anf != 0; anf &= anf - 1 {
+			y[o] ^= m[bits.TrailingZeros16(anf)]
+		}
+	}
+	return y
+}
+
+// linear is the linear transformation LT
+func linear(x [4]uint32) [4]uint32 {
+	x0 := bits.RotateLeft32(x[0], 13)
+	x2 := bits.RotateLeft32(x[2], 3)
+	x1 := x[1] ^ x0 ^ x2
+	x3 := x[3] ^ x2 ^ x0<<3
+	x1 = bits.RotateLeft32(x1, 1)
+	x3 = bits.RotateLeft32(x3, 7)
+	x0 ^= x1 ^ x3
+	x2 ^= x3 ^ x1<<7
+	x0 = bits.RotateLeft32(x0, 5)
+	x2 = bits.RotateLeft32(x2, 22)
+
+	return [4]uint32{x0, x1, x2, x3}
+}
+
+// invLinear is the inverse of LT
+func invLinear(x [4]uint32) [4]uint32 {
+	x0, x1, x2, x3 := x[0], x[1], x[2], x[3]
+	x2 = bits.RotateLeft32(x2, -22)
+	x0 = bits.RotateLeft32(x0, -5)
+	x2 ^= x3 ^ x1<<7
+	x0 ^= x1 ^ x3
+	x3 = bits.RotateLeft32(x3, -7)
+	x1 = bits.RotateLeft32(x1, -1)
+	x3 ^= x2 ^ x0<<3
+	x1 ^= x0 ^ x2
+	x2 = bits.RotateLeft32(x2, -3)
+	x0 = bits.RotateLeft32(x0, -13)
+
+	return [4]uint32{x0, x1, x2, x3}
+}
+
+// makeSBoxes computes the algebraic normal forms of the S-boxes and of
+// their inverses
+func makeSBoxes() (s, inv [8]sbox) {
+	for n, table := range sTables {
+		var invTable [16]uint8
+		for a, b := range table {
+			invTable[b] = uint8(a)
+		}
+
+		s[n] = anf(&table)
+		inv[n] = anf(&invTable)
+	}
+	return s, inv
+}
+
+// anf computes the algebraic normal form of table with the Moebius
+// transform of its truth tables
+func anf(table *[16]uint8) sbox {
+	var s sbox
+	for o := range s.anf {
+		var f [16]uint8
+		for a, b := range table {
+			f[a] = b >> o & 1
+		}
+
+		for i := 0; i < 4; i++ {
+			for a := range f {
+				if a>>i&1 == 1 {
+					f[a] ^= f[a^1<<i]
+				}
+			}
+		}
+
+		for a, c := range f {
+			s.anf[o] |= uint16(c) << a
+		}
+	}
+	return s
+}
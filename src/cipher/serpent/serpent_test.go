@@ -0,0 +1,95 @@
+package serpent
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+// NESSIE test vectors, set 1 vector 0, set 2 vector 0 and set 3 vector 0
+func TestVectors(t *testing.T) {
+	vectors := []struct {
+		keyHex, ptHex, ctHex string
+	}{
+		{"80000000000000000000000000000000", "00000000000000000000000000000000", "264e5481eff42a4606abda06c0bfda3d"},
+		{"00000000000000000000000000000000", "80000000000000000000000000000000", "a3b35de7c358ddd82644678c64b8bcbb"},
+		{"00000000000000000000000000000000", "00000000000000000000000000000000", "3620b17ae6a993d09618b8768266bae9"},
+		{"800000000000000000000000000000000000000000000000", "00000000000000000000000000000000", "9e274ead9b737bb21efcfca548602689"},
+		{"8000000000000000000000000000000000000000000000000000000000000000", "00000000000000000000000000000000", "a223aa1288463c0e2be38ebd825616c0"},
+		{"0000000000000000000000000000000000000000000000000000000000000000", "00000000000000000000000000000000", "49672ba898d98df95019180445491089"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		pt, _ := hex.DecodeString(v.ptHex)
+		c, err := New(key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, BlockSize)
+		c.Encrypt(res, pt)
+		if resHex := hex.EncodeToString(res); resHex != v.ctHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ctHex)
+		}
+
+		c.Decrypt(res, res)
+		if resHex := hex.EncodeToString(res); resHex != v.ptHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ptHex)
+		}
+	}
+}
+
+func TestSBoxes(t *testing.T) {
+	// Column j evaluates the S-boxes on j mod 16
+	var x [4]uint32
+	for j := 0; j < 32; j++ {
+		for i := range x {
+			x[i] |= uint32(j%16>>i&1) << j
+		}
+	}
+
+	for n := range sboxes {
+		y := sboxes[n].apply(x)
+		z := invSBoxes[n].apply(y)
+		for j := 0; j < 32; j++ {
+			var res uint8
+			for i := range y {
+				res |= uint8(y[i]>>j&1) << i
+			}
+			if exp := sTables[n][j%16]; res != exp {
+				t.Errorf("S%d(%d) %d != %d", n, j%16, res, exp)
+			}
+		}
+		if z != x {
+			t.Errorf("S%d^-1(S%d(x)) != x", n, n)
+		}
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	for _, keyLen := range []int{5, 16, 24, 32} {
+		key := make([]byte, keyLen)
+		rand.Read(key)
+		m := make([]byte, BlockSize)
+		rand.Read(m)
+
+		c, _ := New(key)
+		res := make([]byte, BlockSize)
+		c.Encrypt(res, m)
+		c.Decrypt(res, res)
+		if !bytes.Equal(res, m) {
+			t.Errorf("%d: Not equal %x!=%x", keyLen, res, m)
+		}
+	}
+}
+
+func TestKeySize(t *testing.T) {
+	if _, err := New(nil); err == nil {
+		t.Error("empty key accepted")
+	}
+	if _, err := New(make([]byte, MaxKeySize+1)); err == nil {
+		t.Error("long key accepted")
+	}
+}
@@ -0,0 +1,165 @@
+package simon
+
+import (
+	"crypto/cipher"
+	"log"
+	"strconv"
+)
+
+// Simon, c.f. "The SIMON and SPECK Families of Lightweight Block Ciphers"
+// (Beaulieu, Shors, Smith, Treatman-Clark, Weeks, Wingers 2013)
+// Bytes are mapped to words as in "SIMON and SPECK Implementation Guide":
+// words are little endian, and the block (x, y) is stored as y || x.
+
+// The constant sequences z0 to z4, bit i of z_j being z[j]>>(61-i)&1
+var z = [5]uint64{
+	0x3e8958737d12b0e6, // 11111010001001010110000111001101111101000100101011000011100110
+	0x23be4c2d477c985a, // 10001110111110010011000010110101000111011111001001100001011010
+	0x2bdc0d262847e5b3, // 10101111011100000011010010011000101000010001111110010110110011
+	0x36eb19781229cd0f, // 11011011101011000110010111100000010010001010011100110100001111
+	0x3479ad88170ca4ef, // 11010001111001101011011000100000010111000011001010010011101111
+}
+
+// params are the number of rounds and the constant sequence for each
+// block size and key size, in bytes
+var params = map[[2]int][2]int{
+	{4, 8}:   {32, 0},
+	{6, 9}:   {36, 0},
+	{6, 12}:  {36, 1},
+	{8, 12}:  {42, 2},
+	{8, 16}:  {44, 3},
+	{12, 12}: {52, 2},
+	{12, 18}: {54, 3},
+	{16, 16}: {68, 2},
+	{16, 24}: {69, 3},
+	{16, 32}: {72, 4},
+}
+
+// Words of n bits are held in uint64, reduced modulo 2^n
+type simon struct {
+	k    []uint64
+	n    uint
+	mask uint64
+}
+
+// SizeError is returned by NewCipher when the block size and key size do
+// not match one of the variants of Simon.
+type SizeError [2]int
+
+func (s SizeError) Error() string {
+	return "simon: invalid block size " + strconv.Itoa(s[0]) + " and key size " + strconv.Itoa(s[1])
+}
+
+// New creates a new Simon128 cipher.
+// key is 128, 192 or 256 bits.
+func New(key []byte) (cipher.Block, error) {
+	return NewCipher(key, 16)
+}
+
+// NewCipher creates a new Simon cipher of blockSize bytes.
+// Block and key sizes, in bits, are 32/64, 48/72, 48/96, 64/96, 64/128,
+// 96/96, 96/144, 128/128, 128/192 and 128/256.
+func NewCipher(key []byte, blockSize int) (cipher.Block, error) {
+	p, ok := params[[2]int{blockSize, len(key)}]
+	if !ok {
+		return nil, SizeError{blockSize, len(key)}
+	}
+	rounds, zj := p[0], z[p[1]]
+
+	s := &simon{
+		n:    uint(4 * blockSize),
+		mask: 1<<(4*blockSize) - 1,
+	}
+	if blockSize == 16 {
+		s.mask = ^uint64(0)
+	}
+
+	// Key words (k_m-1, ..., k_0), k_0 being the first one
+	wordSize := blockSize / 2
+	m := len(key) / wordSize
+	s.k = make([]uint64, rounds)
+	for i := 0; i < m; i++ {
+		s.k[i] = load(key[wordSize*i:], wordSize)
+	}
+
+	c := s.mask ^ 3
+	for i := m; i < rounds; i++ {
+		tmp := s.rotr(s.k[i-1], 3)
+		if m == 4 {
+			tmp ^= s.k[i-3]
+		}
+		tmp ^= s.rotr(tmp, 1)
+		s.k[i] = c ^ s.k[i-m] ^ tmp ^ zj>>(61-(i-m)%62)&1
+	}
+
+	return s, nil
+}
+
+func (s *simon) BlockSize() int {
+	return int(s.n / 4)
+}
+
+func (s *simon) Encrypt(dst, src []byte) {
+	bs := s.BlockSize()
+	if len(src) < bs {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < bs {
+		log.Panic("cipher: dst too short")
+	}
+
+	y, x := load(src, bs/2), load(src[bs/2:], bs/2)
+	for _, k := range s.k {
+		x, y = y^s.f(x)^k, x
+	}
+
+	store(dst, y, bs/2)
+	store(dst[bs/2:], x, bs/2)
+}
+
+func (s *simon) Decrypt(dst, src []byte) {
+	bs := s.BlockSize()
+	if len(src) < bs {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < bs {
+		log.Panic("cipher: dst too short")
+	}
+
+	y, x := load(src, bs/2), load(src[bs/2:], bs/2)
+	for i := len(s.k) - 1; i >= 0; i-- {
+		x, y = y, x^s.f(y)^s.k[i]
+	}
+
+	store(dst, y, bs/2)
+	store(dst[bs/2:], x, bs/2)
+}
+
+// f is the round function
+func (s *simon) f(x uint64) uint64 {
+	return s.rotl(x, 1)&s.rotl(x, 8) ^ s.rotl(x, 2)
+}
+
+func (s *simon) rotl(x uint64, r uint) uint64 {
+	return (x<<r | x>>(s.n-r)) & s.mask
+}
+
+func (s *simon) rotr(x uint64, r uint) uint64 {
+	return (x>>r | x<<(s.n-r)) & s.mask
+}
+
+// load reads a little endian word of size bytes
+func load(b []byte, size int) uint64 {
+	var x uint64
+	for i := size - 1; i >= 0; i-- {
+		x = x<<8 | uint64(b[i])
+	}
+	return x
+}
+
+// store writes a little endian word of size bytes
+func store(b []byte, x uint64, size int) {
+	for i := 0; i < size; i++ {
+		b[i] = byte(x >> (8 * i))
+	}
+}
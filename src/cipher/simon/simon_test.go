@@ -0,0 +1,63 @@
+package simon
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// reversed decodes words written as in the paper, most significant word
+// and byte first, into their byte representation
+func reversed(s string) []byte {
+	b, _ := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+// Test vectors of the paper, Appendix B
+func TestVectors(t *testing.T) {
+	vectors := []struct {
+		key, pt, ct string
+	}{
+		{"1918 1110 0908 0100", "6565 6877", "c69b e9bb"},
+		{"121110 0a0908 020100", "612067 6e696c", "dae5ac 292cac"},
+		{"1a1918 121110 0a0908 020100", "726963 20646e", "6e06a5 acf156"},
+		{"13121110 0b0a0908 03020100", "6f722067 6e696c63", "5ca2e27f 111a8fc8"},
+		{"1b1a1918 13121110 0b0a0908 03020100", "656b696c 20646e75", "44c8fc20 b9dfa07a"},
+		{"0d0c0b0a0908 050403020100", "2072616c6c69 702065687420", "602807a462b4 69063d8ff082"},
+		{"151413121110 0d0c0b0a0908 050403020100", "746168742074 73756420666f", "ecad1c6c451e 3f59c5db1ae9"},
+		{"0f0e0d0c0b0a0908 0706050403020100", "6373656420737265 6c6c657661727420", "49681b1e1e54fe3f 65aa832af84e0bbc"},
+		{"1716151413121110 0f0e0d0c0b0a0908 0706050403020100", "206572656874206e 6568772065626972", "c4ac61effcdc0d4f 6c9c8d6e2597b85b"},
+		{"1f1e1d1c1b1a1918 1716151413121110 0f0e0d0c0b0a0908 0706050403020100", "74206e69206d6f6f 6d69732061207369", "8d2b5579afc8a3a0 3bf72a87efe7b868"},
+	}
+
+	for _, v := range vectors {
+		key, pt, ct := reversed(v.key), reversed(v.pt), reversed(v.ct)
+		c, err := NewCipher(key, len(pt))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, len(pt))
+		c.Encrypt(res, pt)
+		if resHex, exp := hex.EncodeToString(res), hex.EncodeToString(ct); resHex != exp {
+			t.Errorf("Simon%d/%d: Not equal %s!=%s", 8*len(pt), 8*len(key), resHex, exp)
+		}
+
+		c.Decrypt(res, res)
+		if resHex, exp := hex.EncodeToString(res), hex.EncodeToString(pt); resHex != exp {
+			t.Errorf("Simon%d/%d: Not equal %s!=%s", 8*len(pt), 8*len(key), resHex, exp)
+		}
+	}
+}
+
+func TestSize(t *testing.T) {
+	if _, err := NewCipher(make([]byte, 16), 8); err != nil {
+		t.Error(err.Error())
+	}
+	if _, err := NewCipher(make([]byte, 16), 4); err == nil {
+		t.Error("invalid key size accepted")
+	}
+}
@@ -0,0 +1,52 @@
+package sm4
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// GB/T 32907-2016 Appendix A
+func TestVectors(t *testing.T) {
+	key, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+	c, err := New(key)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	res := make([]byte, BlockSize)
+	copy(res, key)
+	c.Encrypt(res, res)
+	if resHex, exp := hex.EncodeToString(res), "681edf34d206965e86b3e94f536e4246"; resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+
+	c.Decrypt(res, res)
+	if resHex, exp := hex.EncodeToString(res), hex.EncodeToString(key); resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+
+	// 1000000 chained encryptions
+	copy(res, key)
+	for i := 0; i < 1000000; i++ {
+		c.Encrypt(res, res)
+	}
+	if resHex, exp := hex.EncodeToString(res), "595298c7c6fd271f0402f804c33d3f66"; resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+}
+
+func TestSBox(t *testing.T) {
+	var seen [256]bool
+	for _, b := range sbox {
+		if seen[b] {
+			t.Fatalf("S-box is not a permutation, %d repeated", b)
+		}
+		seen[b] = true
+	}
+}
+
+func TestKeySize(t *testing.T) {
+	if _, err := New(make([]byte, 24)); err == nil {
+		t.Error("invalid key size accepted")
+	}
+}
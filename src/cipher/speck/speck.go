@@ -0,0 +1,156 @@
+package speck
+
+import (
+	"crypto/cipher"
+	"log"
+	"strconv"
+)
+
+// Speck, c.f. "The SIMON and SPECK Families of Lightweight Block Ciphers"
+// (Beaulieu, Shors, Smith, Treatman-Clark, Weeks, Wingers 2013)
+// Bytes are mapped to words as in "SIMON and SPECK Implementation Guide":
+// words are little endian, and the block (x, y) is stored as y || x.
+
+// params are the number of rounds for each block size and key size, in
+// bytes
+var params = map[[2]int]int{
+	{4, 8}:   22,
+	{6, 9}:   22,
+	{6, 12}:  23,
+	{8, 12}:  26,
+	{8, 16}:  27,
+	{12, 12}: 28,
+	{12, 18}: 29,
+	{16, 16}: 32,
+	{16, 24}: 33,
+	{16, 32}: 34,
+}
+
+// Words of n bits are held in uint64, reduced modulo 2^n
+type speck struct {
+	k           []uint64
+	n           uint
+	mask        uint64
+	alpha, beta uint
+}
+
+// SizeError is returned by NewCipher when the block size and key size do
+// not match one of the variants of Speck.
+type SizeError [2]int
+
+func (s SizeError) Error() string {
+	return "speck: invalid block size " + strconv.Itoa(s[0]) + " and key size " + strconv.Itoa(s[1])
+}
+
+// New creates a new Speck128 cipher.
+// key is 128, 192 or 256 bits.
+func New(key []byte) (cipher.Block, error) {
+	return NewCipher(key, 16)
+}
+
+// NewCipher creates a new Speck cipher of blockSize bytes.
+// Block and key sizes, in bits, are 32/64, 48/72, 48/96, 64/96, 64/128,
+// 96/96, 96/144, 128/128, 128/192 and 128/256.
+func NewCipher(key []byte, blockSize int) (cipher.Block, error) {
+	rounds, ok := params[[2]int{blockSize, len(key)}]
+	if !ok {
+		return nil, SizeError{blockSize, len(key)}
+	}
+
+	s := &speck{
+		n:     uint(4 * blockSize),
+		mask:  1<<(4*blockSize) - 1,
+		alpha: 8,
+		beta:  3,
+	}
+	if blockSize == 16 {
+		s.mask = ^uint64(0)
+	}
+	if blockSize == 4 {
+		s.alpha, s.beta = 7, 2
+	}
+
+	// Key words (l_m-2, ..., l_0, k_0), k_0 being the first one
+	wordSize := blockSize / 2
+	m := len(key) / wordSize
+	l := make([]uint64, m-1, rounds+m-2)
+	for i := range l {
+		l[i] = load(key[wordSize*(i+1):], wordSize)
+	}
+
+	s.k = make([]uint64, rounds)
+	s.k[0] = load(key, wordSize)
+	for i := 0; i < rounds-1; i++ {
+		li := (s.k[i]+s.rotr(l[i], s.alpha))&s.mask ^ uint64(i)
+		l = append(l, li)
+		s.k[i+1] = s.rotl(s.k[i], s.beta) ^ li
+	}
+
+	return s, nil
+}
+
+func (s *speck) BlockSize() int {
+	return int(s.n / 4)
+}
+
+func (s *speck) Encrypt(dst, src []byte) {
+	bs := s.BlockSize()
+	if len(src) < bs {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < bs {
+		log.Panic("cipher: dst too short")
+	}
+
+	y, x := load(src, bs/2), load(src[bs/2:], bs/2)
+	for _, k := range s.k {
+		x = (s.rotr(x, s.alpha)+y)&s.mask ^ k
+		y = s.rotl(y, s.beta) ^ x
+	}
+
+	store(dst, y, bs/2)
+	store(dst[bs/2:], x, bs/2)
+}
+
+func (s *speck) Decrypt(dst, src []byte) {
+	bs := s.BlockSize()
+	if len(src) < bs {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < bs {
+		log.Panic("cipher: dst too short")
+	}
+
+	y, x := load(src, bs/2), load(src[bs/2:], bs/2)
+	for i := len(s.k) - 1; i >= 0; i-- {
+		y = s.rotr(y^x, s.beta)
+		x = s.rotl(((x^s.k[i])-y)&s.mask, s.alpha)
+	}
+
+	store(dst, y, bs/2)
+	store(dst[bs/2:], x, bs/2)
+}
+
+func (s *speck) rotl(x uint64, r uint) uint64 {
+	return (x<<r | x>>(s.n-r)) & s.mask
+}
+
+func (s *speck) rotr(x uint64, r uint) uint64 {
+	return (x>>r | x<<(s.n-r)) & s.mask
+}
+
+// load reads a little endian word of size bytes
+func load(b []byte, size int) uint64 {
+	var x uint64
+	for i := size - 1; i >= 0; i-- {
+		x = x<<8 | uint64(b[i])
+	}
+	return x
+}
+
+// store writes a little endian word of size bytes
+func store(b []byte, x uint64, size int) {
+	for i := 0; i < size; i++ {
+		b[i] = byte(x >> (8 * i))
+	}
+}
@@ -0,0 +1,75 @@
+package speck
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// reversed decodes words written as in the paper, most significant word
+// and byte first, into their byte representation
+func reversed(s string) []byte {
+	b, _ := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+// Test vectors of the paper, Appendix C
+func TestVectors(t *testing.T) {
+	vectors := []struct {
+		key, pt, ct string
+	}{
+		{"1918 1110 0908 0100", "6574 694c", "a868 42f2"},
+		{"121110 0a0908 020100", "20796c 6c6172", "c049a5 385adc"},
+		{"1a1918 121110 0a0908 020100", "6d2073 696874", "735e10 b6445d"},
+		{"13121110 0b0a0908 03020100", "74614620 736e6165", "9f7952ec 4175946c"},
+		{"1b1a1918 13121110 0b0a0908 03020100", "3b726574 7475432d", "8c6fa548 454e028b"},
+		{"0d0c0b0a0908 050403020100", "65776f68202c 656761737520", "9e4d09ab7178 62bdde8f79aa"},
+		{"151413121110 0d0c0b0a0908 050403020100", "656d6974206e 69202c726576", "2bf31072228a 7ae440252ee6"},
+		{"0f0e0d0c0b0a0908 0706050403020100", "6c61766975716520 7469206564616d20", "a65d985179783265 7860fedf5c570d18"},
+		{"1716151413121110 0f0e0d0c0b0a0908 0706050403020100", "7261482066656968 43206f7420746e65", "1be4cf3a13135566 f9bc185de03c1886"},
+		{"1f1e1d1c1b1a1918 1716151413121110 0f0e0d0c0b0a0908 0706050403020100", "65736f6874206e49 202e72656e6f6f70", "4109010405c0f53e 4eeeb48d9c188f43"},
+	}
+
+	for _, v := range vectors {
+		key, pt, ct := reversed(v.key), reversed(v.pt), reversed(v.ct)
+		c, err := NewCipher(key, len(pt))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, len(pt))
+		c.Encrypt(res, pt)
+		if resHex, exp := hex.EncodeToString(res), hex.EncodeToString(ct); resHex != exp {
+			t.Errorf("Speck%d/%d: Not equal %s!=%s", 8*len(pt), 8*len(key), resHex, exp)
+		}
+
+		c.Decrypt(res, res)
+		if resHex, exp := hex.EncodeToString(res), hex.EncodeToString(pt); resHex != exp {
+			t.Errorf("Speck%d/%d: Not equal %s!=%s", 8*len(pt), 8*len(key), resHex, exp)
+		}
+	}
+}
+
+// Speck128/128 with the bytes of the implementation guide
+func TestByteOrder(t *testing.T) {
+	key, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	c, _ := New(key)
+
+	res := make([]byte, 16)
+	c.Encrypt(res, []byte(" made it equival"))
+	if resHex, exp := hex.EncodeToString(res), "180d575cdffe60786532787951985da6"; resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+}
+
+func TestSize(t *testing.T) {
+	if _, err := NewCipher(make([]byte, 16), 8); err != nil {
+		t.Error(err.Error())
+	}
+	if _, err := NewCipher(make([]byte, 16), 4); err == nil {
+		t.Error("invalid key size accepted")
+	}
+}
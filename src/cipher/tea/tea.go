@@ -0,0 +1,101 @@
+package tea
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"log"
+	"strconv"
+)
+
+// TEA, c.f. "TEA, a Tiny Encryption Algorithm" (Wheeler, Needham 1994)
+// Blocks and keys are read as big endian words.
+
+const BlockSize int = 8
+
+const KeySize int = 16
+
+// Number of cycles, each of 2 Feistel rounds
+const cycles = 32
+
+const delta = 0x9e3779b9
+
+type tea struct {
+	k [4]uint32
+}
+
+// KeySizeError is returned by the constructors when the key has an
+// invalid size.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "tea: invalid key size " + strconv.Itoa(int(k))
+}
+
+// New creates a new TEA cipher.
+// key is 128 bits.
+// TEA has equivalent keys: flipping the most significant bits of k0 and
+// k1, or of k2 and k3, gives the same cipher.
+func New(key []byte) (cipher.Block, error) {
+	if len(key) != KeySize {
+		return nil, KeySizeError(len(key))
+	}
+
+	t := new(tea)
+	loadKey(&t.k, key)
+
+	return t, nil
+}
+
+func (t *tea) BlockSize() int {
+	return BlockSize
+}
+
+func (t *tea) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	v0, v1 := binary.BigEndian.Uint32(src), binary.BigEndian.Uint32(src[4:])
+	k := &t.k
+
+	var sum uint32
+	for i := 0; i < cycles; i++ {
+		sum += delta
+		v0 += ((v1 << 4) + k[0]) ^ (v1 + sum) ^ ((v1 >> 5) + k[1])
+		v1 += ((v0 << 4) + k[2]) ^ (v0 + sum) ^ ((v0 >> 5) + k[3])
+	}
+
+	binary.BigEndian.PutUint32(dst, v0)
+	binary.BigEndian.PutUint32(dst[4:], v1)
+}
+
+func (t *tea) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	v0, v1 := binary.BigEndian.Uint32(src), binary.BigEndian.Uint32(src[4:])
+	k := &t.k
+
+	sum := uint32(delta * cycles & 0xffffffff)
+	for i := 0; i < cycles; i++ {
+		v1 -= ((v0 << 4) + k[2]) ^ (v0 + sum) ^ ((v0 >> 5) + k[3])
+		v0 -= ((v1 << 4) + k[0]) ^ (v1 + sum) ^ ((v1 >> 5) + k[1])
+		sum -= delta
+	}
+
+	binary.BigEndian.PutUint32(dst, v0)
+	binary.BigEndian.PutUint32(dst[4:], v1)
+}
+
+func loadKey(k *[4]uint32, key []byte) {
+	for i := range k {
+		k[i] = binary.BigEndian.Uint32(key[4*i:])
+	}
+}
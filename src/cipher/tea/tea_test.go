@@ -0,0 +1,61 @@
+package tea
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestVectors(t *testing.T) {
+	vectors := []struct {
+		keyHex, ptHex, ctHex string
+	}{
+		{"00000000000000000000000000000000", "0000000000000000", "41ea3a0a94baa940"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		pt, _ := hex.DecodeString(v.ptHex)
+		c, err := New(key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, BlockSize)
+		c.Encrypt(res, pt)
+		if resHex := hex.EncodeToString(res); resHex != v.ctHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ctHex)
+		}
+
+		c.Decrypt(res, res)
+		if resHex := hex.EncodeToString(res); resHex != v.ptHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ptHex)
+		}
+	}
+}
+
+func TestEquivalentKeys(t *testing.T) {
+	key, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	equivalent, _ := hex.DecodeString("800102038405060708090a0b0c0d0e0f")
+	pt, _ := hex.DecodeString("4142434445464748")
+
+	c1, _ := New(key)
+	c2, _ := New(equivalent)
+	res1 := make([]byte, BlockSize)
+	res2 := make([]byte, BlockSize)
+	c1.Encrypt(res1, pt)
+	c2.Encrypt(res2, pt)
+	if res1Hex, res2Hex := hex.EncodeToString(res1), hex.EncodeToString(res2); res1Hex != res2Hex {
+		t.Errorf("Not equal %s!=%s", res1Hex, res2Hex)
+	}
+}
+
+func TestKeySize(t *testing.T) {
+	for _, size := range []int{0, 8, 15, 17, 32} {
+		if _, err := New(make([]byte, size)); err == nil {
+			t.Errorf("Key of %d bytes accepted", size)
+		}
+		if _, err := NewXTEA(make([]byte, size)); err == nil {
+			t.Errorf("XTEA key of %d bytes accepted", size)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package tea
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"log"
+)
+
+// XTEA, c.f. "Tea extensions" (Needham, Wheeler 1997)
+
+type xtea struct {
+	// Subkeys of each round, with sum added
+	k [2 * cycles]uint32
+}
+
+// NewXTEA creates a new XTEA cipher, with 64 rounds.
+// key is 128 bits.
+func NewXTEA(key []byte) (cipher.Block, error) {
+	if len(key) != KeySize {
+		return nil, KeySizeError(len(key))
+	}
+
+	var k [4]uint32
+	loadKey(&k, key)
+
+	x := new(xtea)
+	var sum uint32
+	for i := 0; i < cycles; i++ {
+		x.k[2*i] = sum + k[sum&3]
+		sum += delta
+		x.k[2*i+1] = sum + k[sum>>11&3]
+	}
+
+	return x, nil
+}
+
+func (x *xtea) BlockSize() int {
+	return BlockSize
+}
+
+func (x *xtea) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	v0, v1 := binary.BigEndian.Uint32(src), binary.BigEndian.Uint32(src[4:])
+	for i := 0; i < len(x.k); i += 2 {
+		v0 += ((v1<<4 ^ v1>>5) + v1) ^ x.k[i]
+		v1 += ((v0<<4 ^ v0>>5) + v0) ^ x.k[i+1]
+	}
+
+	binary.BigEndian.PutUint32(dst, v0)
+	binary.BigEndian.PutUint32(dst[4:], v1)
+}
+
+func (x *xtea) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	v0, v1 := binary.BigEndian.Uint32(src), binary.BigEndian.Uint32(src[4:])
+	for i := len(x.k) - 2; i >= 0; i -= 2 {
+		v1 -= ((v0<<4 ^ v0>>5) + v0) ^ x.k[i+1]
+		v0 -= ((v1<<4 ^ v1>>5) + v1) ^ x.k[i]
+	}
+
+	binary.BigEndian.PutUint32(dst, v0)
+	binary.BigEndian.PutUint32(dst[4:], v1)
+}
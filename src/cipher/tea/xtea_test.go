@@ -0,0 +1,39 @@
+package tea
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestXTEAVectors(t *testing.T) {
+	vectors := []struct {
+		keyHex, ptHex, ctHex string
+	}{
+		{"000102030405060708090a0b0c0d0e0f", "4142434445464748", "497df3d072612cb5"},
+		{"000102030405060708090a0b0c0d0e0f", "4141414141414141", "e78f2d13744341d8"},
+		{"000102030405060708090a0b0c0d0e0f", "5a5b6e278948d77f", "4141414141414141"},
+		{"00000000000000000000000000000000", "4142434445464748", "a0390589f8b8efa5"},
+		{"00000000000000000000000000000000", "4141414141414141", "ed23375a821a8c2d"},
+		{"00000000000000000000000000000000", "70e1225d6e4e7655", "4141414141414141"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		pt, _ := hex.DecodeString(v.ptHex)
+		c, err := NewXTEA(key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, BlockSize)
+		c.Encrypt(res, pt)
+		if resHex := hex.EncodeToString(res); resHex != v.ctHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ctHex)
+		}
+
+		c.Decrypt(res, res)
+		if resHex := hex.EncodeToString(res); resHex != v.ptHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ptHex)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package tea
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"log"
+	"strconv"
+)
+
+// XXTEA (Corrected Block TEA), c.f. "Correction to xtea" (Wheeler, Needham
+// 1998)
+// Contrary to TEA and XTEA, blocks and keys are read as little endian
+// words, as in most implementations.
+
+// Minimum block size of XXTEA, in bytes
+const MinXXTEABlockSize = 8
+
+type xxtea struct {
+	k [4]uint32
+	// Number of words of a block
+	n int
+}
+
+// BlockSizeError is returned by NewXXTEA when the block size is invalid.
+type BlockSizeError int
+
+func (b BlockSizeError) Error() string {
+	return "tea: invalid XXTEA block size " + strconv.Itoa(int(b))
+}
+
+// NewXXTEA creates a new XXTEA cipher on blocks of blockSize bytes.
+// key is 128 bits, blockSize is a multiple of 4 of at least 8 bytes.
+func NewXXTEA(key []byte, blockSize int) (cipher.Block, error) {
+	if len(key) != KeySize {
+		return nil, KeySizeError(len(key))
+	}
+	if blockSize < MinXXTEABlockSize || blockSize%4 != 0 {
+		return nil, BlockSizeError(blockSize)
+	}
+
+	x := &xxtea{n: blockSize / 4}
+	for i := range x.k {
+		x.k[i] = binary.LittleEndian.Uint32(key[4*i:])
+	}
+
+	return x, nil
+}
+
+func (x *xxtea) BlockSize() int {
+	return 4 * x.n
+}
+
+func (x *xxtea) Encrypt(dst, src []byte) {
+	if len(src) < x.BlockSize() {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < x.BlockSize() {
+		log.Panic("cipher: dst too short")
+	}
+
+	v := x.load(src)
+	n := x.n
+
+	var sum uint32
+	z := v[n-1]
+	for rounds := 6 + 52/n; rounds > 0; rounds-- {
+		sum += delta
+		e := int(sum >> 2 & 3)
+		for p := 0; p < n; p++ {
+			y := v[(p+1)%n]
+			v[p] += x.mx(sum, y, z, p, e)
+			z = v[p]
+		}
+	}
+
+	x.store(dst, v)
+}
+
+func (x *xxtea) Decrypt(dst, src []byte) {
+	if len(src) < x.BlockSize() {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < x.BlockSize() {
+		log.Panic("cipher: dst too short")
+	}
+
+	v := x.load(src)
+	n := x.n
+
+	rounds := 6 + 52/n
+	sum := uint32(rounds) * delta
+	y := v[0]
+	for ; rounds > 0; rounds-- {
+		e := int(sum >> 2 & 3)
+		for p := n - 1; p >= 0; p-- {
+			z := v[(p+n-1)%n]
+			v[p] -= x.mx(sum, y, z, p, e)
+			y = v[p]
+		}
+		sum -= delta
+	}
+
+	x.store(dst, v)
+}
+
+// mx is the mixing function MX
+func (x *xxtea) mx(sum, y, z uint32, p, e int) uint32 {
+	return ((z>>5 ^ y<<2) + (y>>3 ^ z<<4)) ^ ((sum ^ y) + (x.k[p&3^e] ^ z))
+}
+
+func (x *xxtea) load(b []byte) []uint32 {
+	v := make([]uint32, x.n)
+	for i := range v {
+		v[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+	return v
+}
+
+func (x *xxtea) store(b []byte, v []uint32) {
+	for i, w := range v {
+		binary.LittleEndian.PutUint32(b[4*i:], w)
+	}
+}
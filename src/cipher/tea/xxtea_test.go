@@ -0,0 +1,70 @@
+package tea
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+func TestXXTEAVectors(t *testing.T) {
+	// Words 053704ab 575d8c80 of the reference implementation
+	vectors := []struct {
+		keyHex, ptHex, ctHex string
+	}{
+		{"00000000000000000000000000000000", "0000000000000000", "ab043705808c5d57"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		pt, _ := hex.DecodeString(v.ptHex)
+		c, err := NewXXTEA(key, len(pt))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, len(pt))
+		c.Encrypt(res, pt)
+		if resHex := hex.EncodeToString(res); resHex != v.ctHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ctHex)
+		}
+
+		c.Decrypt(res, res)
+		if resHex := hex.EncodeToString(res); resHex != v.ptHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ptHex)
+		}
+	}
+}
+
+func TestXXTEADecrypt(t *testing.T) {
+	key := make([]byte, KeySize)
+	rand.Read(key)
+
+	for _, size := range []int{8, 12, 16, 20, 64, 212, 1024} {
+		c, err := NewXXTEA(key, size)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if c.BlockSize() != size {
+			t.Errorf("Not equal %d!=%d", c.BlockSize(), size)
+		}
+
+		m := make([]byte, size)
+		rand.Read(m)
+		res := make([]byte, size)
+		c.Encrypt(res, m)
+		c.Decrypt(res, res)
+		if !bytes.Equal(res, m) {
+			t.Errorf("Not equal %x!=%x", res, m)
+		}
+	}
+}
+
+func TestXXTEABlockSize(t *testing.T) {
+	key := make([]byte, KeySize)
+	for _, size := range []int{0, 4, 10, 17} {
+		if _, err := NewXXTEA(key, size); err == nil {
+			t.Errorf("Block of %d bytes accepted", size)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package twofish
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"log"
+	"math/bits"
+	"strconv"
+)
+
+// Twofish, c.f. "Twofish: A 128-Bit Block Cipher" (Schneier, Kelsey, Whiting,
+// Wagner, Hall, Ferguson 1998)
+
+const BlockSize int = 16
+
+// Reduction polynomials of the MDS and RS matrices
+const (
+	mdsPoly = 0x169
+	rsPoly  = 0x14d
+)
+
+// Nibble permutations of q0 and q1, c.f. 4.3.5
+var qt = [2][4][16]byte{
+	{
+		{0x8, 0x1, 0x7, 0xd, 0x6, 0xf, 0x3, 0x2, 0x0, 0xb, 0x5, 0x9, 0xe, 0xc, 0xa, 0x4},
+		{0xe, 0xc, 0xb, 0x8, 0x1, 0x2, 0x3, 0x5, 0xf, 0x4, 0xa, 0x6, 0x7, 0x0, 0x9, 0xd},
+		{0xb, 0xa, 0x5, 0xe, 0x6, 0xd, 0x9, 0x0, 0xc, 0x8, 0xf, 0x3, 0x2, 0x4, 0x7, 0x1},
+		{0xd, 0x7, 0xf, 0x4, 0x1, 0x2, 0x6, 0xe, 0x9, 0xb, 0x3, 0x0, 0x8, 0x5, 0xc, 0xa},
+	},
+	{
+		{0x2, 0x8, 0xb, 0xd, 0xf, 0x7, 0x6, 0xe, 0x3, 0x1, 0x9, 0x4, 0x0, 0xa, 0xc, 0x5},
+		{0x1, 0xe, 0x2, 0xb, 0x4, 0xc, 0x3, 0x7, 0x6, 0xd, 0xa, 0x5, 0xf, 0x9, 0x0, 0x8},
+		{0x4, 0xc, 0x7, 0x5, 0x1, 0x6, 0x9, 0xa, 0x0, 0xe, 0xd, 0x8, 0x2, 0xb, 0x3, 0xf},
+		{0xb, 0x9, 0x5, 0x1, 0xc, 0x3, 0xd, 0xe, 0x6, 0x4, 0x7, 0xf, 0x2, 0x0, 0x8, 0xa},
+	},
+}
+
+var q0, q1 = makeQ(0), makeQ(1)
+
+var mds = [4][4]byte{
+	{0x01, 0xef, 0x5b, 0x5b},
+	{0x5b, 0xef, 0xef, 0x01},
+	{0xef, 0x5b, 0x01, 0xef},
+	{0xef, 0x01, 0xef, 0x5b},
+}
+
+var rs = [4][8]byte{
+	{0x01, 0xa4, 0x55, 0x87, 0x5a, 0x58, 0xdb, 0x9e},
+	{0xa4, 0x56, 0x82, 0xf3, 0x1e, 0xc6, 0x68, 0xe5},
+	{0x02, 0xa1, 0xfc, 0xc1, 0x47, 0xae, 0x3d, 0x19},
+	{0xa4, 0x55, 0x87, 0x5a, 0x58, 0xdb, 0x9e, 0x03},
+}
+
+type twofish struct {
+	// Key dependent S-boxes combined with the columns of MDS, so that
+	// g(X) = s[0][x0] ^ s[1][x1] ^ s[2][x2] ^ s[3][x3]
+	s [4][256]uint32
+	// Whitening and round subkeys
+	k [40]uint32
+}
+
+// KeySizeError is returned by New when the key has an invalid size.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "twofish: invalid key size " + strconv.Itoa(int(k))
+}
+
+// New creates a new Twofish cipher.
+// key is 128, 192 or 256 bits.
+func New(key []byte) (cipher.Block, error) {
+	if len(key) != 16 && len(key) != 24 && len(key) != 32 {
+		return nil, KeySizeError(len(key))
+	}
+
+	k := len(key) / 8
+
+	// Me, Mo and S in the order used by h, c.f. 4.3
+	me := make([]uint32, k)
+	mo := make([]uint32, k)
+	s := make([]uint32, k)
+	for i := 0; i < k; i++ {
+		me[i] = binary.LittleEndian.Uint32(key[8*i:])
+		mo[i] = binary.LittleEndian.Uint32(key[8*i+4:])
+		s[k-1-i] = rsMul(key[8*i : 8*i+8])
+	}
+
+	t := new(twofish)
+
+	const rho = 0x01010101
+	for i := uint32(0); i < 20; i++ {
+		a := h(2*i*rho, me)
+		b := bits.RotateLeft32(h((2*i+1)*rho, mo), 8)
+		t.k[2*i] = a + b
+		t.k[2*i+1] = bits.RotateLeft32(a+2*b, 9)
+	}
+
+	for x := 0; x < 256; x++ {
+		b := byte(x)
+		y := qChain([4]byte{b, b, b, b}, s)
+		for j := 0; j < 4; j++ {
+			t.s[j][x] = mdsColumn(j, y[j])
+		}
+	}
+
+	return t, nil
+}
+
+func (t *twofish) BlockSize() int {
+	return BlockSize
+}
+
+func (t *twofish) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	// Input whitening
+	r0 := binary.LittleEndian.Uint32(src) ^ t.k[0]
+	r1 := binary.LittleEndian.Uint32(src[4:]) ^ t.k[1]
+	r2 := binary.LittleEndian.Uint32(src[8:]) ^ t.k[2]
+	r3 := binary.LittleEndian.Uint32(src[12:]) ^ t.k[3]
+
+	// Two rounds per iteration, so that the halves are swapped implicitly
+	for i := 0; i < 16; i += 2 {
+		f0, f1 := t.f(r0, r1, i)
+		r2 = bits.RotateLeft32(r2^f0, -1)
+		r3 = bits.RotateLeft32(r3, 1) ^ f1
+
+		f0, f1 = t.f(r2, r3, i+1)
+		r0 = bits.RotateLeft32(r0^f0, -1)
+		r1 = bits.RotateLeft32(r1, 1) ^ f1
+	}
+
+	// Undo the last swap, and output whitening
+	binary.LittleEndian.PutUint32(dst, r2^t.k[4])
+	binary.LittleEndian.PutUint32(dst[4:], r3^t.k[5])
+	binary.LittleEndian.PutUint32(dst[8:], r0^t.k[6])
+	binary.LittleEndian.PutUint32(dst[12:], r1^t.k[7])
+}
+
+func (t *twofish) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize {
+		log.Panic("cipher: src too short")
+	}
+	if len(dst) < BlockSize {
+		log.Panic("cipher: dst too short")
+	}
+
+	r2 := binary.LittleEndian.Uint32(src) ^ t.k[4]
+	r3 := binary.LittleEndian.Uint32(src[4:]) ^ t.k[5]
+	r0 := binary.LittleEndian.Uint32(src[8:]) ^ t.k[6]
+	r1 := binary.LittleEndian.Uint32(src[12:]) ^ t.k[7]
+
+	for i := 14; i >= 0; i -= 2 {
+		f0, f1 := t.f(r2, r3, i+1)
+		r0 = bits.RotateLeft32(r0, 1) ^ f0
+		r1 = bits.RotateLeft32(r1^f1, -1)
+
+		f0, f1 = t.f(r0, r1, i)
+		r2 = bits.RotateLeft32(r2, 1) ^ f0
+		r3 = bits.RotateLeft32(r3^f1, -1)
+	}
+
+	binary.LittleEndian.PutUint32(dst, r0^t.k[0])
+	binary.LittleEndian.PutUint32(dst[4:], r1^t.k[1])
+	binary.LittleEndian.PutUint32(dst[8:], r2^t.k[2])
+	binary.LittleEndian.PutUint32(dst[12:], r3^t.k[3])
+}
+
+// f is the round function F of round r
+func (t *twofish) f(r0, r1 uint32, r int) (uint32, uint32) {
+	t0 := t.g(r0)
+	t1 := t.g(bits.RotateLeft32(r1, 8))
+
+	return t0 + t1 + t.k[2*r+8], t0 + 2*t1 + t.k[2*r+9]
+}
+
+// g is h with the key dependent S-boxes
+func (t *twofish) g(x uint32) uint32 {
+	return t.s[0][x&0xff] ^ t.s[1][(x>>8)&0xff] ^ t.s[2][(x>>16)&0xff] ^ t.s[3][x>>24]
+}
+
+// h is the function of 4.3.2, with l of 2, 3 or 4 words
+func h(x uint32, l []uint32) uint32 {
+	var y [4]byte
+	binary.LittleEndian.PutUint32(y[:], x)
+	y = qChain(y, l)
+
+	return mdsColumn(0, y[0]) ^ mdsColumn(1, y[1]) ^ mdsColumn(2, y[2]) ^ mdsColumn(3, y[3])
+}
+
+// qChain applies the q permutations of h, interleaved with the xors of
+// the bytes of l
+func qChain(y [4]byte, l []uint32) [4]byte {
+	lb := func(i, j int) byte {
+		return byte(l[i] >> (8 * j))
+	}
+
+	if len(l) == 4 {
+		y[0] = q1[y[0]] ^ lb(3, 0)
+		y[1] = q0[y[1]] ^ lb(3, 1)
+		y[2] = q0[y[2]] ^ lb(3, 2)
+		y[3] = q1[y[3]] ^ lb(3, 3)
+	}
+	if len(l) >= 3 {
+		y[0] = q1[y[0]] ^ lb(2, 0)
+		y[1] = q1[y[1]] ^ lb(2, 1)
+		y[2] = q0[y[2]] ^ lb(2, 2)
+		y[3] = q0[y[3]] ^ lb(2, 3)
+	}
+
+	y[0] = q1[q0[q0[y[0]]^lb(1, 0)]^lb(0, 0)]
+	y[1] = q0[q0[q1[y[1]]^lb(1, 1)]^lb(0, 1)]
+	y[2] = q1[q1[q0[y[2]]^lb(1, 2)]^lb(0, 2)]
+	y[3] = q0[q1[q1[y[3]]^lb(1, 3)]^lb(0, 3)]
+
+	return y
+}
+
+// mdsColumn returns column j of MDS multiplied by b, as a little endian word
+func mdsColumn(j int, b byte) uint32 {
+	var res uint32
+	for i := 0; i < 4; i++ {
+		res |= uint32(gfMul(mds[i][j], b, mdsPoly)) << (8 * i)
+	}
+	return res
+}
+
+// rsMul returns RS times the 8 bytes m, as a little endian word
+func rsMul(m []byte) uint32 {
+	var res uint32
+	for i := 0; i < 4; i++ {
+		var s byte
+		for j := 0; j < 8; j++ {
+			s ^= gfMul(rs[i][j], m[j], rsPoly)
+		}
+		res |= uint32(s) << (8 * i)
+	}
+	return res
+}
+
+// gfMul multiplies a and b in GF(2^8) modulo poly
+func gfMul(a, b byte, poly uint) byte {
+	var res uint
+	x := uint(a)
+	for ; b != 0; b >>= 1 {
+		if b&1 == 1 {
+			res ^= x
+		}
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= poly
+		}
+	}
+	return byte(res)
+}
+
+// makeQ computes the permutation q0 or q1 from its nibble permutations,
+// c.f. 4.3.5
+func makeQ(n int) [256]byte {
+	ror4 := func(x byte) byte {
+		return (x>>1 | x<<3) & 0xf
+	}
+
+	var q [256]byte
+	for x := 0; x < 256; x++ {
+		a, b := byte(x>>4), byte(x&0xf)
+		for i := 0; i < 4; i += 2 {
+			a, b = a^b, a^ror4(b)^(a<<3)&0xf
+			a, b = qt[n][i][a], qt[n][i+1][b]
+		}
+		q[x] = b<<4 | a
+	}
+	return q
+}
@@ -0,0 +1,96 @@
+package twofish
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+func TestQ(t *testing.T) {
+	// First bytes of the tables of q0 and q1 in the reference implementation
+	if q0[0] != 0xa9 || q0[1] != 0x67 || q1[0] != 0x75 || q1[1] != 0xf3 {
+		t.Errorf("Wrong q %02x %02x %02x %02x", q0[0], q0[1], q1[0], q1[1])
+	}
+}
+
+// Schneier's reference vectors, c.f. ecb_tbl.txt
+func TestVectors(t *testing.T) {
+	vectors := []struct {
+		keyHex, ptHex, ctHex string
+	}{
+		{"00000000000000000000000000000000", "00000000000000000000000000000000", "9f589f5cf6122c32b6bfec2f2ae8c35a"},
+		{"0123456789abcdeffedcba98765432100011223344556677", "00000000000000000000000000000000", "cfd1d2e5a9be9cdf501f13b892bd2248"},
+		{"0123456789abcdeffedcba987654321000112233445566778899aabbccddeeff", "00000000000000000000000000000000", "37527be0052334b89f0cfccae87cfa20"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		pt, _ := hex.DecodeString(v.ptHex)
+		c, err := New(key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		res := make([]byte, BlockSize)
+		c.Encrypt(res, pt)
+		if resHex := hex.EncodeToString(res); resHex != v.ctHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ctHex)
+		}
+
+		c.Decrypt(res, res)
+		if resHex := hex.EncodeToString(res); resHex != v.ptHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.ptHex)
+		}
+	}
+}
+
+// The 49 chained encryptions of ecb_ival.txt: the next key is the
+// plaintext followed by the beginning of the key, and the next plaintext
+// the ciphertext.
+func TestChained(t *testing.T) {
+	exps := map[int]string{
+		16: "5d9d4eeffa9151575524f115815a12e0",
+		24: "e75449212beef9f4a390bd860a640941",
+		32: "37fe26ff1cf66175f5ddf4c33b97a205",
+	}
+
+	for keyLen, exp := range exps {
+		key := make([]byte, keyLen)
+		pt := make([]byte, BlockSize)
+		ct := make([]byte, BlockSize)
+
+		for i := 0; i < 49; i++ {
+			c, _ := New(key)
+			c.Encrypt(ct, pt)
+			copy(key[BlockSize:], key)
+			copy(key, pt)
+			copy(pt, ct)
+		}
+
+		if res := hex.EncodeToString(ct); res != exp {
+			t.Errorf("%d: Not equal %s!=%s", keyLen, res, exp)
+		}
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	m := make([]byte, BlockSize)
+	rand.Read(m)
+
+	c, _ := New(key)
+	res := make([]byte, BlockSize)
+	c.Encrypt(res, m)
+	c.Decrypt(res, res)
+	if !bytes.Equal(res, m) {
+		t.Errorf("Not equal %x!=%x", res, m)
+	}
+}
+
+func TestKeySize(t *testing.T) {
+	if _, err := New(make([]byte, 20)); err == nil {
+		t.Error("invalid key size accepted")
+	}
+}
@@ -0,0 +1,283 @@
+// Package blake2b implements the BLAKE2b hash function as defined in
+// RFC 7693 (https://datatracker.ietf.org/doc/html/rfc7693).
+package blake2b
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+)
+
+const BlockSize int = 128
+const Size int = 64
+const Size384 int = 48
+const Size256 int = 32
+
+// Marshaled state layout, compatible with golang.org/x/crypto/blake2b
+const (
+	magic         = "b2b"
+	marshaledSize = len(magic) + 8*8 + 2*8 + 1 + BlockSize + 1
+)
+
+type digest struct {
+	h [8]uint64
+	// Counter of bytes compressed (t in the RFC)
+	c      [2]uint64
+	size   int
+	block  [BlockSize]byte
+	offset int
+
+	key    [BlockSize]byte
+	keyLen int
+}
+
+var iv = [8]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+// Message word permutations, c.f. RFC7693 2.7
+var sigma = [10][16]byte{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+// g is the mixing function
+// c.f. RFC7693 3.1
+func g(v *[16]uint64, a, b, c, d int, x, y uint64) {
+	v[a] = v[a] + v[b] + x
+	v[d] = bits.RotateLeft64(v[d]^v[a], -32)
+	v[c] = v[c] + v[d]
+	v[b] = bits.RotateLeft64(v[b]^v[c], -24)
+	v[a] = v[a] + v[b] + y
+	v[d] = bits.RotateLeft64(v[d]^v[a], -16)
+	v[c] = v[c] + v[d]
+	v[b] = bits.RotateLeft64(v[b]^v[c], -63)
+}
+
+// compress is the compression function F
+// c.f. RFC7693 3.2
+func compress(h *[8]uint64, c [2]uint64, block []byte, final bool) {
+	var v [16]uint64
+	copy(v[:8], h[:])
+	copy(v[8:], iv[:])
+
+	v[12] ^= c[0]
+	v[13] ^= c[1]
+	if final {
+		v[14] = ^v[14]
+	}
+
+	var m [16]uint64
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint64(block[8*i:])
+	}
+
+	for i := 0; i < 12; i++ {
+		s := &sigma[i%10]
+		g(&v, 0, 4, 8, 12, m[s[0]], m[s[1]])
+		g(&v, 1, 5, 9, 13, m[s[2]], m[s[3]])
+		g(&v, 2, 6, 10, 14, m[s[4]], m[s[5]])
+		g(&v, 3, 7, 11, 15, m[s[6]], m[s[7]])
+		g(&v, 0, 5, 10, 15, m[s[8]], m[s[9]])
+		g(&v, 1, 6, 11, 12, m[s[10]], m[s[11]])
+		g(&v, 2, 7, 8, 13, m[s[12]], m[s[13]])
+		g(&v, 3, 4, 9, 14, m[s[14]], m[s[15]])
+	}
+
+	for i := range h {
+		h[i] ^= v[i] ^ v[i+8]
+	}
+}
+
+// FUNCTIONS
+
+func Sum512(data []byte) [Size]byte {
+	h, _ := New512(nil)
+	h.Write(data)
+	res := h.Sum(nil)
+
+	return ([Size]byte)(res[:])
+}
+
+func Sum384(data []byte) [Size384]byte {
+	h, _ := New384(nil)
+	h.Write(data)
+	res := h.Sum(nil)
+
+	return ([Size384]byte)(res[:])
+}
+
+func Sum256(data []byte) [Size256]byte {
+	h, _ := New256(nil)
+	h.Write(data)
+	res := h.Sum(nil)
+
+	return ([Size256]byte)(res[:])
+}
+
+// HASH
+
+// New returns a new BLAKE2b hash with a digest of size bytes (1 to 64).
+// A non-empty key of up to 64 bytes turns it into a MAC.
+func New(size int, key []byte) (hash.Hash, error) {
+	if size < 1 || size > Size {
+		return nil, errors.New("hash/blake2b: invalid hash size")
+	}
+	if len(key) > Size {
+		return nil, errors.New("hash/blake2b: invalid key size")
+	}
+
+	d := &digest{
+		size:   size,
+		keyLen: len(key),
+	}
+	copy(d.key[:], key)
+	d.Reset()
+
+	return d, nil
+}
+
+func New512(key []byte) (hash.Hash, error) {
+	return New(Size, key)
+}
+
+func New384(key []byte) (hash.Hash, error) {
+	return New(Size384, key)
+}
+
+func New256(key []byte) (hash.Hash, error) {
+	return New(Size256, key)
+}
+
+// increment adds n to the byte counter
+func (d *digest) increment(n uint64) {
+	var carry uint64
+	d.c[0], carry = bits.Add64(d.c[0], n, 0)
+	d.c[1] += carry
+}
+
+func (d *digest) Write(p []byte) (n int, err error) {
+	n = len(p)
+
+	for len(p) > 0 {
+		// The last block is compressed differently, so only compress a full
+		// block once we know more data follows
+		if d.offset == BlockSize {
+			d.increment(uint64(BlockSize))
+			compress(&d.h, d.c, d.block[:], false)
+			d.offset = 0
+		}
+
+		c := copy(d.block[d.offset:], p)
+		d.offset += c
+		p = p[c:]
+	}
+
+	return n, nil
+}
+
+func (d0 *digest) Sum(b []byte) []byte {
+	// Work on a copy so that the caller can keep writing
+	d := *d0
+
+	// Pad the last block with zeros
+	for i := d.offset; i < BlockSize; i++ {
+		d.block[i] = 0
+	}
+	d.increment(uint64(d.offset))
+	compress(&d.h, d.c, d.block[:], true)
+
+	res := make([]byte, Size)
+	for i, w := range d.h {
+		binary.LittleEndian.PutUint64(res[8*i:], w)
+	}
+
+	return append(b, res[:d.size]...)
+}
+
+func (d *digest) Reset() {
+	d.h = iv
+	// Parameter block: digest length, key length, fanout = depth = 1
+	d.h[0] ^= uint64(d.size) | uint64(d.keyLen)<<8 | 1<<16 | 1<<24
+	d.c = [2]uint64{}
+	d.offset = 0
+
+	// The key is padded to a full block and processed first
+	if d.keyLen > 0 {
+		d.block = d.key
+		d.offset = BlockSize
+	}
+}
+
+func (d *digest) Size() int {
+	return d.size
+}
+
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+// MarshalBinary encodes the current state of the hash so that it can be
+// resumed later with UnmarshalBinary.
+// The format is the same as the one used by golang.org/x/crypto/blake2b.
+// Keyed hashes cannot be marshaled, as that would leak the key.
+func (d *digest) MarshalBinary() ([]byte, error) {
+	if d.keyLen != 0 {
+		return nil, errors.New("hash/blake2b: cannot marshal MACs")
+	}
+
+	b := make([]byte, 0, marshaledSize)
+	b = append(b, magic...)
+	for _, w := range d.h {
+		b = binary.BigEndian.AppendUint64(b, w)
+	}
+	b = binary.BigEndian.AppendUint64(b, d.c[0])
+	b = binary.BigEndian.AppendUint64(b, d.c[1])
+	// Maximum value for size is 64
+	b = append(b, byte(d.size))
+	b = append(b, d.block[:]...)
+	b = append(b, byte(d.offset))
+
+	return b, nil
+}
+
+// UnmarshalBinary restores a state encoded by MarshalBinary.
+func (d *digest) UnmarshalBinary(b []byte) error {
+	if len(b) < len(magic) || string(b[:len(magic)]) != magic {
+		return errors.New("hash/blake2b: invalid hash state identifier")
+	}
+	if len(b) != marshaledSize {
+		return errors.New("hash/blake2b: invalid hash state size")
+	}
+
+	b = b[len(magic):]
+	for i := range d.h {
+		d.h[i] = binary.BigEndian.Uint64(b[8*i:])
+	}
+	b = b[8*8:]
+	d.c[0] = binary.BigEndian.Uint64(b)
+	d.c[1] = binary.BigEndian.Uint64(b[8:])
+	b = b[2*8:]
+
+	size, offset := int(b[0]), int(b[1+BlockSize])
+	if size < 1 || size > Size || offset > BlockSize {
+		return errors.New("hash/blake2b: invalid hash state")
+	}
+
+	d.size = size
+	copy(d.block[:], b[1:])
+	d.offset = offset
+	d.keyLen = 0
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package blake2b
+
+import (
+	"encoding"
+	"encoding/hex"
+	"testing"
+)
+
+func TestABC512(t *testing.T) {
+	// RFC 7693 appendix A
+	msg := []byte("abc")
+	expHex := "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"
+
+	res := Sum512(msg)
+	resHex := hex.EncodeToString(res[:])
+
+	if resHex != expHex {
+		t.Errorf("Not equal %s!=%s", resHex, expHex)
+	}
+}
+
+func TestEmpty512(t *testing.T) {
+	expHex := "786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce"
+
+	res := Sum512(nil)
+	resHex := hex.EncodeToString(res[:])
+
+	if resHex != expHex {
+		t.Errorf("Not equal %s!=%s", resHex, expHex)
+	}
+}
+
+func TestABC256(t *testing.T) {
+	msg := []byte("abc")
+	expHex := "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319"
+
+	res := Sum256(msg)
+	resHex := hex.EncodeToString(res[:])
+
+	if resHex != expHex {
+		t.Errorf("Not equal %s!=%s", resHex, expHex)
+	}
+}
+
+func TestABC384(t *testing.T) {
+	msg := []byte("abc")
+	expHex := "6f56a82c8e7ef526dfe182eb5212f7db9df1317e57815dbda46083fc30f54ee6c66ba83be64b302d7cba6ce15bb556f4"
+
+	res := Sum384(msg)
+	resHex := hex.EncodeToString(res[:])
+
+	if resHex != expHex {
+		t.Errorf("Not equal %s!=%s", resHex, expHex)
+	}
+}
+
+func TestKeyed(t *testing.T) {
+	// Vectors from the reference implementation (blake2b-kat.txt)
+	key := make([]byte, 64)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	msg := make([]byte, 255)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+
+	vectors := []struct {
+		msg    []byte
+		expHex string
+	}{
+		{nil, "10ebb67700b1868efb4417987acf4690ae9d972fb7a590c2f02871799aaa4786b5e996e8f0f4eb981fc214b005f42d2ff4233499391653df7aefcbc13fc51568"},
+		{msg, "142709d62e28fcccd0af97fad0f8465b971e82201dc51070faa0372aa43e92484be1c1e73ba10906d5d1853db6a4106e0a7bf9800d373d6dee2d46d62ef2a461"},
+	}
+
+	for _, v := range vectors {
+		h, err := New512(key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		h.Write(v.msg)
+		resHex := hex.EncodeToString(h.Sum(nil))
+
+		if resHex != v.expHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.expHex)
+		}
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	msg := make([]byte, 1000)
+	for i := range msg {
+		msg[i] = byte(i % 251)
+	}
+	expHex := "c11e1c0340bd7e5a1b275f1230c962fad215ecb1391486e74e31b960a2f2996381a5fad092da06841d5f26e38f6ecfeaf441acbcd1c2de61aef121e7927175f5"
+
+	for _, chunk := range []int{1, 7, 64, 127, 128, 129, 1000} {
+		h, _ := New512(nil)
+		for i := 0; i < len(msg); i += chunk {
+			end := i + chunk
+			if end > len(msg) {
+				end = len(msg)
+			}
+			h.Write(msg[i:end])
+			// Sum in the middle must not change the result
+			h.Sum(nil)
+		}
+
+		if resHex := hex.EncodeToString(h.Sum(nil)); resHex != expHex {
+			t.Errorf("chunk %d: Not equal %s!=%s", chunk, resHex, expHex)
+		}
+	}
+}
+
+func TestInvalidParameters(t *testing.T) {
+	if _, err := New(0, nil); err == nil {
+		t.Error("size 0 accepted")
+	}
+	if _, err := New(65, nil); err == nil {
+		t.Error("size 65 accepted")
+	}
+	if _, err := New(64, make([]byte, 65)); err == nil {
+		t.Error("key of 65 bytes accepted")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	msg := make([]byte, 1000)
+	for i := range msg {
+		msg[i] = byte(i % 251)
+	}
+	expHex := "c11e1c0340bd7e5a1b275f1230c962fad215ecb1391486e74e31b960a2f2996381a5fad092da06841d5f26e38f6ecfeaf441acbcd1c2de61aef121e7927175f5"
+
+	for split := 0; split <= len(msg); split += 61 {
+		h, _ := New512(nil)
+		h.Write(msg[:split])
+		state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		// Resume on a hash of another size, the size is part of the state
+		h, _ = New256(nil)
+		if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+			t.Fatal(err.Error())
+		}
+		h.Write(msg[split:])
+
+		if resHex := hex.EncodeToString(h.Sum(nil)); resHex != expHex {
+			t.Errorf("split %d: Not equal %s!=%s", split, resHex, expHex)
+		}
+	}
+
+	mac, _ := New512([]byte("key"))
+	if _, err := mac.(encoding.BinaryMarshaler).MarshalBinary(); err == nil {
+		t.Error("keyed hash marshaled")
+	}
+}
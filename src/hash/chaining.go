@@ -0,0 +1,21 @@
+package hash
+
+import (
+	"hash"
+)
+
+// Chaining is implemented by the Merkle-Damgård hashes of the library
+// (MD5, SHA-1, SHA-2), giving raw access to the chaining value of their
+// compression function, e.g. to key them as in NMAC.
+type Chaining interface {
+	hash.Hash
+	// ChainingValue returns the chaining value after the last complete
+	// block written, encoded in the byte order of the digest.
+	ChainingValue() []byte
+	// SetChainingValue resets the hash, with iv as initial chaining value
+	// instead of the standard one.
+	// iv must be ChainingSize bytes long, encoded as in ChainingValue.
+	SetChainingValue(iv []byte) error
+	// ChainingSize returns the length of the chaining value in bytes.
+	ChainingSize() int
+}
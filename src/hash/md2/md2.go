@@ -1,16 +1,33 @@
 package md2
 
 import (
+	"errors"
 	"hash"
 )
 
 type digest struct {
-	msg []byte
+	// MD buffer, c.f. RFC1319 3.3
+	x [48]byte
+	// Running checksum and its last byte
+	check [BlockSize]byte
+	l     byte
+	// Pending bytes which do not fill a block yet
+	buf [BlockSize]byte
+	nx  int
 }
 
 const BlockSize int = 16
 const Size int = 16
 
+// Marshaled state layout: magic, MD buffer, checksum, pending block and
+// number of pending bytes.
+// There is no crypto/md2 so the format is our own; the last byte of the
+// magic is the format version.
+const (
+	magic         = "md2\x01"
+	marshaledSize = len(magic) + 16 + BlockSize + BlockSize + 1
+)
+
 var piSubst = [...]byte{
 	41, 46, 67, 201, 162, 216, 124, 1, 61, 54, 84, 161, 236, 240, 6,
 	19, 98, 167, 5, 243, 192, 199, 115, 140, 152, 147, 43, 217, 188,
@@ -32,104 +49,54 @@ var piSubst = [...]byte{
 	31, 26, 219, 153, 141, 51, 159, 17, 131, 20,
 }
 
-// appendPadding appends the padding to the buffer
-// c.f. RFC1319 3.1
-func appendPadding(buf *[]byte) {
-	// length must be multiple of 16
-	// pad value i, i times
-	padLen := BlockSize - (len(*buf) % BlockSize)
-
-	padB := byte(padLen)
-
-	for i := 0; i < padLen; i++ {
-		*buf = append(*buf, padB)
-	}
-}
-
-// appendChecksum appends the checksum to the buffer
+// updateChecksum updates the running checksum with one 16B block
 // c.f. RFC1319 3.2
-func appendChecksum(buf *[]byte) {
-	N := len(*buf)
-	check := make([]byte, BlockSize)
-
-	// Clear checksum
-	for i := range check {
-		check[i] = 0
-	}
-
-	var L byte = 0
-
-	// Process each byte
-	for i := 0; i < N/BlockSize; i++ {
-		// Checksum block i
-		for j := 0; j < BlockSize; j++ {
-			c := (*buf)[i*16+j]
-			// Don't know where ^ check[j] is mentionned but we need it
-			check[j] = piSubst[c^L] ^ check[j]
-			L = check[j]
-		}
+func (d *digest) updateChecksum(block []byte) {
+	for j := 0; j < BlockSize; j++ {
+		c := block[j]
+		// Don't know where ^ check[j] is mentionned but we need it
+		d.check[j] = piSubst[c^d.l] ^ d.check[j]
+		d.l = d.check[j]
 	}
-
-	*buf = append(*buf, check...)
 }
 
-// processMessage computes the message digest
-// c.f. RFC1319 3.3-3.4
-func processMessage(buf *[]byte) [48]byte {
-	// Step 3
-	var X [48]byte
-	for i := range X {
-		X[i] = 0
+// processBlock updates the MD buffer with one 16B block
+// c.f. RFC1319 3.4
+func (d *digest) processBlock(block []byte) {
+	// Copy block into X
+	for j := 0; j < BlockSize; j++ {
+		d.x[16+j] = block[j]
+		d.x[32+j] = d.x[16+j] ^ d.x[j]
 	}
 
-	// Step 4
-	Nprime := len(*buf)
+	var t byte = 0
 
-	// Process each 16B block
-	for i := 0; i < Nprime/BlockSize; i++ {
-		// Copy block i into X
-		for j := 0; j < BlockSize; j++ {
-			X[16+j] = (*buf)[i*16+j]
-			X[32+j] = X[16+j] ^ X[j]
+	// Do 18 rounds
+	for j := 0; j < 18; j++ {
+		// Round j
+		for k := 0; k < 48; k++ {
+			d.x[k] = d.x[k] ^ piSubst[t]
+			t = d.x[k]
 		}
 
-		var t byte = 0
-
-		// Do 18 rounds
-		for j := 0; j < 18; j++ {
-			// Round j
-			for k := 0; k < 48; k++ {
-				X[k] = X[k] ^ piSubst[t]
-				t = X[k]
-			}
-
-			t = t + byte(j)
-		}
+		t = t + byte(j)
 	}
+}
 
-	return X
+// block processes a full 16B block of the message
+func (d *digest) block(block []byte) {
+	d.updateChecksum(block)
+	d.processBlock(block)
 }
 
 // Sum computes a hash of data using the Sum algorithm as defined
 // in RFC 1319 (https://datatracker.ietf.org/doc/html/rfc1319)
 func Sum(data []byte) [Size]byte {
-	res := make([]byte, len(data))
-	copy(res, data)
-	// fmt.Println(hex.EncodeToString(res))
+	h := New()
+	h.Write(data)
+	res := h.Sum(nil)
 
-	// Step 1: append padding
-	appendPadding(&res)
-	// fmt.Println(hex.EncodeToString(res))
-
-	// Step 2: append checksum
-	appendChecksum(&res)
-	// fmt.Println(hex.EncodeToString(res))
-
-	// Step 3 & 4: initialize MD buffer & process message in 16B blocks
-	X := processMessage(&res)
-	// fmt.Println(hex.EncodeToString(X))
-
-	return ([BlockSize]byte)(X[:BlockSize])
+	return ([Size]byte)(res[:])
 }
 
 // Implement Hash
@@ -141,18 +108,62 @@ func New() hash.Hash {
 }
 
 func (d *digest) Write(p []byte) (n int, err error) {
-	// Just append to the already written data
-	d.msg = append(d.msg, p...)
-	return
+	n = len(p)
+
+	// Fill the pending block first
+	if d.nx > 0 {
+		c := copy(d.buf[d.nx:], p)
+		d.nx += c
+		p = p[c:]
+
+		if d.nx < BlockSize {
+			return n, nil
+		}
+
+		d.block(d.buf[:])
+		d.nx = 0
+	}
+
+	// Process full blocks straight from p
+	for len(p) >= BlockSize {
+		d.block(p[:BlockSize])
+		p = p[BlockSize:]
+	}
+
+	// Keep the rest for later
+	d.nx = copy(d.buf[:], p)
+
+	return n, nil
 }
 
 func (d *digest) Reset() {
-	d.msg = nil
+	d.x = [48]byte{}
+	d.check = [BlockSize]byte{}
+	d.l = 0
+	d.buf = [BlockSize]byte{}
+	d.nx = 0
 }
 
 func (d *digest) Sum(b []byte) []byte {
-	h := Sum(d.msg)
-	return append(b, h[:]...)
+	// Work on a copy so that the caller can keep writing
+	d0 := *d
+
+	// Step 1: append padding
+	// length must be multiple of 16, pad value i, i times
+	// c.f. RFC1319 3.1
+	padLen := BlockSize - d0.nx
+	pad := make([]byte, padLen)
+	for i := range pad {
+		pad[i] = byte(padLen)
+	}
+	d0.Write(pad)
+
+	// Step 2: append checksum
+	// The checksum is not part of itself, so only process it
+	check := d0.check
+	d0.processBlock(check[:])
+
+	return append(b, d0.x[:Size]...)
 }
 
 func (d *digest) Size() int {
@@ -162,3 +173,44 @@ func (d *digest) Size() int {
 func (d *digest) BlockSize() int {
 	return BlockSize
 }
+
+// MarshalBinary encodes the current state of the hash so that it can be
+// resumed later with UnmarshalBinary.
+func (d *digest) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, marshaledSize)
+	b = append(b, magic...)
+	// Only the first 16 bytes of X are kept between blocks
+	b = append(b, d.x[:16]...)
+	b = append(b, d.check[:]...)
+	b = append(b, d.buf[:]...)
+	b = append(b, byte(d.nx))
+
+	return b, nil
+}
+
+// UnmarshalBinary restores a state encoded by MarshalBinary.
+func (d *digest) UnmarshalBinary(b []byte) error {
+	if len(b) < len(magic) || string(b[:len(magic)]) != magic {
+		return errors.New("hash/md2: invalid hash state identifier")
+	}
+	if len(b) != marshaledSize {
+		return errors.New("hash/md2: invalid hash state size")
+	}
+	if int(b[marshaledSize-1]) >= BlockSize {
+		return errors.New("hash/md2: invalid hash state")
+	}
+
+	d.Reset()
+	b = b[len(magic):]
+	copy(d.x[:16], b)
+	b = b[16:]
+	copy(d.check[:], b)
+	b = b[BlockSize:]
+	copy(d.buf[:], b)
+	d.nx = int(b[BlockSize])
+
+	// L is the last checksum byte written
+	d.l = d.check[BlockSize-1]
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package md2
 
 import (
+	"encoding"
 	"encoding/hex"
 	"testing"
 )
@@ -74,3 +75,78 @@ func TestRFC(t *testing.T) {
 		}
 	}
 }
+
+func TestStreaming(t *testing.T) {
+	msg := []byte("12345678901234567890123456789012345678901234567890123456789012345678901234567890")
+	expHex := "d5976f79d83d3a0dc9806c3c66f3efd8"
+
+	// Write the message in chunks of every size
+	for chunk := 1; chunk <= len(msg); chunk++ {
+		h := New()
+		for i := 0; i < len(msg); i += chunk {
+			end := i + chunk
+			if end > len(msg) {
+				end = len(msg)
+			}
+			h.Write(msg[i:end])
+		}
+
+		resHex := hex.EncodeToString(h.Sum(nil))
+
+		if resHex != expHex {
+			t.Errorf("chunk %d: %s != %s", chunk, resHex, expHex)
+		}
+	}
+}
+
+func TestSumTwice(t *testing.T) {
+	h := New()
+	h.Write([]byte("message "))
+	h.Sum(nil)
+	h.Write([]byte("digest"))
+
+	resHex := hex.EncodeToString(h.Sum(nil))
+	expHex := "ab4f496bfb2a530b219ff33031fe06b0"
+
+	if resHex != expHex {
+		t.Errorf("Not equal %s != %s", resHex, expHex)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	msg := []byte("12345678901234567890123456789012345678901234567890123456789012345678901234567890")
+	expHex := "d5976f79d83d3a0dc9806c3c66f3efd8"
+
+	for split := 0; split <= len(msg); split++ {
+		h := New()
+		h.Write(msg[:split])
+		state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		// Resume on a fresh hash
+		h = New()
+		if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+			t.Fatal(err.Error())
+		}
+		h.Write(msg[split:])
+
+		resHex := hex.EncodeToString(h.Sum(nil))
+
+		if resHex != expHex {
+			t.Errorf("split %d: %s != %s", split, resHex, expHex)
+		}
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	h := New().(encoding.BinaryUnmarshaler)
+
+	if err := h.UnmarshalBinary([]byte("sha\x01")); err == nil {
+		t.Error("wrong identifier accepted")
+	}
+	if err := h.UnmarshalBinary([]byte("md2\x01")); err == nil {
+		t.Error("wrong size accepted")
+	}
+}
@@ -0,0 +1,249 @@
+// Package md5 implements the MD5 hash function as defined in RFC 1321
+// (https://datatracker.ietf.org/doc/html/rfc1321).
+//
+// MD5 is broken and must not be used for new designs, it is provided for
+// legacy formats such as MD5-crypt.
+package md5
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+)
+
+type digest struct {
+	s   [4]uint32
+	buf [BlockSize]byte
+	nx  int
+	// Length of the message in bytes
+	len uint64
+}
+
+const BlockSize int = 64
+const Size int = 16
+
+// Marshaled state layout, compatible with crypto/md5
+const (
+	magic         = "md5\x01"
+	marshaledSize = len(magic) + 4*4 + BlockSize + 8
+)
+
+// Constants T[i] = floor(2^32 * abs(sin(i)))
+// c.f. RFC1321 3.4
+var t = [64]uint32{
+	0xd76aa478, 0xe8c7b756, 0x242070db, 0xc1bdceee, 0xf57c0faf, 0x4787c62a, 0xa8304613, 0xfd469501,
+	0x698098d8, 0x8b44f7af, 0xffff5bb1, 0x895cd7be, 0x6b901122, 0xfd987193, 0xa679438e, 0x49b40821,
+	0xf61e2562, 0xc040b340, 0x265e5a51, 0xe9b6c7aa, 0xd62f105d, 0x02441453, 0xd8a1e681, 0xe7d3fbc8,
+	0x21e1cde6, 0xc33707d6, 0xf4d50d87, 0x455a14ed, 0xa9e3e905, 0xfcefa3f8, 0x676f02d9, 0x8d2a4c8a,
+	0xfffa3942, 0x8771f681, 0x6d9d6122, 0xfde5380c, 0xa4beea44, 0x4bdecfa9, 0xf6bb4b60, 0xbebfbc70,
+	0x289b7ec6, 0xeaa127fa, 0xd4ef3085, 0x04881d05, 0xd9d4d039, 0xe6db99e5, 0x1fa27cf8, 0xc4ac5665,
+	0xf4292244, 0x432aff97, 0xab9423a7, 0xfc93a039, 0x655b59c3, 0x8f0ccc92, 0xffeff47d, 0x85845dd1,
+	0x6fa87e4f, 0xfe2ce6e0, 0xa3014314, 0x4e0811a1, 0xf7537e82, 0xbd3af235, 0x2ad7d2bb, 0xeb86d391,
+}
+
+// Shift amounts of each round
+var shifts = [4][4]int{
+	{7, 12, 17, 22},
+	{5, 9, 14, 20},
+	{4, 11, 16, 23},
+	{6, 10, 15, 21},
+}
+
+// block processes one 64B block
+// c.f. RFC1321 3.4
+func (d *digest) block(p []byte) {
+	var x [16]uint32
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(p[4*i:])
+	}
+
+	a, b, c, dd := d.s[0], d.s[1], d.s[2], d.s[3]
+
+	for i := 0; i < 64; i++ {
+		var f uint32
+		var k int
+
+		switch i / 16 {
+		case 0:
+			// F(X,Y,Z) = XY v not(X) Z
+			f = (b & c) | (^b & dd)
+			k = i
+		case 1:
+			// G(X,Y,Z) = XZ v Y not(Z)
+			f = (b & dd) | (c & ^dd)
+			k = (5*i + 1) % 16
+		case 2:
+			// H(X,Y,Z) = X xor Y xor Z
+			f = b ^ c ^ dd
+			k = (3*i + 5) % 16
+		default:
+			// I(X,Y,Z) = Y xor (X v not(Z))
+			f = c ^ (b | ^dd)
+			k = (7 * i) % 16
+		}
+
+		// a = b + ((a + F(b,c,d) + X[k] + T[i]) <<< s)
+		a, dd, c, b = dd, c, b, b+bits.RotateLeft32(a+f+x[k]+t[i], shifts[i/16][i%4])
+	}
+
+	d.s[0] += a
+	d.s[1] += b
+	d.s[2] += c
+	d.s[3] += dd
+}
+
+// FUNCTIONS
+
+func Sum(data []byte) [Size]byte {
+	h := New()
+	h.Write(data)
+	res := h.Sum(nil)
+
+	return ([Size]byte)(res[:])
+}
+
+// HASH
+
+func New() hash.Hash {
+	d := new(digest)
+	d.Reset()
+	return d
+}
+
+func (d *digest) Write(p []byte) (n int, err error) {
+	n = len(p)
+	d.len += uint64(n)
+
+	// Fill the pending block first
+	if d.nx > 0 {
+		c := copy(d.buf[d.nx:], p)
+		d.nx += c
+		p = p[c:]
+
+		if d.nx < BlockSize {
+			return n, nil
+		}
+
+		d.block(d.buf[:])
+		d.nx = 0
+	}
+
+	// Process full blocks straight from p
+	for len(p) >= BlockSize {
+		d.block(p[:BlockSize])
+		p = p[BlockSize:]
+	}
+
+	// Keep the rest for later
+	d.nx = copy(d.buf[:], p)
+
+	return n, nil
+}
+
+func (d0 *digest) Sum(b []byte) []byte {
+	// Work on a copy so that the caller can keep writing
+	d := *d0
+	l := d.len
+
+	// Append "1" + "0"*k so that the length is 56 mod 64, then the length
+	// in bits
+	// c.f. RFC1321 3.1 and 3.2
+	pad := make([]byte, 1, BlockSize+8)
+	pad[0] = 0x80
+	pad = append(pad, make([]byte, (55-l)%uint64(BlockSize))...)
+	pad = binary.LittleEndian.AppendUint64(pad, l<<3)
+	d.Write(pad)
+
+	res := make([]byte, 0, Size)
+	for _, w := range d.s {
+		res = binary.LittleEndian.AppendUint32(res, w)
+	}
+
+	return append(b, res...)
+}
+
+func (d *digest) Reset() {
+	d.s = [4]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476}
+	d.nx = 0
+	d.len = 0
+}
+
+func (d *digest) Size() int {
+	return Size
+}
+
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+// MarshalBinary encodes the current state of the hash so that it can be
+// resumed later with UnmarshalBinary.
+// The format is the same as the one used by crypto/md5.
+func (d *digest) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, marshaledSize)
+	b = append(b, magic...)
+	for _, w := range d.s {
+		b = binary.BigEndian.AppendUint32(b, w)
+	}
+
+	// Pending bytes, padded with zeros to a full block
+	b = append(b, d.buf[:d.nx]...)
+	b = b[:len(b)+BlockSize-d.nx]
+
+	b = binary.BigEndian.AppendUint64(b, d.len)
+
+	return b, nil
+}
+
+// UnmarshalBinary restores a state encoded by MarshalBinary.
+func (d *digest) UnmarshalBinary(b []byte) error {
+	if len(b) < len(magic) || string(b[:len(magic)]) != magic {
+		return errors.New("hash/md5: invalid hash state identifier")
+	}
+	if len(b) != marshaledSize {
+		return errors.New("hash/md5: invalid hash state size")
+	}
+
+	b = b[len(magic):]
+	for i := range d.s {
+		d.s[i] = binary.BigEndian.Uint32(b[4*i:])
+	}
+	b = b[4*4:]
+
+	d.len = binary.BigEndian.Uint64(b[BlockSize:])
+	d.nx = int(d.len % uint64(BlockSize))
+	copy(d.buf[:], b[:d.nx])
+
+	return nil
+}
+
+// ChainingSize returns the length of the chaining value in bytes.
+func (d *digest) ChainingSize() int {
+	return 4 * 4
+}
+
+// ChainingValue returns the chaining value after the last complete block,
+// in little-endian as the digest.
+func (d *digest) ChainingValue() []byte {
+	res := make([]byte, 0, d.ChainingSize())
+	for _, w := range d.s {
+		res = binary.LittleEndian.AppendUint32(res, w)
+	}
+
+	return res
+}
+
+// SetChainingValue resets the hash with iv as initial chaining value.
+func (d *digest) SetChainingValue(iv []byte) error {
+	if len(iv) != d.ChainingSize() {
+		return errors.New("hash/md5: invalid chaining value size")
+	}
+
+	d.Reset()
+	for i := range d.s {
+		d.s[i] = binary.LittleEndian.Uint32(iv[4*i:])
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package md5
+
+import (
+	"crypto/md5"
+	crand "crypto/rand"
+	"encoding"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+)
+
+func TestRFC(t *testing.T) {
+	// RFC 1321 appendix A.5
+	msgs := []string{
+		"",
+		"a",
+		"abc",
+		"message digest",
+		"abcdefghijklmnopqrstuvwxyz",
+		"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789",
+		"12345678901234567890123456789012345678901234567890123456789012345678901234567890",
+	}
+
+	expHexs := []string{
+		"d41d8cd98f00b204e9800998ecf8427e",
+		"0cc175b9c0f1b6a831c399e269772661",
+		"900150983cd24fb0d6963f7d28e17f72",
+		"f96b697d7cb7938d525a2f31aaf161d0",
+		"c3fcd3d76192e4007dfb496cca67e13b",
+		"d174ab98d277d9f5a5611c2c9f419d9f",
+		"57edf4a22be3c955ac49da2e2107b67a",
+	}
+
+	for i := range msgs {
+		res := Sum([]byte(msgs[i]))
+		resHex := hex.EncodeToString(res[:])
+
+		if resHex != expHexs[i] {
+			t.Errorf("MD5(%s) %s != %s", msgs[i], resHex, expHexs[i])
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	tries := 1000
+
+	for tr := 0; tr < tries; tr++ {
+		msgLen := rand.Intn(300)
+		msg := make([]byte, msgLen)
+		crand.Read(msg)
+
+		res := Sum(msg)
+		resHex := hex.EncodeToString(res[:])
+
+		exp := md5.Sum(msg)
+		expHex := hex.EncodeToString(exp[:])
+
+		if expHex != resHex {
+			t.Errorf("Not equal %s!=%s", resHex, expHex)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	msg := make([]byte, 200)
+	crand.Read(msg)
+
+	exp := md5.Sum(msg)
+	expHex := hex.EncodeToString(exp[:])
+
+	for split := 0; split <= len(msg); split += 7 {
+		// Marshal our state and resume it with crypto/md5
+		h := New()
+		h.Write(msg[:split])
+		state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		goH := md5.New()
+		if err := goH.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+			t.Fatal(err.Error())
+		}
+		goH.Write(msg[split:])
+
+		if resHex := hex.EncodeToString(goH.Sum(nil)); resHex != expHex {
+			t.Errorf("split %d: Not equal %s!=%s", split, resHex, expHex)
+		}
+
+		// Marshal crypto/md5's state and resume it with ours
+		goH = md5.New()
+		goH.Write(msg[:split])
+		state, err = goH.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		h = New()
+		if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+			t.Fatal(err.Error())
+		}
+		h.Write(msg[split:])
+
+		if resHex := hex.EncodeToString(h.Sum(nil)); resHex != expHex {
+			t.Errorf("split %d: Not equal %s!=%s", split, resHex, expHex)
+		}
+	}
+}
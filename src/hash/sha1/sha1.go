@@ -1,6 +1,7 @@
 package sha1
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"hash"
@@ -19,6 +20,12 @@ type digest struct {
 const BlockSize int = 512 / 8
 const Size int = 160 / 8
 
+// Marshaled state layout, compatible with crypto/sha1
+const (
+	magic         = "sha\x01"
+	marshaledSize = len(magic) + 5*4 + BlockSize + 8
+)
+
 // Word size in bits
 const wordSize int = 32
 
@@ -122,7 +129,11 @@ func (dig *digest) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
-func (d *digest) Sum(b []byte) []byte {
+func (d0 *digest) Sum(b []byte) []byte {
+	// Work on a copy so that the caller can keep writing
+	d := *d0
+	d.buf = bytes.Clone(d0.buf)
+
 	l := big.NewInt(0).Add(&d.writenBits, big.NewInt(int64(len(d.buf))*8))
 	pad := lh.ShaPadding32(l)
 	d.Write(pad)
@@ -153,3 +164,85 @@ func (d *digest) Size() int {
 func (d *digest) BlockSize() int {
 	return BlockSize
 }
+
+// MarshalBinary encodes the current state of the hash so that it can be
+// resumed later with UnmarshalBinary.
+// The format is the same as the one used by crypto/sha1.
+func (d *digest) MarshalBinary() ([]byte, error) {
+	if d.writenBits.BitLen() > 64 {
+		return nil, errors.New("hash/sha1: message too long to marshal")
+	}
+
+	b := make([]byte, 0, marshaledSize)
+	b = append(b, magic...)
+	b = binary.BigEndian.AppendUint32(b, d.h0)
+	b = binary.BigEndian.AppendUint32(b, d.h1)
+	b = binary.BigEndian.AppendUint32(b, d.h2)
+	b = binary.BigEndian.AppendUint32(b, d.h3)
+	b = binary.BigEndian.AppendUint32(b, d.h4)
+
+	// Pending bytes, padded with zeros to a full block
+	b = append(b, d.buf...)
+	b = b[:len(b)+BlockSize-len(d.buf)]
+
+	// Length in bytes
+	l := d.writenBits.Uint64()/8 + uint64(len(d.buf))
+	b = binary.BigEndian.AppendUint64(b, l)
+
+	return b, nil
+}
+
+// UnmarshalBinary restores a state encoded by MarshalBinary.
+func (d *digest) UnmarshalBinary(b []byte) error {
+	if len(b) < len(magic) || string(b[:len(magic)]) != magic {
+		return errors.New("hash/sha1: invalid hash state identifier")
+	}
+	if len(b) != marshaledSize {
+		return errors.New("hash/sha1: invalid hash state size")
+	}
+
+	b = b[len(magic):]
+	d.h0 = binary.BigEndian.Uint32(b[0:])
+	d.h1 = binary.BigEndian.Uint32(b[4:])
+	d.h2 = binary.BigEndian.Uint32(b[8:])
+	d.h3 = binary.BigEndian.Uint32(b[12:])
+	d.h4 = binary.BigEndian.Uint32(b[16:])
+	b = b[5*4:]
+
+	l := binary.BigEndian.Uint64(b[BlockSize:])
+	nx := int(l % uint64(BlockSize))
+
+	d.buf = append(make([]byte, 0, BlockSize), b[:nx]...)
+	d.writenBits = *new(big.Int).Mul(new(big.Int).SetUint64(l-uint64(nx)), big.NewInt(8))
+
+	return nil
+}
+
+// ChainingSize returns the length of the chaining value in bytes.
+func (d *digest) ChainingSize() int {
+	return 5 * 4
+}
+
+// ChainingValue returns the chaining value after the last complete block.
+func (d *digest) ChainingValue() []byte {
+	res := make([]byte, 0, d.ChainingSize())
+	for _, w := range []uint32{d.h0, d.h1, d.h2, d.h3, d.h4} {
+		res = binary.BigEndian.AppendUint32(res, w)
+	}
+
+	return res
+}
+
+// SetChainingValue resets the hash with iv as initial chaining value.
+func (d *digest) SetChainingValue(iv []byte) error {
+	if len(iv) != d.ChainingSize() {
+		return errors.New("hash/sha1: invalid chaining value size")
+	}
+
+	d.Reset()
+	for i, w := range []*uint32{&d.h0, &d.h1, &d.h2, &d.h3, &d.h4} {
+		*w = binary.BigEndian.Uint32(iv[4*i:])
+	}
+
+	return nil
+}
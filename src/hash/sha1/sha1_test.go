@@ -3,6 +3,7 @@ package sha1
 import (
 	crand "crypto/rand"
 	"crypto/sha1"
+	"encoding"
 	"encoding/hex"
 	"math/rand"
 	"testing"
@@ -41,3 +42,74 @@ func TestRandom(t *testing.T) {
 		}
 	}
 }
+
+func TestMarshal(t *testing.T) {
+	msg := make([]byte, 200)
+	crand.Read(msg)
+
+	for split := 0; split <= len(msg); split += 7 {
+		// Marshal our state and resume it with crypto/sha1
+		h := New()
+		h.Write(msg[:split])
+		state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		goH := sha1.New()
+		if err := goH.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+			t.Fatal(err.Error())
+		}
+		goH.Write(msg[split:])
+
+		exp := sha1.Sum(msg)
+		expHex := hex.EncodeToString(exp[:])
+
+		if resHex := hex.EncodeToString(goH.Sum(nil)); resHex != expHex {
+			t.Errorf("split %d: Not equal %s!=%s", split, resHex, expHex)
+		}
+
+		// Marshal crypto/sha1's state and resume it with ours
+		goH = sha1.New()
+		goH.Write(msg[:split])
+		state, err = goH.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		h = New()
+		if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+			t.Fatal(err.Error())
+		}
+		h.Write(msg[split:])
+
+		if resHex := hex.EncodeToString(h.Sum(nil)); resHex != expHex {
+			t.Errorf("split %d: Not equal %s!=%s", split, resHex, expHex)
+		}
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	h := New().(encoding.BinaryUnmarshaler)
+
+	if err := h.UnmarshalBinary([]byte("sha\x02")); err == nil {
+		t.Error("wrong identifier accepted")
+	}
+	if err := h.UnmarshalBinary([]byte("sha\x01")); err == nil {
+		t.Error("wrong size accepted")
+	}
+}
+
+func TestSumTwice(t *testing.T) {
+	h := New()
+	h.Write([]byte("a"))
+	h.Sum(nil)
+	h.Write([]byte("bc"))
+
+	res := h.Sum(nil)
+	exp := sha1.Sum([]byte("abc"))
+
+	if resHex, expHex := hex.EncodeToString(res), hex.EncodeToString(exp[:]); resHex != expHex {
+		t.Errorf("Not equal %s!=%s", resHex, expHex)
+	}
+}
@@ -3,7 +3,9 @@ package sha256
 import (
 	crand "crypto/rand"
 	"crypto/sha256"
+	"encoding"
 	"encoding/hex"
+	"hash"
 	"math/rand"
 	"testing"
 )
@@ -75,3 +77,94 @@ func TestRandom224(t *testing.T) {
 		}
 	}
 }
+
+func TestMarshal(t *testing.T) {
+	msg := make([]byte, 200)
+	crand.Read(msg)
+
+	news := []func() hash.Hash{New224, New256}
+	goNews := []func() hash.Hash{sha256.New224, sha256.New}
+
+	for i := range news {
+		goH := goNews[i]()
+		goH.Write(msg)
+		expHex := hex.EncodeToString(goH.Sum(nil))
+
+		for split := 0; split <= len(msg); split += 7 {
+			// Marshal our state and resume it with crypto/sha256
+			h := news[i]()
+			h.Write(msg[:split])
+			state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			goH := goNews[i]()
+			if err := goH.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+				t.Fatal(err.Error())
+			}
+			goH.Write(msg[split:])
+
+			if resHex := hex.EncodeToString(goH.Sum(nil)); resHex != expHex {
+				t.Errorf("split %d: Not equal %s!=%s", split, resHex, expHex)
+			}
+
+			// Marshal crypto/sha256's state and resume it with ours
+			goH = goNews[i]()
+			goH.Write(msg[:split])
+			state, err = goH.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			h = news[i]()
+			if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+				t.Fatal(err.Error())
+			}
+			h.Write(msg[split:])
+
+			if resHex := hex.EncodeToString(h.Sum(nil)); resHex != expHex {
+				t.Errorf("split %d: Not equal %s!=%s", split, resHex, expHex)
+			}
+		}
+	}
+}
+
+func TestUnmarshalWrongType(t *testing.T) {
+	state, err := New224().(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := New256().(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err == nil {
+		t.Error("SHA-224 state accepted by SHA-256")
+	}
+}
+
+func TestSumTwice(t *testing.T) {
+	hashes := []struct {
+		new func() hash.Hash
+		ref func() hash.Hash
+	}{
+		{New224, sha256.New224},
+		{New256, sha256.New},
+	}
+
+	for _, v := range hashes {
+		// Sum must not change the state, neither inside nor after a block
+		for _, n := range []int{1, 100} {
+			h := v.new()
+			h.Write(make([]byte, n))
+			h.Sum(nil)
+			h.Write([]byte("bc"))
+
+			ref := v.ref()
+			ref.Write(make([]byte, n))
+			ref.Write([]byte("bc"))
+
+			if resHex, expHex := hex.EncodeToString(h.Sum(nil)), hex.EncodeToString(ref.Sum(nil)); resHex != expHex {
+				t.Errorf("Not equal %s!=%s", resHex, expHex)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package sha512
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"hash"
@@ -30,6 +31,15 @@ const Size384 int = 384 / 8
 const Size512_224 int = 224 / 8
 const Size512_256 int = 256 / 8
 
+// Marshaled state layout, compatible with crypto/sha512
+const (
+	magic384      = "sha\x04"
+	magic512_224  = "sha\x05"
+	magic512_256  = "sha\x06"
+	magic512      = "sha\x07"
+	marshaledSize = len(magic512) + 8*8 + BlockSize + 8
+)
+
 // FUNCTIONS
 
 var k = [...]uint64{
@@ -236,7 +246,11 @@ func (dig *digest) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
-func (d *digest) Sum(b []byte) []byte {
+func (d0 *digest) Sum(b []byte) []byte {
+	// Work on a copy so that the caller can keep writing
+	d := *d0
+	d.buf = bytes.Clone(d0.buf)
+
 	bufBitLen := big.NewInt(0).Mul(big.NewInt(int64(len(d.buf))), big.NewInt(8))
 	l := big.NewInt(0).Add(&d.writenBits, bufBitLen)
 	pad := padding(l)
@@ -313,3 +327,107 @@ func (d *digest) Size() int {
 func (d *digest) BlockSize() int {
 	return BlockSize
 }
+
+// magic returns the marshaling identifier of the hash type
+func (d *digest) magic() string {
+	if d.htype == h512 {
+		return magic512
+	} else if d.htype == h384 {
+		return magic384
+	} else if d.htype == h512_224 {
+		return magic512_224
+	} else {
+		return magic512_256
+	}
+}
+
+// MarshalBinary encodes the current state of the hash so that it can be
+// resumed later with UnmarshalBinary.
+// The format is the same as the one used by crypto/sha512.
+func (d *digest) MarshalBinary() ([]byte, error) {
+	if d.writenBits.BitLen() > 64 {
+		return nil, errors.New("hash/sha512: message too long to marshal")
+	}
+
+	b := make([]byte, 0, marshaledSize)
+	b = append(b, d.magic()...)
+	b = binary.BigEndian.AppendUint64(b, d.h0)
+	b = binary.BigEndian.AppendUint64(b, d.h1)
+	b = binary.BigEndian.AppendUint64(b, d.h2)
+	b = binary.BigEndian.AppendUint64(b, d.h3)
+	b = binary.BigEndian.AppendUint64(b, d.h4)
+	b = binary.BigEndian.AppendUint64(b, d.h5)
+	b = binary.BigEndian.AppendUint64(b, d.h6)
+	b = binary.BigEndian.AppendUint64(b, d.h7)
+
+	// Pending bytes, padded with zeros to a full block
+	b = append(b, d.buf...)
+	b = b[:len(b)+BlockSize-len(d.buf)]
+
+	// Length in bytes
+	l := d.writenBits.Uint64()/8 + uint64(len(d.buf))
+	b = binary.BigEndian.AppendUint64(b, l)
+
+	return b, nil
+}
+
+// UnmarshalBinary restores a state encoded by MarshalBinary.
+// The state must come from a hash of the same type.
+func (d *digest) UnmarshalBinary(b []byte) error {
+	magic := d.magic()
+	if len(b) < len(magic) || string(b[:len(magic)]) != magic {
+		return errors.New("hash/sha512: invalid hash state identifier")
+	}
+	if len(b) != marshaledSize {
+		return errors.New("hash/sha512: invalid hash state size")
+	}
+
+	b = b[len(magic):]
+	d.h0 = binary.BigEndian.Uint64(b[0:])
+	d.h1 = binary.BigEndian.Uint64(b[8:])
+	d.h2 = binary.BigEndian.Uint64(b[8*2:])
+	d.h3 = binary.BigEndian.Uint64(b[8*3:])
+	d.h4 = binary.BigEndian.Uint64(b[8*4:])
+	d.h5 = binary.BigEndian.Uint64(b[8*5:])
+	d.h6 = binary.BigEndian.Uint64(b[8*6:])
+	d.h7 = binary.BigEndian.Uint64(b[8*7:])
+	b = b[8*8:]
+
+	l := binary.BigEndian.Uint64(b[BlockSize:])
+	nx := int(l % uint64(BlockSize))
+
+	d.buf = append(make([]byte, 0, BlockSize), b[:nx]...)
+	d.writenBits = *new(big.Int).Mul(new(big.Int).SetUint64(l-uint64(nx)), big.NewInt(8))
+
+	return nil
+}
+
+// ChainingSize returns the length of the chaining value in bytes, which
+// does not depend on the truncation of the output.
+func (d *digest) ChainingSize() int {
+	return 8 * 8
+}
+
+// ChainingValue returns the chaining value after the last complete block.
+func (d *digest) ChainingValue() []byte {
+	res := make([]byte, 0, d.ChainingSize())
+	for _, w := range []uint64{d.h0, d.h1, d.h2, d.h3, d.h4, d.h5, d.h6, d.h7} {
+		res = binary.BigEndian.AppendUint64(res, w)
+	}
+
+	return res
+}
+
+// SetChainingValue resets the hash with iv as initial chaining value.
+func (d *digest) SetChainingValue(iv []byte) error {
+	if len(iv) != d.ChainingSize() {
+		return errors.New("hash/sha512: invalid chaining value size")
+	}
+
+	d.Reset()
+	for i, w := range []*uint64{&d.h0, &d.h1, &d.h2, &d.h3, &d.h4, &d.h5, &d.h6, &d.h7} {
+		*w = binary.BigEndian.Uint64(iv[8*i:])
+	}
+
+	return nil
+}
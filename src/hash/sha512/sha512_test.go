@@ -3,7 +3,9 @@ package sha512
 import (
 	crand "crypto/rand"
 	"crypto/sha512"
+	"encoding"
 	"encoding/hex"
+	"hash"
 	"math/rand"
 	"testing"
 )
@@ -143,3 +145,96 @@ func TestRandom512_256(t *testing.T) {
 		}
 	}
 }
+
+func TestMarshal(t *testing.T) {
+	msg := make([]byte, 400)
+	crand.Read(msg)
+
+	news := []func() hash.Hash{New512, New384, New512_224, New512_256}
+	goNews := []func() hash.Hash{sha512.New, sha512.New384, sha512.New512_224, sha512.New512_256}
+
+	for i := range news {
+		goH := goNews[i]()
+		goH.Write(msg)
+		expHex := hex.EncodeToString(goH.Sum(nil))
+
+		for split := 0; split <= len(msg); split += 13 {
+			// Marshal our state and resume it with crypto/sha512
+			h := news[i]()
+			h.Write(msg[:split])
+			state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			goH := goNews[i]()
+			if err := goH.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+				t.Fatal(err.Error())
+			}
+			goH.Write(msg[split:])
+
+			if resHex := hex.EncodeToString(goH.Sum(nil)); resHex != expHex {
+				t.Errorf("split %d: Not equal %s!=%s", split, resHex, expHex)
+			}
+
+			// Marshal crypto/sha512's state and resume it with ours
+			goH = goNews[i]()
+			goH.Write(msg[:split])
+			state, err = goH.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			h = news[i]()
+			if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+				t.Fatal(err.Error())
+			}
+			h.Write(msg[split:])
+
+			if resHex := hex.EncodeToString(h.Sum(nil)); resHex != expHex {
+				t.Errorf("split %d: Not equal %s!=%s", split, resHex, expHex)
+			}
+		}
+	}
+}
+
+func TestUnmarshalWrongType(t *testing.T) {
+	state, err := New384().(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := New512().(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err == nil {
+		t.Error("SHA-384 state accepted by SHA-512")
+	}
+}
+
+func TestSumTwice(t *testing.T) {
+	hashes := []struct {
+		new func() hash.Hash
+		ref func() hash.Hash
+	}{
+		{New384, sha512.New384},
+		{New512, sha512.New},
+		{New512_224, sha512.New512_224},
+		{New512_256, sha512.New512_256},
+	}
+
+	for _, v := range hashes {
+		// Sum must not change the state, neither inside nor after a block
+		for _, n := range []int{1, 200} {
+			h := v.new()
+			h.Write(make([]byte, n))
+			h.Sum(nil)
+			h.Write([]byte("bc"))
+
+			ref := v.ref()
+			ref.Write(make([]byte, n))
+			ref.Write([]byte("bc"))
+
+			if resHex, expHex := hex.EncodeToString(h.Sum(nil)), hex.EncodeToString(ref.Sum(nil)); resHex != expHex {
+				t.Errorf("Not equal %s!=%s", resHex, expHex)
+			}
+		}
+	}
+}
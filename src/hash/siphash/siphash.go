@@ -0,0 +1,235 @@
+// Package siphash implements the SipHash keyed pseudorandom functions
+// SipHash-2-4 and SipHash-1-3, with 64 or 128 bits outputs, as defined in
+// "SipHash: a fast short-input PRF" (Aumasson, Bernstein 2012,
+// https://www.aumasson.jp/siphash/siphash.pdf).
+//
+// SipHash is meant for hash tables and other short inputs where an
+// attacker must not be able to predict collisions, it is not a general
+// purpose MAC. The outputs are little-endian, as in the reference vectors.
+//
+// Unlike the other digests, the state cannot be marshaled: SipHash is
+// always keyed and the internal state reveals the key.
+package siphash
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+)
+
+const BlockSize int = 8
+const KeySize int = 16
+const Size int = 8
+const Size128 int = 16
+
+// Initialization constants, "somepseudorandomlygeneratedbytes"
+const (
+	c0 uint64 = 0x736f6d6570736575
+	c1 uint64 = 0x646f72616e646f6d
+	c2 uint64 = 0x6c7967656e657261
+	c3 uint64 = 0x7465646279746573
+)
+
+type digest struct {
+	k0, k1 uint64
+	v      [4]uint64
+	buf    [BlockSize]byte
+	nx     int
+	// Length of the message in bytes, only its low byte is used
+	len uint64
+
+	// Number of compression and finalization rounds
+	c, d int
+	size int
+}
+
+// sipRound applies n SipRounds to v
+func sipRound(v *[4]uint64, n int) {
+	v0, v1, v2, v3 := v[0], v[1], v[2], v[3]
+	for i := 0; i < n; i++ {
+		v0 += v1
+		v1 = bits.RotateLeft64(v1, 13)
+		v1 ^= v0
+		v0 = bits.RotateLeft64(v0, 32)
+		v2 += v3
+		v3 = bits.RotateLeft64(v3, 16)
+		v3 ^= v2
+		v0 += v3
+		v3 = bits.RotateLeft64(v3, 21)
+		v3 ^= v0
+		v2 += v1
+		v1 = bits.RotateLeft64(v1, 17)
+		v1 ^= v2
+		v2 = bits.RotateLeft64(v2, 32)
+	}
+	v[0], v[1], v[2], v[3] = v0, v1, v2, v3
+}
+
+// block processes the full 8B words of p
+func (d *digest) block(p []byte) {
+	for len(p) >= BlockSize {
+		m := binary.LittleEndian.Uint64(p)
+		d.v[3] ^= m
+		sipRound(&d.v, d.c)
+		d.v[0] ^= m
+		p = p[BlockSize:]
+	}
+}
+
+// FUNCTIONS
+
+// Sum64 returns the SipHash-2-4 of data, without allocating.
+func Sum64(key *[KeySize]byte, data []byte) uint64 {
+	d := digest{c: 2, d: 4, size: Size}
+	d.setKey(key[:])
+	d.Reset()
+	d.Write(data)
+
+	return d.Sum64()
+}
+
+// Sum64_13 returns the SipHash-1-3 of data, without allocating.
+func Sum64_13(key *[KeySize]byte, data []byte) uint64 {
+	d := digest{c: 1, d: 3, size: Size}
+	d.setKey(key[:])
+	d.Reset()
+	d.Write(data)
+
+	return d.Sum64()
+}
+
+// HASH
+
+// newDigest returns a SipHash-c-d with an output of size bytes
+func newDigest(c, d, size int, key []byte) (*digest, error) {
+	if len(key) != KeySize {
+		return nil, errors.New("hash/siphash: invalid key size")
+	}
+
+	h := &digest{c: c, d: d, size: size}
+	h.setKey(key)
+	h.Reset()
+
+	return h, nil
+}
+
+// New64 returns a new SipHash-2-4 with a 64 bits output.
+func New64(key []byte) (hash.Hash64, error) {
+	return newDigest(2, 4, Size, key)
+}
+
+// New128 returns a new SipHash-2-4 with a 128 bits output.
+func New128(key []byte) (hash.Hash, error) {
+	return newDigest(2, 4, Size128, key)
+}
+
+// New13_64 returns a new SipHash-1-3 with a 64 bits output.
+func New13_64(key []byte) (hash.Hash64, error) {
+	return newDigest(1, 3, Size, key)
+}
+
+// New13_128 returns a new SipHash-1-3 with a 128 bits output.
+func New13_128(key []byte) (hash.Hash, error) {
+	return newDigest(1, 3, Size128, key)
+}
+
+func (d *digest) setKey(key []byte) {
+	d.k0 = binary.LittleEndian.Uint64(key)
+	d.k1 = binary.LittleEndian.Uint64(key[8:])
+}
+
+func (d *digest) Write(p []byte) (n int, err error) {
+	n = len(p)
+	d.len += uint64(n)
+
+	// Fill the pending word first
+	if d.nx > 0 {
+		c := copy(d.buf[d.nx:], p)
+		d.nx += c
+		p = p[c:]
+
+		if d.nx < BlockSize {
+			return n, nil
+		}
+
+		d.block(d.buf[:])
+		d.nx = 0
+	}
+
+	full := len(p) &^ (BlockSize - 1)
+	d.block(p[:full])
+
+	// Keep the rest for later
+	d.nx = copy(d.buf[:], p[full:])
+
+	return n, nil
+}
+
+// finalize returns the output words, the receiver is modified
+func (d *digest) finalize() (uint64, uint64) {
+	// The last word holds the remaining bytes and the length mod 256
+	// c.f. paper 2.2
+	var last [BlockSize]byte
+	copy(last[:], d.buf[:d.nx])
+	last[7] = byte(d.len)
+	d.block(last[:])
+
+	if d.size == Size128 {
+		d.v[2] ^= 0xee
+	} else {
+		d.v[2] ^= 0xff
+	}
+	sipRound(&d.v, d.d)
+	r0 := d.v[0] ^ d.v[1] ^ d.v[2] ^ d.v[3]
+	if d.size != Size128 {
+		return r0, 0
+	}
+
+	// The second half of the 128 bits output
+	// c.f. https://github.com/veorq/SipHash
+	d.v[1] ^= 0xdd
+	sipRound(&d.v, d.d)
+	r1 := d.v[0] ^ d.v[1] ^ d.v[2] ^ d.v[3]
+
+	return r0, r1
+}
+
+func (d0 *digest) Sum(b []byte) []byte {
+	// Work on a copy so that the caller can keep writing
+	d := *d0
+	r0, r1 := d.finalize()
+
+	b = binary.LittleEndian.AppendUint64(b, r0)
+	if d.size == Size128 {
+		b = binary.LittleEndian.AppendUint64(b, r1)
+	}
+
+	return b
+}
+
+// Sum64 returns the 64 bits output as an integer, for the 128 bits
+// variants this is its first half.
+func (d0 *digest) Sum64() uint64 {
+	d := *d0
+	r0, _ := d.finalize()
+
+	return r0
+}
+
+func (d *digest) Reset() {
+	d.v = [4]uint64{d.k0 ^ c0, d.k1 ^ c1, d.k0 ^ c2, d.k1 ^ c3}
+	if d.size == Size128 {
+		d.v[1] ^= 0xee
+	}
+	d.nx = 0
+	d.len = 0
+}
+
+func (d *digest) Size() int {
+	return d.size
+}
+
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
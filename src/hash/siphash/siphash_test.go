@@ -0,0 +1,123 @@
+package siphash
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"testing"
+)
+
+// Key 00 01 .. 0f and message 00 01 .. (n-1) of the reference vectors
+func refKey() []byte {
+	key := make([]byte, KeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	return key
+}
+
+func refMsg(n int) []byte {
+	msg := make([]byte, n)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+	return msg
+}
+
+func TestVectors(t *testing.T) {
+	// Reference implementation vectors, also checked against OpenSSL
+	vectors := []struct {
+		n                int
+		exp24, exp24_128 string
+		exp13, exp13_128 string
+	}{
+		{0, "310e0edd47db6f72", "a3817f04ba25a8e66df67214c7550293", "dcc40f055801acab", "e77ebcb22788a5befd62db6add303001"},
+		{1, "fd67dc93c539f874", "da87c1d86b99af44347659119b22fc45", "93ca577df39bf4c9", "fc6f370460d3eda85e0573cc2b2ff063"},
+		{7, "37d1018bf50002ab", "a1f1ebbed8dbc153c0b84aa61ff08239", "4011b19b987d92d3", "1084b923f2aae0c3a62f2ec80848ab77"},
+		{8, "6224939a79f5f593", "3b62a9ba6258f5610f83e264f31497b4", "8e9a298d11959036", "aa12fee1d5e3dab4724f16ab35f9c799"},
+		{15, "e545be4961ca29a1", "5493e99933b0a8117e08ec0f97cfc3d9", "5699512a6dd820d3", "c17e5505b2bd526c2921cdec1e7e0109"},
+		{63, "724506eb4c328a95", "5150d1772f50834a503e069a973fbd7c", "a8b3bbb76290199d", "4c5800e34efe426f079f6b0aa75260ad"},
+	}
+
+	constructors := []func([]byte) (hash.Hash, error){
+		func(k []byte) (hash.Hash, error) { return New64(k) },
+		New128,
+		func(k []byte) (hash.Hash, error) { return New13_64(k) },
+		New13_128,
+	}
+
+	for _, v := range vectors {
+		msg := refMsg(v.n)
+		for i, expHex := range []string{v.exp24, v.exp24_128, v.exp13, v.exp13_128} {
+			h, err := constructors[i](refKey())
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			h.Write(msg)
+
+			if resHex := hex.EncodeToString(h.Sum(nil)); resHex != expHex {
+				t.Errorf("%d bytes: Not equal %s!=%s", v.n, resHex, expHex)
+			}
+		}
+	}
+}
+
+func TestSum64(t *testing.T) {
+	key := ([KeySize]byte)(refKey())
+
+	for n := 0; n < 64; n++ {
+		msg := refMsg(n)
+
+		h, _ := New64(key[:])
+		h.Write(msg)
+		if res, exp := Sum64(&key, msg), h.Sum64(); res != exp {
+			t.Errorf("Not equal %x!=%x", res, exp)
+		}
+		if res, exp := h.Sum64(), binary.LittleEndian.Uint64(h.Sum(nil)); res != exp {
+			t.Errorf("Not equal %x!=%x", res, exp)
+		}
+
+		h, _ = New13_64(key[:])
+		h.Write(msg)
+		if res, exp := Sum64_13(&key, msg), h.Sum64(); res != exp {
+			t.Errorf("Not equal %x!=%x", res, exp)
+		}
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	msg := refMsg(63)
+	expHex := "5150d1772f50834a503e069a973fbd7c"
+
+	for _, chunk := range []int{1, 3, 7, 8, 9, 63} {
+		h, _ := New128(refKey())
+		for i := 0; i < len(msg); i += chunk {
+			end := i + chunk
+			if end > len(msg) {
+				end = len(msg)
+			}
+			h.Write(msg[i:end])
+			// Sum in the middle must not change the result
+			h.Sum(nil)
+		}
+
+		if resHex := hex.EncodeToString(h.Sum(nil)); resHex != expHex {
+			t.Errorf("chunk %d: Not equal %s!=%s", chunk, resHex, expHex)
+		}
+
+		h.Reset()
+		h.Write(msg)
+		if resHex := hex.EncodeToString(h.Sum(nil)); resHex != expHex {
+			t.Errorf("reset: Not equal %s!=%s", resHex, expHex)
+		}
+	}
+}
+
+func TestInvalidKey(t *testing.T) {
+	if _, err := New64(make([]byte, 15)); err == nil {
+		t.Error("key of 15 bytes accepted")
+	}
+	if _, err := New13_128(make([]byte, 17)); err == nil {
+		t.Error("key of 17 bytes accepted")
+	}
+}
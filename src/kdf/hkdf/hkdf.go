@@ -0,0 +1,104 @@
+// Package hkdf implements the HMAC-based Extract-and-Expand Key Derivation
+// Function (HKDF) as defined in RFC 5869
+// (https://datatracker.ietf.org/doc/html/rfc5869).
+//
+// Any hash of the library can be used, e.g. hkdf.Key(sha256.New256, ...).
+package hkdf
+
+import (
+	"errors"
+	"hash"
+	"io"
+
+	"github.com/loicbacciga/crypto-go/src/mac/hmac"
+)
+
+// Extract generates a pseudorandom key from secret and salt.
+// If salt is nil, a string of HashLen zeros is used.
+// c.f. RFC5869 2.2
+func Extract(h func() hash.Hash, secret, salt []byte) []byte {
+	if salt == nil {
+		salt = make([]byte, h().Size())
+	}
+
+	extractor := hmac.New(h, salt)
+	extractor.Write(secret)
+
+	return extractor.Sum(nil)
+}
+
+type expander struct {
+	expander hash.Hash
+	info     []byte
+	// Counter of the next block T(i)
+	counter byte
+	// Previous block T(i-1)
+	prev []byte
+	// Bytes of prev not read yet
+	buf []byte
+}
+
+// Expand returns a Reader from which keys can be read, using the given
+// pseudorandom key and optional context info.
+// At most 255 * HashLen bytes can be read, after which the Reader returns
+// an error.
+// c.f. RFC5869 2.3
+func Expand(h func() hash.Hash, pseudorandomKey, info []byte) io.Reader {
+	return &expander{
+		expander: hmac.New(h, pseudorandomKey),
+		info:     info,
+		counter:  1,
+	}
+}
+
+func (e *expander) Read(p []byte) (n int, err error) {
+	// Check whether enough data can be generated
+	need := len(p)
+	blocksLeft := 0
+	if e.counter != 0 {
+		// Once the counter wraps, all 255 blocks were generated
+		blocksLeft = 256 - int(e.counter)
+	}
+	if len(e.buf)+blocksLeft*e.expander.Size() < need {
+		return 0, errors.New("hkdf: entropy limit reached")
+	}
+
+	// Read any leftover from the buffer
+	n = copy(p, e.buf)
+	p = p[n:]
+
+	// Fill the rest of the buffer
+	for len(p) > 0 {
+		// T(i) = HMAC-Hash(PRK, T(i-1) | info | i)
+		e.expander.Reset()
+		e.expander.Write(e.prev)
+		e.expander.Write(e.info)
+		e.expander.Write([]byte{e.counter})
+		e.prev = e.expander.Sum(e.prev[:0])
+		e.counter++
+
+		// Copy the new batch into p
+		e.buf = e.prev
+		n = copy(p, e.buf)
+		p = p[n:]
+	}
+
+	// Save leftovers for next run
+	e.buf = e.buf[n:]
+
+	return need, nil
+}
+
+// Key derives a key of keyLength bytes from secret, salt and info.
+// It is Extract followed by Expand.
+// Returns an error if keyLength is larger than 255 * HashLen.
+func Key(h func() hash.Hash, secret, salt, info []byte, keyLength int) ([]byte, error) {
+	prk := Extract(h, secret, salt)
+
+	key := make([]byte, keyLength)
+	if _, err := io.ReadFull(Expand(h, prk, info), key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
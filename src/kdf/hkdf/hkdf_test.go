@@ -0,0 +1,144 @@
+package hkdf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"hash"
+	"io"
+	"testing"
+
+	"github.com/loicbacciga/crypto-go/src/hash/sha1"
+	"github.com/loicbacciga/crypto-go/src/hash/sha256"
+)
+
+func fromHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// seq returns the bytes from, from+1, ..., to-1
+func seq(from, to int) []byte {
+	res := make([]byte, 0, to-from)
+	for i := from; i < to; i++ {
+		res = append(res, byte(i))
+	}
+	return res
+}
+
+type testVector struct {
+	h               func() hash.Hash
+	ikm, salt, info []byte
+	l               int
+	prkHex, okmHex  string
+}
+
+// Test cases of RFC 5869 appendix A
+var rfc5869Vectors = []testVector{
+	// A.1
+	{
+		sha256.New256, bytes.Repeat([]byte{0x0b}, 22), seq(0x00, 0x0d), seq(0xf0, 0xfa), 42,
+		"077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5",
+		"3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865",
+	},
+	// A.2
+	{
+		sha256.New256, seq(0x00, 0x50), seq(0x60, 0xb0), seq(0xb0, 0x100), 82,
+		"06a6b88c5853361a06104c9ceb35b45cef760014904671014a193f40c15fc244",
+		"b11e398dc80327a1c8e7f78c596a49344f012eda2d4efad8a050cc4c19afa97c59045a99cac7827271cb41c65e590e09da3275600c2f09b8367793a9aca3db71cc30c58179ec3e87c14c01d5c1f3434f1d87",
+	},
+	// A.3
+	{
+		sha256.New256, bytes.Repeat([]byte{0x0b}, 22), []byte{}, []byte{}, 42,
+		"19ef24a32c717b167f33a91d6f648bdf96596776afdb6377ac434c1c293ccb04",
+		"8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8",
+	},
+	// A.4
+	{
+		sha1.New, bytes.Repeat([]byte{0x0b}, 11), seq(0x00, 0x0d), seq(0xf0, 0xfa), 42,
+		"9b6c18c432a7bf8f0e71c8eb88f4b30baa2ba243",
+		"085a01ea1b10f36933068b56efa5ad81a4f14b822f5b091568a9cdd4f155fda2c22e422478d305f3f896",
+	},
+	// A.5
+	{
+		sha1.New, seq(0x00, 0x50), seq(0x60, 0xb0), seq(0xb0, 0x100), 82,
+		"8adae09a2a307059478d309b26c4115a224cfaf6",
+		"0bd770a74d1160f7c9f12cd5912a06ebff6adcae899d92191fe4305673ba2ffe8fa3f1a4e5ad79f3f334b3b202b2173c486ea37ce3d397ed034c7f9dfeb15c5e927336d0441f4c4300e2cff0d0900b52d3b4",
+	},
+	// A.6
+	{
+		sha1.New, bytes.Repeat([]byte{0x0b}, 22), []byte{}, []byte{}, 42,
+		"da8c8a73c7fa77288ec6f5e7c297786aa0d32d01",
+		"0ac1af7002b3d761d1e55298da9d0506b9ae52057220a306e07b6b87e8df21d0ea00033de03984d34918",
+	},
+	// A.7, salt not provided
+	{
+		sha1.New, bytes.Repeat([]byte{0x0c}, 22), nil, []byte{}, 42,
+		"2adccada18779e7c2077ad2eb19d3f3e731385dd",
+		"2c91117204d745f3500d636a62f64f0ab3bae548aa53d423b0d1f27ebba6f5e5673a081d70cce7acfc48",
+	},
+}
+
+func TestRFC5869(t *testing.T) {
+	for i, v := range rfc5869Vectors {
+		prk := Extract(v.h, v.ikm, v.salt)
+		if resHex := hex.EncodeToString(prk); resHex != v.prkHex {
+			t.Errorf("vector %d: PRK %s != %s", i, resHex, v.prkHex)
+		}
+
+		okm := make([]byte, v.l)
+		if _, err := io.ReadFull(Expand(v.h, prk, v.info), okm); err != nil {
+			t.Fatal(err.Error())
+		}
+		if resHex := hex.EncodeToString(okm); resHex != v.okmHex {
+			t.Errorf("vector %d: OKM %s != %s", i, resHex, v.okmHex)
+		}
+
+		key, err := Key(v.h, v.ikm, v.salt, v.info, v.l)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if resHex := hex.EncodeToString(key); resHex != v.okmHex {
+			t.Errorf("vector %d: Key %s != %s", i, resHex, v.okmHex)
+		}
+	}
+}
+
+func TestExpandSmallReads(t *testing.T) {
+	v := rfc5869Vectors[1]
+	exp := fromHex(v.okmHex)
+
+	// Read one byte at a time
+	r := Expand(v.h, fromHex(v.prkHex), v.info)
+	res := make([]byte, 0, v.l)
+	b := make([]byte, 1)
+	for len(res) < v.l {
+		if _, err := r.Read(b); err != nil {
+			t.Fatal(err.Error())
+		}
+		res = append(res, b[0])
+	}
+
+	if !bytes.Equal(res, exp) {
+		t.Errorf("Not equal %s!=%s", hex.EncodeToString(res), v.okmHex)
+	}
+}
+
+func TestExpandLimit(t *testing.T) {
+	prk := make([]byte, sha256.Size256)
+	limit := 255 * sha256.Size256
+
+	r := Expand(sha256.New256, prk, nil)
+	if _, err := io.ReadFull(r, make([]byte, limit)); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := r.Read(make([]byte, 1)); err == nil {
+		t.Error("read past the limit")
+	}
+
+	if _, err := Key(sha256.New256, prk, nil, nil, limit+1); err == nil {
+		t.Error("key longer than the limit")
+	}
+}
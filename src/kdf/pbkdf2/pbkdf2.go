@@ -0,0 +1,62 @@
+// Package pbkdf2 implements the password-based key derivation function
+// PBKDF2 as defined in RFC 8018 5.2
+// (https://datatracker.ietf.org/doc/html/rfc8018#section-5.2),
+// using HMAC with any hash of the library as the pseudorandom function.
+package pbkdf2
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"hash"
+
+	"github.com/loicbacciga/crypto-go/src/mac/hmac"
+)
+
+// Key derives a key of keyLength bytes from password and salt, using iter
+// iterations of HMAC with the hash h.
+// Returns an error if iter is not positive or keyLength is too large.
+func Key(h func() hash.Hash, password, salt []byte, iter, keyLength int) ([]byte, error) {
+	if iter < 1 {
+		return nil, errors.New("pbkdf2: iteration count must be positive")
+	}
+	if keyLength < 0 {
+		return nil, errors.New("pbkdf2: negative key length")
+	}
+
+	prf := hmac.New(h, password)
+	hLen := prf.Size()
+
+	// Step 1: dkLen must be at most (2^32 - 1) * hLen
+	l := (keyLength + hLen - 1) / hLen
+	if uint64(l) > 0xffffffff {
+		return nil, errors.New("pbkdf2: derived key too long")
+	}
+
+	dk := make([]byte, 0, l*hLen)
+	u := make([]byte, hLen)
+	t := make([]byte, hLen)
+
+	// Step 3: T_i = F(P, S, c, i)
+	for i := 1; i <= l; i++ {
+		// U_1 = PRF(P, S || INT(i))
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+		u = prf.Sum(u[:0])
+		copy(t, u)
+
+		// U_j = PRF(P, U_{j-1}), T_i = U_1 xor ... xor U_c
+		for j := 2; j <= iter; j++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			subtle.XORBytes(t, t, u)
+		}
+
+		dk = append(dk, t...)
+	}
+
+	// Step 4: concatenate the blocks and keep the first dkLen bytes
+	return dk[:keyLength], nil
+}
@@ -0,0 +1,57 @@
+package pbkdf2
+
+import (
+	"encoding/hex"
+	"hash"
+	"testing"
+
+	"github.com/loicbacciga/crypto-go/src/hash/sha1"
+	"github.com/loicbacciga/crypto-go/src/hash/sha256"
+)
+
+type testVector struct {
+	password, salt string
+	iter, keyLen   int
+	expHex         string
+}
+
+func checkVectors(t *testing.T, h func() hash.Hash, vectors []testVector) {
+	for _, v := range vectors {
+		res, err := Key(h, []byte(v.password), []byte(v.salt), v.iter, v.keyLen)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if resHex := hex.EncodeToString(res); resHex != v.expHex {
+			t.Errorf("PBKDF2(%q, %q, %d): %s != %s", v.password, v.salt, v.iter, resHex, v.expHex)
+		}
+	}
+}
+
+// RFC 6070 section 2, without the 16777216 iterations case
+func TestRFC6070(t *testing.T) {
+	checkVectors(t, sha1.New, []testVector{
+		{"password", "salt", 1, 20, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+		{"password", "salt", 2, 20, "ea6c014dc72d6f8ccd1ed92ace1d41f0d8de8957"},
+		{"password", "salt", 4096, 20, "4b007901b765489abead49d926f721d065a429c1"},
+		{"passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096, 25, "3d2eec4fe41c849b80c8d83662c0e44a8b291a964cf2f07038"},
+		{"pass\x00word", "sa\x00lt", 4096, 16, "56fa6aa75548099dcc37d7f03425e0c3"},
+	})
+}
+
+// RFC 7914 section 11
+func TestRFC7914(t *testing.T) {
+	checkVectors(t, sha256.New256, []testVector{
+		{"passwd", "salt", 1, 64, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"},
+		{"Password", "NaCl", 80000, 64, "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d"},
+	})
+}
+
+func TestInvalidParameters(t *testing.T) {
+	if _, err := Key(sha256.New256, []byte("p"), []byte("s"), 0, 32); err == nil {
+		t.Error("zero iterations accepted")
+	}
+	if _, err := Key(sha256.New256, []byte("p"), []byte("s"), 1, -1); err == nil {
+		t.Error("negative key length accepted")
+	}
+}
@@ -0,0 +1,187 @@
+// Package scrypt implements the scrypt password-based key derivation
+// function as defined in RFC 7914 (https://datatracker.ietf.org/doc/html/rfc7914).
+package scrypt
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+
+	"github.com/loicbacciga/crypto-go/src/hash/sha256"
+	"github.com/loicbacciga/crypto-go/src/kdf/pbkdf2"
+)
+
+const maxInt = int(^uint(0) >> 1)
+
+// salsa208 applies the Salsa20/8 core to b in place.
+// c.f. RFC7914 3
+func salsa208(b *[16]uint32) {
+	x := *b
+
+	for i := 0; i < 8; i += 2 {
+		// Columns
+		x[4] ^= bits.RotateLeft32(x[0]+x[12], 7)
+		x[8] ^= bits.RotateLeft32(x[4]+x[0], 9)
+		x[12] ^= bits.RotateLeft32(x[8]+x[4], 13)
+		x[0] ^= bits.RotateLeft32(x[12]+x[8], 18)
+		x[9] ^= bits.RotateLeft32(x[5]+x[1], 7)
+		x[13] ^= bits.RotateLeft32(x[9]+x[5], 9)
+		x[1] ^= bits.RotateLeft32(x[13]+x[9], 13)
+		x[5] ^= bits.RotateLeft32(x[1]+x[13], 18)
+		x[14] ^= bits.RotateLeft32(x[10]+x[6], 7)
+		x[2] ^= bits.RotateLeft32(x[14]+x[10], 9)
+		x[6] ^= bits.RotateLeft32(x[2]+x[14], 13)
+		x[10] ^= bits.RotateLeft32(x[6]+x[2], 18)
+		x[3] ^= bits.RotateLeft32(x[15]+x[11], 7)
+		x[7] ^= bits.RotateLeft32(x[3]+x[15], 9)
+		x[11] ^= bits.RotateLeft32(x[7]+x[3], 13)
+		x[15] ^= bits.RotateLeft32(x[11]+x[7], 18)
+
+		// Rows
+		x[1] ^= bits.RotateLeft32(x[0]+x[3], 7)
+		x[2] ^= bits.RotateLeft32(x[1]+x[0], 9)
+		x[3] ^= bits.RotateLeft32(x[2]+x[1], 13)
+		x[0] ^= bits.RotateLeft32(x[3]+x[2], 18)
+		x[6] ^= bits.RotateLeft32(x[5]+x[4], 7)
+		x[7] ^= bits.RotateLeft32(x[6]+x[5], 9)
+		x[4] ^= bits.RotateLeft32(x[7]+x[6], 13)
+		x[5] ^= bits.RotateLeft32(x[4]+x[7], 18)
+		x[11] ^= bits.RotateLeft32(x[10]+x[9], 7)
+		x[8] ^= bits.RotateLeft32(x[11]+x[10], 9)
+		x[9] ^= bits.RotateLeft32(x[8]+x[11], 13)
+		x[10] ^= bits.RotateLeft32(x[9]+x[8], 18)
+		x[12] ^= bits.RotateLeft32(x[15]+x[14], 7)
+		x[13] ^= bits.RotateLeft32(x[12]+x[15], 9)
+		x[14] ^= bits.RotateLeft32(x[13]+x[12], 13)
+		x[15] ^= bits.RotateLeft32(x[14]+x[13], 18)
+	}
+
+	for i := range b {
+		b[i] += x[i]
+	}
+}
+
+// blockMix applies scryptBlockMix to b (32*r words), using y as scratch
+// space of the same size.
+// c.f. RFC7914 4
+func blockMix(b, y []uint32, r int) {
+	// Step 1: X = B[2 * r - 1]
+	var x [16]uint32
+	copy(x[:], b[(2*r-1)*16:])
+
+	// Step 2: X = Salsa(X xor B[i]), Y[i] = X
+	for i := 0; i < 2*r; i++ {
+		for j := range x {
+			x[j] ^= b[i*16+j]
+		}
+		salsa208(&x)
+
+		// Step 3: B' = (Y[0], Y[2], ..., Y[2 * r - 2], Y[1], Y[3], ..., Y[2 * r - 1])
+		dst := (i/2)*16 + (i%2)*r*16
+		copy(y[dst:dst+16], x[:])
+	}
+
+	copy(b, y)
+}
+
+// integerify returns the first word of the last 64B block of b, as needed
+// by ROMix.
+func integerify(b []uint32, r int) uint64 {
+	j := (2*r - 1) * 16
+	return uint64(b[j]) | uint64(b[j+1])<<32
+}
+
+// roMix applies scryptROMix to the 128*r bytes of b in place.
+// v is scratch space of 32*r*N words, xy of 64*r words.
+// c.f. RFC7914 5
+func roMix(b []byte, r, N int, v, xy []uint32) {
+	x := xy[:32*r]
+	y := xy[32*r:]
+
+	// Step 1: X = B
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+
+	// Step 2: V[i] = X, X = scryptBlockMix(X)
+	for i := 0; i < N; i++ {
+		copy(v[i*32*r:], x)
+		blockMix(x, y, r)
+	}
+
+	// Step 3: j = Integerify(X) mod N, X = scryptBlockMix(X xor V[j])
+	for i := 0; i < N; i++ {
+		j := int(integerify(x, r) & uint64(N-1))
+		vj := v[j*32*r : (j+1)*32*r]
+		for k := range x {
+			x[k] ^= vj[k]
+		}
+		blockMix(x, y, r)
+	}
+
+	// Step 4: B' = X
+	for i, w := range x {
+		binary.LittleEndian.PutUint32(b[4*i:], w)
+	}
+}
+
+// Memory returns the number of bytes Key allocates for the given
+// parameters, or -1 if it does not fit in an int.
+func Memory(N, r, p int) int {
+	if r <= 0 || p <= 0 || N <= 0 || r > maxInt/128/p || r > maxInt/256 || N > maxInt/128/r {
+		return -1
+	}
+
+	// B, XY and V, the p lanes are computed one after the other
+	return 128*r*p + 256*r + 128*r*N
+}
+
+// Key derives a key of keyLen bytes from password and salt.
+// N is the CPU/memory cost parameter and must be a power of 2 greater than 1,
+// r the block size and p the parallelization parameter, with r * p < 2^30.
+// Recommended parameters for interactive logins as of 2017 are N=32768, r=8
+// and p=1.
+func Key(password, salt []byte, N, r, p, keyLen int) ([]byte, error) {
+	return KeyLimit(password, salt, N, r, p, keyLen, maxInt)
+}
+
+// KeyLimit is like Key but returns an error instead of using more than
+// maxMemory bytes, c.f. Memory.
+func KeyLimit(password, salt []byte, N, r, p, keyLen, maxMemory int) ([]byte, error) {
+	if N <= 1 || N&(N-1) != 0 {
+		return nil, errors.New("scrypt: N must be > 1 and a power of 2")
+	}
+	if r <= 0 || p <= 0 {
+		return nil, errors.New("scrypt: r and p must be positive")
+	}
+	if uint64(r)*uint64(p) >= 1<<30 {
+		return nil, errors.New("scrypt: r * p must be < 2^30")
+	}
+	if 16*r < 64 && uint64(N) >= uint64(1)<<(16*r) {
+		return nil, errors.New("scrypt: N must be < 2^(128 * r / 8)")
+	}
+
+	mem := Memory(N, r, p)
+	if mem < 0 {
+		return nil, errors.New("scrypt: parameters are too large")
+	}
+	if mem > maxMemory {
+		return nil, errors.New("scrypt: parameters need too much memory")
+	}
+
+	// Step 1: B = PBKDF2-HMAC-SHA256(P, S, 1, p * 128 * r)
+	b, err := pbkdf2.Key(sha256.New256, password, salt, 1, p*128*r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: B[i] = scryptROMix(r, B[i], N)
+	xy := make([]uint32, 64*r)
+	v := make([]uint32, 32*r*N)
+	for i := 0; i < p; i++ {
+		roMix(b[i*128*r:], r, N, v, xy)
+	}
+
+	// Step 3: DK = PBKDF2-HMAC-SHA256(P, B, 1, dkLen)
+	return pbkdf2.Key(sha256.New256, password, b, 1, keyLen)
+}
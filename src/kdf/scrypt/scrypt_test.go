@@ -0,0 +1,98 @@
+package scrypt
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+)
+
+func fromHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// RFC 7914 section 8
+func TestSalsa208(t *testing.T) {
+	in := fromHex("7e879a214f3ec9867ca940e641718f26baee555b8c61c1b50df846116dcd3b1dee24f319df9b3d8514121e4b5ac5aa3276021d2909c74829edebc68db8b8c25e")
+	expHex := "a41f859c6608cc993b81cacb020cef05044b2181a2fd337dfd7b1c6396682f29b4393168e3c9e6bcfe6bc5b7a06d96bae424cc102c91745c24ad673dc7618f81"
+
+	var b [16]uint32
+	for i := range b {
+		b[i] = binary.LittleEndian.Uint32(in[4*i:])
+	}
+
+	salsa208(&b)
+
+	res := make([]byte, 0, 64)
+	for _, w := range b {
+		res = binary.LittleEndian.AppendUint32(res, w)
+	}
+
+	if resHex := hex.EncodeToString(res); resHex != expHex {
+		t.Errorf("Not equal %s != %s", resHex, expHex)
+	}
+}
+
+// RFC 7914 section 12, without the N = 1048576 case
+func TestRFC7914(t *testing.T) {
+	vectors := []struct {
+		password, salt string
+		N, r, p        int
+		expHex         string
+	}{
+		{"", "", 16, 1, 1, "77d6576238657b203b19ca42c18a0497f16b4844e3074ae8dfdffa3fede21442fcd0069ded0948f8326a753a0fc81f17e8d3e0fb2e0d3628cf35e20c38d18906"},
+		{"password", "NaCl", 1024, 8, 16, "fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b3731622eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640"},
+		{"pleaseletmein", "SodiumChloride", 16384, 8, 1, "7023bdcb3afd7348461c06cd81fd38ebfda8fbba904f8e3ea9b543f6545da1f2d5432955613f0fcf62d49705242a9af9e61e85dc0d651e40dfcf017b45575887"},
+	}
+
+	for _, v := range vectors {
+		res, err := Key([]byte(v.password), []byte(v.salt), v.N, v.r, v.p, 64)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if resHex := hex.EncodeToString(res); resHex != v.expHex {
+			t.Errorf("scrypt(%q, %q, %d, %d, %d): %s != %s", v.password, v.salt, v.N, v.r, v.p, resHex, v.expHex)
+		}
+	}
+}
+
+func TestInvalidParameters(t *testing.T) {
+	params := [][3]int{
+		// N not a power of 2
+		{0, 1, 1},
+		{1, 1, 1},
+		{1000, 1, 1},
+		// r and p
+		{16, 0, 1},
+		{16, 1, 0},
+		{16, 1 << 15, 1 << 15},
+		// N >= 2^(128 * r / 8)
+		{1 << 16, 1, 1},
+	}
+
+	for _, param := range params {
+		if _, err := Key([]byte("p"), []byte("s"), param[0], param[1], param[2], 32); err == nil {
+			t.Errorf("parameters %v accepted", param)
+		}
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	N, r, p := 1024, 8, 1
+	mem := Memory(N, r, p)
+
+	if mem != 128*r*p+256*r+128*r*N {
+		t.Errorf("Memory(%d, %d, %d) = %d", N, r, p, mem)
+	}
+
+	if _, err := KeyLimit([]byte("p"), []byte("s"), N, r, p, 32, mem-1); err == nil {
+		t.Error("memory limit ignored")
+	}
+	if _, err := KeyLimit([]byte("p"), []byte("s"), N, r, p, 32, mem); err != nil {
+		t.Error(err.Error())
+	}
+}
@@ -0,0 +1,209 @@
+// Package sp800108 implements the key-based key derivation functions of
+// NIST SP 800-108r1 (https://doi.org/10.6028/NIST.SP.800-108r1): counter
+// mode, feedback mode and double-pipeline iteration mode.
+//
+// The pseudorandom function is HMAC with any hash of the library, or any
+// other MAC exposed as a hash.Hash, such as CMAC.
+package sp800108
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"hash"
+
+	"github.com/loicbacciga/crypto-go/src/mac/cmac"
+	"github.com/loicbacciga/crypto-go/src/mac/hmac"
+)
+
+// PRF returns the pseudorandom function keyed with key.
+// Returns an error if key is not a valid key of the PRF.
+type PRF func(key []byte) (hash.Hash, error)
+
+// HMAC returns the PRF HMAC with the hash h.
+func HMAC(h func() hash.Hash) PRF {
+	return func(key []byte) (hash.Hash, error) {
+		return hmac.New(h, key), nil
+	}
+}
+
+// CMAC returns the PRF CMAC with the block cipher created by newCipher.
+// The PRF returns the error of newCipher if the key is invalid for the
+// cipher.
+func CMAC(newCipher func(key []byte) (cipher.Block, error)) PRF {
+	return func(key []byte) (hash.Hash, error) {
+		b, err := newCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		return cmac.New(b)
+	}
+}
+
+// Location is the position of the counter in the input of the PRF.
+type Location int
+
+const (
+	// BeforeFixed places the counter right before the fixed input data:
+	// [i] || Fixed in counter mode, K(i-1) || [i] || Fixed in feedback mode
+	// and A(i) || [i] || Fixed in double-pipeline mode.
+	BeforeFixed Location = iota
+	// AfterFixed places the counter after the fixed input data.
+	AfterFixed
+	// MiddleFixed places the counter inside the fixed input data, after
+	// its first Params.Split bytes.
+	MiddleFixed
+	// BeforeIter places the counter before the iteration variable, in
+	// feedback and double-pipeline modes only: [i] || K(i-1) || Fixed.
+	BeforeIter
+	// NoCounter omits the counter, in feedback and double-pipeline modes
+	// only.
+	NoCounter
+)
+
+// Params are the options of the input of the PRF.
+// The zero value is a 32 bits counter before the fixed input data.
+type Params struct {
+	// CounterBits is the length r of the counter: 8, 16, 24 or 32.
+	// 0 means 32.
+	CounterBits int
+	// Location of the counter
+	Location Location
+	// Split is the number of bytes of the fixed input data before the
+	// counter, for MiddleFixed.
+	Split int
+}
+
+// FixedInput returns the fixed input data Label || 0x00 || Context || [L]_32
+// recommended by SP 800-108r1 section 4, for a key of keyLength bytes.
+func FixedInput(label, context []byte, keyLength int) []byte {
+	res := make([]byte, 0, len(label)+1+len(context)+4)
+	res = append(res, label...)
+	res = append(res, 0)
+	res = append(res, context...)
+	res = binary.BigEndian.AppendUint32(res, uint32(keyLength*8))
+
+	return res
+}
+
+// Key derives a key of keyLength bytes from key, label and context with the
+// counter mode, a 32 bits counter and the fixed input data of FixedInput.
+func Key(prf PRF, key, label, context []byte, keyLength int) ([]byte, error) {
+	return Counter(prf, key, FixedInput(label, context, keyLength), keyLength, Params{})
+}
+
+// Counter derives a key of keyLength bytes from key and the fixed input
+// data with the counter mode:
+// K(i) = PRF(KI, [i] || Fixed)
+// c.f. SP 800-108r1 4.1
+func Counter(prf PRF, key, fixed []byte, keyLength int, p Params) ([]byte, error) {
+	if p.Location == BeforeIter || p.Location == NoCounter {
+		return nil, errors.New("sp800108: counter mode requires a counter before, after or inside the fixed input data")
+	}
+
+	mac, err := prf(key)
+	if err != nil {
+		return nil, err
+	}
+	return derive(mac, nil, fixed, keyLength, p, func(i int, _ []byte) []byte {
+		return nil
+	})
+}
+
+// Feedback derives a key of keyLength bytes from key, the initial value iv
+// and the fixed input data with the feedback mode:
+// K(i) = PRF(KI, K(i-1) || [i] || Fixed), with K(0) = iv.
+// iv may be empty.
+// c.f. SP 800-108r1 4.2
+func Feedback(prf PRF, key, iv, fixed []byte, keyLength int, p Params) ([]byte, error) {
+	mac, err := prf(key)
+	if err != nil {
+		return nil, err
+	}
+	return derive(mac, iv, fixed, keyLength, p, func(_ int, prev []byte) []byte {
+		return prev
+	})
+}
+
+// DoublePipeline derives a key of keyLength bytes from key and the fixed
+// input data with the double-pipeline iteration mode:
+// A(i) = PRF(KI, A(i-1)), with A(0) = Fixed,
+// K(i) = PRF(KI, A(i) || [i] || Fixed).
+// c.f. SP 800-108r1 4.3
+func DoublePipeline(prf PRF, key, fixed []byte, keyLength int, p Params) ([]byte, error) {
+	mac, err := prf(key)
+	if err != nil {
+		return nil, err
+	}
+	a := fixed
+	return derive(mac, nil, fixed, keyLength, p, func(_ int, _ []byte) []byte {
+		mac.Reset()
+		mac.Write(a)
+		a = mac.Sum(nil)
+		return a
+	})
+}
+
+// derive runs the iterations K(i) = PRF(KI, iter(i, K(i-1)) || [i] || Fixed),
+// with the counter at the position given by p.
+func derive(mac hash.Hash, k0, fixed []byte, keyLength int, p Params, iter func(i int, prev []byte) []byte) ([]byte, error) {
+	r := p.CounterBits
+	if r == 0 {
+		r = 32
+	}
+	if r%8 != 0 || r > 32 {
+		return nil, errors.New("sp800108: counter length must be 8, 16, 24 or 32 bits")
+	}
+	if p.Location < BeforeFixed || p.Location > NoCounter {
+		return nil, errors.New("sp800108: invalid counter location")
+	}
+	if p.Location == MiddleFixed && (p.Split < 0 || p.Split > len(fixed)) {
+		return nil, errors.New("sp800108: invalid split of the fixed input data")
+	}
+	if keyLength < 0 {
+		return nil, errors.New("sp800108: negative key length")
+	}
+
+	// n = ceil(L / h) must be at most 2^r - 1, and L must fit in 32 bits
+	h := mac.Size()
+	n := (keyLength + h - 1) / h
+	if uint64(keyLength)*8 > 0xffffffff || (p.Location != NoCounter && uint64(n) > 1<<r-1) {
+		return nil, errors.New("sp800108: derived key too long")
+	}
+
+	res := make([]byte, 0, n*h)
+	prev := k0
+	counter := make([]byte, 4)
+	for i := 1; i <= n; i++ {
+		binary.BigEndian.PutUint32(counter, uint32(i))
+		ctr := counter[4-r/8:]
+
+		it := iter(i, prev)
+
+		mac.Reset()
+		if p.Location == BeforeIter {
+			mac.Write(ctr)
+		}
+		mac.Write(it)
+		switch p.Location {
+		case BeforeFixed:
+			mac.Write(ctr)
+			mac.Write(fixed)
+		case AfterFixed:
+			mac.Write(fixed)
+			mac.Write(ctr)
+		case MiddleFixed:
+			mac.Write(fixed[:p.Split])
+			mac.Write(ctr)
+			mac.Write(fixed[p.Split:])
+		default:
+			mac.Write(fixed)
+		}
+
+		prev = mac.Sum(nil)
+		res = append(res, prev...)
+	}
+
+	return res[:keyLength], nil
+}
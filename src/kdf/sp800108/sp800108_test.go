@@ -0,0 +1,274 @@
+package sp800108
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/hex"
+	"hash"
+	"testing"
+
+	"github.com/loicbacciga/crypto-go/src/cipher/camellia"
+	"github.com/loicbacciga/crypto-go/src/cipher/des"
+	"github.com/loicbacciga/crypto-go/src/hash/sha1"
+	"github.com/loicbacciga/crypto-go/src/hash/sha256"
+	"github.com/loicbacciga/crypto-go/src/hash/sha512"
+	"github.com/loicbacciga/crypto-go/src/mac/hmac"
+)
+
+func seq(from, to int) []byte {
+	res := make([]byte, 0, to-from)
+	for i := from; i < to; i++ {
+		res = append(res, byte(i))
+	}
+	return res
+}
+
+// CAVP KBKDF vectors (KDFCTR_gen.rsp), COUNT=0 of each section
+func TestCounterCAVP(t *testing.T) {
+	vectors := []struct {
+		prf                  PRF
+		p                    Params
+		keyHex, fixedHex, ko string
+	}{
+		// [PRF=CMAC_AES128] [CTRLOCATION=BEFORE_FIXED] [RLEN=8_BITS]
+		{
+			CMAC(aes.NewCipher), Params{CounterBits: 8, Location: BeforeFixed},
+			"dff1e50ac0b69dc40f1051d46c2b069c",
+			"c16e6e02c5a3dcc8d78b9ac1306877761310455b4e41469951d9e6c2245a064b33fd8c3b01203a7824485bf0a64060c4648b707d2607935699316ea5",
+			"8be8f0869b3c0ba97b71863d1b9f7813",
+		},
+		// [PRF=CMAC_AES128] [CTRLOCATION=BEFORE_FIXED] [RLEN=32_BITS]
+		{
+			CMAC(aes.NewCipher), Params{CounterBits: 32, Location: BeforeFixed},
+			"c10b152e8c97b77e18704e0f0bd38305",
+			"98cd4cbbbebe15d17dc86e6dbad800a2dcbd64f7c7ad0e78e9cf94ffdba89d03e97eadf6c4f7b806caf52aa38f09d0eb71d71f497bcc6906b48d36c4",
+			"26faf61908ad9ee881b8305c221db53f",
+		},
+		// [PRF=CMAC_AES128] [CTRLOCATION=AFTER_FIXED] [RLEN=8_BITS]
+		{
+			CMAC(aes.NewCipher), Params{CounterBits: 8, Location: AfterFixed},
+			"e61a51e1633e7d0de704dcebbd8f962f",
+			"5eef88f8cb188e63e08e23c957ee424a3345da88400c567548b57693931a847501f8e1bce1c37a09ef8c6e2ad553dd0f603b52cc6d4e4cbb76eb6c8f",
+			"63a5647d0fe69d21fc420b1a8ce34cc1",
+		},
+		// [PRF=HMAC_SHA1] [CTRLOCATION=BEFORE_FIXED] [RLEN=8_BITS]
+		{
+			HMAC(sha1.New), Params{CounterBits: 8, Location: BeforeFixed},
+			"00a39bd547fb88b2d98727cf64c195c61e1cad6c",
+			"98132c1ffaf59ae5cbc0a3133d84c551bb97e0c75ecaddfc30056f6876f59803009bffc7d75c4ed46f40b8f80426750d15bc1ddb14ac5dcb69a68242",
+			"0611e1903609b47ad7a5fc2c82e47702",
+		},
+		// [PRF=HMAC_SHA256] [CTRLOCATION=BEFORE_FIXED] [RLEN=32_BITS]
+		{
+			HMAC(sha256.New256), Params{CounterBits: 32, Location: BeforeFixed},
+			"dd1d91b7d90b2bd3138533ce92b272fbf8a369316aefe242e659cc0ae238afe0",
+			"01322b96b30acd197979444e468e1c5c6859bf1b1cf951b7e725303e237e46b864a145fab25e517b08f8683d0315bb2911d80a0e8aba17f3b413faac",
+			"10621342bfb0fd40046c0e29f2cfdbf0",
+		},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		fixed, _ := hex.DecodeString(v.fixedHex)
+		res, err := Counter(v.prf, key, fixed, len(v.ko)/2, v.p)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if resHex := hex.EncodeToString(res); resHex != v.ko {
+			t.Errorf("Not equal %s!=%s", resHex, v.ko)
+		}
+
+		// The counter in the middle of the fixed input data, at either end
+		split := 0
+		if v.p.Location == AfterFixed {
+			split = len(fixed)
+		}
+		res, err = Counter(v.prf, key, fixed, len(v.ko)/2, Params{CounterBits: v.p.CounterBits, Location: MiddleFixed, Split: split})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if resHex := hex.EncodeToString(res); resHex != v.ko {
+			t.Errorf("Not equal %s!=%s", resHex, v.ko)
+		}
+	}
+}
+
+// RFC 8009 section 5 uses the counter mode with HMAC-SHA2 and the fixed input
+// data of FixedInput, with an empty context
+func TestRFC8009(t *testing.T) {
+	vectors := []struct {
+		h                  func() hash.Hash
+		keyHex, label, exp string
+	}{
+		{sha256.New256, "3705d96080c17728a0e800eab6e0d23c", "0000000299", "b31a018a48f54776f403e9a396325dc3"},
+		{sha256.New256, "3705d96080c17728a0e800eab6e0d23c", "00000002aa", "9b197dd1e8c5609d6e67c3e37c62c72e"},
+		{sha256.New256, "3705d96080c17728a0e800eab6e0d23c", "0000000255", "9fda0e56ab2d85e1569a688696c26a6c"},
+		{sha512.New384, "6d404d37faf79f9df0d33568d320669800eb4836472ea8a026d16b7182460c52", "0000000299", "ef5718be86cc84963d8bbb5031e9f5c4ba41f28faf69e73d"},
+		{sha512.New384, "6d404d37faf79f9df0d33568d320669800eb4836472ea8a026d16b7182460c52", "00000002aa", "56ab22bee63d82d7bc5227f6773f8ea7a5eb1c825160c38312980c442e5c7e49"},
+		{sha512.New384, "6d404d37faf79f9df0d33568d320669800eb4836472ea8a026d16b7182460c52", "0000000255", "69b16514e3cd8e56b82010d5c73012b622c4d00ffc23ed1f"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		label, _ := hex.DecodeString(v.label)
+		res, err := Key(HMAC(v.h), key, label, nil, len(v.exp)/2)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if resHex := hex.EncodeToString(res); resHex != v.exp {
+			t.Errorf("Not equal %s!=%s", resHex, v.exp)
+		}
+	}
+}
+
+// RFC 6803 section 10 uses the feedback mode with CMAC-Camellia, a 32 bits
+// counter and an IV of zeros
+func TestRFC6803(t *testing.T) {
+	vectors := []struct {
+		keyHex, label, exp string
+	}{
+		{"57d0297298ffd9d35de5a47fb4bde24b", "0000000299", "d155775a209d05f02b38d42a389e5a56"},
+		{"57d0297298ffd9d35de5a47fb4bde24b", "00000002aa", "64df83f85a532f17577d8c37035796ab"},
+		{"57d0297298ffd9d35de5a47fb4bde24b", "0000000255", "3e4fbdf30fb8259c425cb6c96f1f4635"},
+		{"b9d6828b2056b7be656d88a123b1fac68214ac2b727ecf5f69afe0c4df2a6d2c", "0000000299", "e467f9a9552bc7d3155a6220af9c19220eeed4ff78b0d1e6a1544991461a9e50"},
+		{"b9d6828b2056b7be656d88a123b1fac68214ac2b727ecf5f69afe0c4df2a6d2c", "00000002aa", "412aefc362a7285fc3966c6a5181e7605ae675235b6d549fbfc9ab6630a4c604"},
+		{"b9d6828b2056b7be656d88a123b1fac68214ac2b727ecf5f69afe0c4df2a6d2c", "0000000255", "fa624fa0e523993fa388aefdc67e67ebcd8c08e8a0246b1d73b0d1dd9fc582b0"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		label, _ := hex.DecodeString(v.label)
+		fixed := FixedInput(label, nil, len(v.exp)/2)
+		res, err := Feedback(CMAC(camellia.New), key, make([]byte, camellia.BlockSize), fixed, len(v.exp)/2, Params{})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if resHex := hex.EncodeToString(res); resHex != v.exp {
+			t.Errorf("Not equal %s!=%s", resHex, v.exp)
+		}
+	}
+}
+
+// HKDF-Expand is the feedback mode with HMAC, no IV and an 8 bits counter
+// after the fixed input data, c.f. RFC 5869 appendix A
+func TestRFC5869(t *testing.T) {
+	prk, _ := hex.DecodeString("077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+
+	res, err := Feedback(HMAC(sha256.New256), prk, nil, info, 42, Params{CounterBits: 8, Location: AfterFixed})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	exp := "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865"
+	if resHex := hex.EncodeToString(res); resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+}
+
+// Interoperability vectors generated with pyca/cryptography and OpenSSL, for
+// the variants without reproducible published vectors
+func TestInterop(t *testing.T) {
+	key := seq(0, 32)
+
+	res, err := Counter(HMAC(sha512.New512), key, seq(100, 140), 100, Params{CounterBits: 16, Location: MiddleFixed, Split: 10})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	exp := "0f074e58757f68a8fc1bd6f49225d1a4bc1cdb0c8106f442a653c4fca5fb467ad66f641c8273df9cdf6ef43512c97ece7aa11ada14e98a7365f4ccee6d9c7e0babe7a5a1056abb80568a6a7bf5336baf2363501c7b2e86dc68ca7406c614326991faee2e"
+	if resHex := hex.EncodeToString(res); resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+
+	fixed := FixedInput([]byte("label"), []byte("context"), 40)
+	res, err = Feedback(CMAC(des.NewTriple), seq(0, 24), seq(0xf0, 0xf8), fixed, 40, Params{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	exp = "f47fb306940bbbc65466c65fbdec139e3a2df467dc5441f6cd95d9e3a00262e55c162c126409f54b"
+	if resHex := hex.EncodeToString(res); resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+}
+
+func TestDoublePipeline(t *testing.T) {
+	// The CAVP double-pipeline vectors (KDFDblPipeline_gen.rsp) are not
+	// reproduced here, check against the definition
+	key := seq(0, 32)
+	fixed := seq(100, 140)
+
+	locations := []Params{
+		{},
+		{CounterBits: 8, Location: AfterFixed},
+		{CounterBits: 24, Location: BeforeIter},
+		{Location: NoCounter},
+	}
+
+	for _, p := range locations {
+		res, err := DoublePipeline(HMAC(sha256.New256), key, fixed, 80, p)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		var exp []byte
+		a := fixed
+		for i := 1; len(exp) < 80; i++ {
+			mac := hmac.New(sha256.New256, key)
+			mac.Write(a)
+			a = mac.Sum(nil)
+
+			ctr := []byte{0, 0, 0, byte(i)}
+			var input []byte
+			switch p.Location {
+			case BeforeFixed:
+				input = bytes.Join([][]byte{a, ctr, fixed}, nil)
+			case AfterFixed:
+				input = bytes.Join([][]byte{a, fixed, ctr[3:]}, nil)
+			case BeforeIter:
+				input = bytes.Join([][]byte{ctr[1:], a, fixed}, nil)
+			case NoCounter:
+				input = bytes.Join([][]byte{a, fixed}, nil)
+			}
+
+			mac = hmac.New(sha256.New256, key)
+			mac.Write(input)
+			exp = mac.Sum(exp)
+		}
+
+		if !bytes.Equal(res, exp[:80]) {
+			t.Errorf("%v: Not equal %x!=%x", p, res, exp[:80])
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	prf := HMAC(sha256.New256)
+	key := seq(0, 32)
+
+	if _, err := Counter(prf, key, nil, 32, Params{Location: NoCounter}); err == nil {
+		t.Error("counter mode without counter accepted")
+	}
+	if _, err := Counter(prf, key, nil, 32, Params{CounterBits: 12}); err == nil {
+		t.Error("invalid counter length accepted")
+	}
+	if _, err := Counter(prf, key, nil, 32, Params{Location: MiddleFixed, Split: 1}); err == nil {
+		t.Error("invalid split accepted")
+	}
+	if _, err := Feedback(prf, key, nil, nil, 256*32, Params{CounterBits: 8}); err == nil {
+		t.Error("too long key accepted")
+	}
+	if _, err := Feedback(prf, key, nil, nil, 255*32, Params{CounterBits: 8}); err != nil {
+		t.Error(err.Error())
+	}
+
+	// Invalid AES key
+	cmacPRF := CMAC(aes.NewCipher)
+	if _, err := Counter(cmacPRF, key[:15], nil, 16, Params{}); err == nil {
+		t.Error("invalid CMAC key accepted")
+	}
+	if _, err := Feedback(cmacPRF, key[:15], nil, nil, 16, Params{}); err == nil {
+		t.Error("invalid CMAC key accepted")
+	}
+	if _, err := DoublePipeline(cmacPRF, key[:15], nil, 16, Params{}); err == nil {
+		t.Error("invalid CMAC key accepted")
+	}
+}
@@ -0,0 +1,87 @@
+// Package sp80056c implements the key derivation functions used after a
+// key establishment scheme, as defined in NIST SP 800-56Cr2
+// (https://doi.org/10.6028/NIST.SP.800-56Cr2): the one-step KDF with a hash
+// or HMAC, and the two-step extraction-then-expansion KDF.
+package sp80056c
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+
+	"github.com/loicbacciga/crypto-go/src/kdf/sp800108"
+	"github.com/loicbacciga/crypto-go/src/mac/hmac"
+)
+
+// OneStep derives a key of keyLength bytes from the shared secret z and
+// fixedInfo, with the auxiliary function H = h:
+// K(i) = H([i]_32 || Z || FixedInfo)
+// c.f. SP 800-56Cr2 4.1, option 1
+func OneStep(h func() hash.Hash, z, fixedInfo []byte, keyLength int) ([]byte, error) {
+	return oneStep(h(), z, fixedInfo, keyLength)
+}
+
+// OneStepHMAC derives a key of keyLength bytes from the shared secret z and
+// fixedInfo, with the auxiliary function H = HMAC-h keyed with salt.
+// If salt is nil, a string of zeros of the block size of h is used.
+// c.f. SP 800-56Cr2 4.1, option 2
+func OneStepHMAC(h func() hash.Hash, z, salt, fixedInfo []byte, keyLength int) ([]byte, error) {
+	if salt == nil {
+		salt = make([]byte, h().BlockSize())
+	}
+
+	return oneStep(hmac.New(h, salt), z, fixedInfo, keyLength)
+}
+
+func oneStep(aux hash.Hash, z, fixedInfo []byte, keyLength int) ([]byte, error) {
+	if keyLength < 0 {
+		return nil, errors.New("sp80056c: negative key length")
+	}
+
+	// reps = ceil(L / H_outputBits) must be at most 2^32 - 1
+	hLen := aux.Size()
+	reps := (keyLength + hLen - 1) / hLen
+	if uint64(reps) > 0xffffffff {
+		return nil, errors.New("sp80056c: derived key too long")
+	}
+
+	res := make([]byte, 0, reps*hLen)
+	for i := 1; i <= reps; i++ {
+		aux.Reset()
+		aux.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+		aux.Write(z)
+		aux.Write(fixedInfo)
+		res = aux.Sum(res)
+	}
+
+	return res[:keyLength], nil
+}
+
+// Extract is the randomness extraction step of the two-step KDF:
+// K_DK = MAC(salt, Z), where the MAC is HMAC or AES-CMAC.
+// Returns an error if salt is not a valid key of prf.
+// c.f. SP 800-56Cr2 5.1
+func Extract(prf sp800108.PRF, salt, z []byte) ([]byte, error) {
+	mac, err := prf(salt)
+	if err != nil {
+		return nil, err
+	}
+	mac.Write(z)
+
+	return mac.Sum(nil), nil
+}
+
+// TwoStep derives a key of keyLength bytes from the shared secret z, salt
+// and fixedInfo with Extract, followed by the SP 800-108 counter mode with a
+// 32 bits counter before fixedInfo.
+// Other expansion modes can be used by calling sp800108 on the output of
+// Extract.
+// c.f. SP 800-56Cr2 5
+func TwoStep(prf sp800108.PRF, salt, z, fixedInfo []byte, keyLength int) ([]byte, error) {
+	kdk, err := Extract(prf, salt, z)
+	if err != nil {
+		return nil, err
+	}
+
+	return sp800108.Counter(prf, kdk, fixedInfo, keyLength, sp800108.Params{})
+}
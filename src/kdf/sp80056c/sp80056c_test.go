@@ -0,0 +1,136 @@
+package sp80056c
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/loicbacciga/crypto-go/src/hash/sha256"
+	"github.com/loicbacciga/crypto-go/src/hash/sha512"
+	"github.com/loicbacciga/crypto-go/src/kdf/sp800108"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return b
+}
+
+// CAVP SP 800-56A KAS vectors, as used by pyca/cryptography
+func TestOneStepCAVP(t *testing.T) {
+	z := decodeHex(t, "52169af5c485dcc2321eb8d26d5efa21fb9b93c98e38412ee2484cf14f0d0d23")
+	info := decodeHex(t, "a1b2c3d4e53728157e634612c12d6d5223e204aeea4341565369647bd184bcd246f72971f292badaa2fe4124612cba")
+
+	res, err := OneStep(sha256.New256, z, info, 16)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	exp := "1c3bc9e7c4547c5191c0d478cccaed55"
+	if resHex := hex.EncodeToString(res); resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+
+	z = decodeHex(t, "013951627c1dea63ea2d7702dd24e963eef5faac6b4af7e4b831cde499dff1ce45f6179f741c728aa733583b024092088f0af7fce1d045edbc5790931e8d5ca79c73")
+	info = decodeHex(t, "a1b2c3d4e55e600be5f367e0e8a465f4bf2704db00c9325c9fbd216d12b49160b2ae5157650f43415653696421e68e")
+
+	res, err = OneStepHMAC(sha512.New512, z, nil, info, 32)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	exp = "64ce901db10d558661f10b6836a122a7605323ce2f39bf27eaaac8b34cf89f2f"
+	if resHex := hex.EncodeToString(res); resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+}
+
+// HKDF is the two-step KDF with HMAC extraction and the SP 800-108 feedback
+// mode, c.f. SP 800-56Cr2 5 and RFC 5869 appendix A
+func TestTwoStepHKDF(t *testing.T) {
+	vectors := []struct {
+		ikm, salt, info, prk, okm string
+	}{
+		{
+			"0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+			"000102030405060708090a0b0c",
+			"f0f1f2f3f4f5f6f7f8f9",
+			"077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5",
+			"3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865",
+		},
+		{
+			"0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+			"",
+			"",
+			"19ef24a32c717b167f33a91d6f648bdf96596776afdb6377ac434c1c293ccb04",
+			"8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8",
+		},
+	}
+
+	prf := sp800108.HMAC(sha256.New256)
+	for _, v := range vectors {
+		kdk, err := Extract(prf, decodeHex(t, v.salt), decodeHex(t, v.ikm))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if resHex := hex.EncodeToString(kdk); resHex != v.prk {
+			t.Errorf("Not equal %s!=%s", resHex, v.prk)
+		}
+
+		res, err := sp800108.Feedback(prf, kdk, nil, decodeHex(t, v.info), len(v.okm)/2, sp800108.Params{CounterBits: 8, Location: sp800108.AfterFixed})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if resHex := hex.EncodeToString(res); resHex != v.okm {
+			t.Errorf("Not equal %s!=%s", resHex, v.okm)
+		}
+	}
+}
+
+// The AES-CMAC extraction is AES-CMAC-PRF-128 with a 128 bits salt, c.f.
+// RFC 4615 section 4
+func TestExtractCMAC(t *testing.T) {
+	salt := decodeHex(t, "000102030405060708090a0b0c0d0e0f")
+	z := decodeHex(t, "000102030405060708090a0b0c0d0e0f10111213")
+
+	kdk, err := Extract(sp800108.CMAC(aes.NewCipher), salt, z)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	exp := "980ae87b5f4c9c5214f5b6a8455e4c2d"
+	if resHex := hex.EncodeToString(kdk); resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+
+	if _, err := Extract(sp800108.CMAC(aes.NewCipher), salt[:15], z); err == nil {
+		t.Errorf("Extract should fail with an invalid AES key")
+	}
+}
+
+// The CAVP SP 800-56C two-step vectors are not reproduced here: TwoStep is
+// checked against Extract and sp800108.Counter, both tested against published
+// vectors above and in sp800108
+func TestTwoStep(t *testing.T) {
+	z := []byte("shared secret")
+	salt := []byte("salt")
+	info := []byte("fixed info")
+	prf := sp800108.HMAC(sha256.New256)
+
+	kdk, err := Extract(prf, salt, z)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	res, err := TwoStep(prf, salt, z, info, 70)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	exp, err := sp800108.Counter(prf, kdk, info, 70, sp800108.Params{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !bytes.Equal(res, exp) {
+		t.Errorf("Not equal %x!=%x", res, exp)
+	}
+}
@@ -0,0 +1,37 @@
+// Package x963 implements the key derivation function of ANSI X9.63
+// (also described in SEC 1 v2.0 3.6.1), used with elliptic curve key
+// agreement.
+package x963
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+// Key derives a key of keyLength bytes from the shared secret z and
+// sharedInfo with the hash h:
+// K(i) = H(Z || [i]_32 || SharedInfo)
+func Key(h func() hash.Hash, z, sharedInfo []byte, keyLength int) ([]byte, error) {
+	if keyLength < 0 {
+		return nil, errors.New("x963: negative key length")
+	}
+
+	d := h()
+	hLen := d.Size()
+	n := (keyLength + hLen - 1) / hLen
+	if uint64(n) > 0xfffffffe {
+		return nil, errors.New("x963: derived key too long")
+	}
+
+	res := make([]byte, 0, n*hLen)
+	for i := 1; i <= n; i++ {
+		d.Reset()
+		d.Write(z)
+		d.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+		d.Write(sharedInfo)
+		res = d.Sum(res)
+	}
+
+	return res[:keyLength], nil
+}
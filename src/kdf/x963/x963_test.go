@@ -0,0 +1,59 @@
+package x963
+
+import (
+	"encoding/hex"
+	"hash"
+	"testing"
+
+	"github.com/loicbacciga/crypto-go/src/hash/sha256"
+	"github.com/loicbacciga/crypto-go/src/hash/sha512"
+)
+
+func TestKey(t *testing.T) {
+	// CAVP ANS X9.63 vectors, as used by pyca/cryptography, and a vector
+	// generated with pyca/cryptography
+	vectors := []struct {
+		h       func() hash.Hash
+		z, info string
+		exp     string
+	}{
+		{
+			sha256.New256,
+			"96c05619d56c328ab95fe84b18264b08725b85e33fd34f08",
+			"",
+			"443024c3dae66b95e6f5670601558f71",
+		},
+		{
+			sha256.New256,
+			"22518b10e70f2a3f243810ae3254139efbee04aa57c7af7d",
+			"75eef81aa3041e33b80971203d2c0c52",
+			"c498af77161cc59f2962b9a713e2b215152d139766ce34a776df11866a69bf2e" +
+				"52a13d9c7c6fc878c50c5ea0bc7b00e0da2447cfd874f6cf92f30d0097111485" +
+				"500c90c3af8b487872d04685d14c8d1dc8d7fa08beb0ce0ababc11f0bd496269" +
+				"142d43525a78e5bc79a17f59676a5706dc54d54d4d1f0bd7e386128ec26afc21",
+		},
+		{
+			sha512.New512,
+			hex.EncodeToString([]byte("secret")),
+			hex.EncodeToString([]byte("info")),
+			"d80043fbbbf35d93bf1bcfa3d98d86789c3db5d9c13fdeccc703cac3788dcfeb" +
+				"21368f4f17058c623e1cb7eddfc8b04469b46a5daaea38dc8032baac39007541" +
+				"cd43a100c4adaec91316d0f21391eaf82019e0ac39f4929cdd692ba19b6a2682" +
+				"6b4ee548",
+		},
+	}
+
+	for _, v := range vectors {
+		z, _ := hex.DecodeString(v.z)
+		info, _ := hex.DecodeString(v.info)
+
+		res, err := Key(v.h, z, info, len(v.exp)/2)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if resHex := hex.EncodeToString(res); resHex != v.exp {
+			t.Errorf("Not equal %s!=%s", resHex, v.exp)
+		}
+	}
+}
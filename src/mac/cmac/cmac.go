@@ -0,0 +1,153 @@
+// Package cmac implements the Cipher-based Message Authentication Code
+// (CMAC, also known as OMAC1) as defined in NIST SP 800-38B
+// (https://doi.org/10.6028/NIST.SP.800-38B) and RFC 4493, for any 64 or 128
+// bits cipher.Block, e.g.
+//
+//	b, err := des.NewTriple(key)
+//	...
+//	mac, err := cmac.New(b)
+//	mac.Write(message)
+//	tag := mac.Sum(nil)
+package cmac
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"log"
+)
+
+// Constants R_b of the subkey generation, for 64 and 128 bits blocks
+// c.f. SP 800-38B 5.3
+const (
+	rb64  = 0x1b
+	rb128 = 0x87
+)
+
+type cmac struct {
+	b         cipher.Block
+	blockSize int
+	// Masks of a complete and of a padded last block
+	k1, k2 []byte
+	// Chaining value
+	x []byte
+	// Unprocessed data, which may be the last block
+	buf []byte
+}
+
+// New returns a new CMAC using the cipher b.
+// Returns an error if the block size of b is not 64 or 128 bits.
+func New(b cipher.Block) (hash.Hash, error) {
+	var rb byte
+	switch b.BlockSize() {
+	case 8:
+		rb = rb64
+	case 16:
+		rb = rb128
+	default:
+		return nil, errors.New("cmac: block size must be 64 or 128 bits")
+	}
+
+	// Subkeys K1 = L.u, K2 = L.u^2, with L = CIPH_K(0^b)
+	// c.f. SP 800-38B 6.1
+	l := make([]byte, b.BlockSize())
+	b.Encrypt(l, l)
+	k1 := shift(l, rb)
+	k2 := shift(k1, rb)
+
+	return NewWithSubkeys(b, k1, k2), nil
+}
+
+// NewWithSubkeys returns a CBC-MAC using the cipher b, whose last block is
+// xored with k1 if it is complete, or padded with 10* and xored with k2.
+// This is CMAC when k1 and k2 are the subkeys generated from b, and can be
+// used to build related MACs such as XCBC.
+func NewWithSubkeys(b cipher.Block, k1, k2 []byte) hash.Hash {
+	blockSize := b.BlockSize()
+	if len(k1) != blockSize || len(k2) != blockSize {
+		log.Panic("cmac: subkeys must be one block long")
+	}
+
+	return &cmac{
+		b:         b,
+		blockSize: blockSize,
+		k1:        bytes.Clone(k1),
+		k2:        bytes.Clone(k2),
+		x:         make([]byte, blockSize),
+		buf:       make([]byte, 0, blockSize),
+	}
+}
+
+// shift computes the multiplication by u in GF(2^b), a left shift of one
+// bit reduced by rb.
+func shift(src []byte, rb byte) []byte {
+	dst := make([]byte, len(src))
+
+	var carry byte
+	for i := len(src) - 1; i >= 0; i-- {
+		dst[i] = src[i]<<1 | carry
+		carry = src[i] >> 7
+	}
+
+	// Constant time conditional xor
+	dst[len(dst)-1] ^= rb & -carry
+
+	return dst
+}
+
+func (c *cmac) Write(p []byte) (n int, err error) {
+	n = len(p)
+
+	// The last block is kept in buf until Sum, so only process a full
+	// buffer when more data follows
+	for len(p) > 0 {
+		if len(c.buf) == c.blockSize {
+			subtle.XORBytes(c.x, c.x, c.buf)
+			c.b.Encrypt(c.x, c.x)
+			c.buf = c.buf[:0]
+		}
+
+		l := c.blockSize - len(c.buf)
+		if l > len(p) {
+			l = len(p)
+		}
+		c.buf = append(c.buf, p[:l]...)
+		p = p[l:]
+	}
+
+	return n, nil
+}
+
+func (c *cmac) Sum(b []byte) []byte {
+	last := make([]byte, c.blockSize)
+	copy(last, c.buf)
+
+	if len(c.buf) == c.blockSize {
+		subtle.XORBytes(last, last, c.k1)
+	} else {
+		last[len(c.buf)] = 0x80
+		subtle.XORBytes(last, last, c.k2)
+	}
+
+	subtle.XORBytes(last, last, c.x)
+	c.b.Encrypt(last, last)
+
+	return append(b, last...)
+}
+
+func (c *cmac) Reset() {
+	for i := range c.x {
+		c.x[i] = 0
+	}
+	c.buf = c.buf[:0]
+}
+
+func (c *cmac) Size() int {
+	return c.blockSize
+}
+
+func (c *cmac) BlockSize() int {
+	return c.blockSize
+}
@@ -0,0 +1,113 @@
+package cmac
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"testing"
+
+	"github.com/loicbacciga/crypto-go/src/cipher/des"
+	"github.com/loicbacciga/crypto-go/src/cipher/utils"
+)
+
+// Message of the examples of SP 800-38B appendix D
+const exampleMessage = "6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e51" +
+	"30c81c46a35ce411e5fbc1191a0a52eff69f2445df4f9b17ad2b417be66c3710"
+
+func checkExamples(t *testing.T, name string, b cipher.Block, lengths []int, expHexs []string) {
+	msg, _ := hex.DecodeString(exampleMessage)
+
+	for i, l := range lengths {
+		mac, err := New(b)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		mac.Write(msg[:l])
+		resHex := hex.EncodeToString(mac.Sum(nil))
+
+		if resHex != expHexs[i] {
+			t.Errorf("%s Mlen %d: %s != %s", name, l*8, resHex, expHexs[i])
+		}
+	}
+}
+
+func TestAES128(t *testing.T) {
+	key, _ := hex.DecodeString("2b7e151628aed2a6abf7158809cf4f3c")
+	b, _ := aes.NewCipher(key)
+
+	checkExamples(t, "CMAC-AES128", b, []int{0, 16, 40, 64}, []string{
+		"bb1d6929e95937287fa37d129b756746",
+		"070a16b46b4d4144f79bdd9dd04a287c",
+		"dfa66747de9ae63030ca32611497c827",
+		"51f0bebf7e3b9d92fc49741779363cfe",
+	})
+}
+
+func TestTDES(t *testing.T) {
+	// 3 keys TDEA, checked with OpenSSL
+	key, _ := hex.DecodeString("8aa83bf8cbda10620bc1bf19fbb6cd58bc313d4a371ca8b5")
+	b, err := des.NewTriple(key)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	checkExamples(t, "CMAC-TDEA3", b, []int{0, 16, 20, 32}, []string{
+		"b7a688e122ffaf95",
+		"286d394673448197",
+		"743ddbe0ce2dc2ed",
+		"33e6b1092400eae5",
+	})
+
+	// 2 keys TDEA
+	key, _ = hex.DecodeString("4cf15134a2850dd58a3d10ba80570d384cf15134a2850dd5")
+	b, err = des.NewTriple(key)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	checkExamples(t, "CMAC-TDEA2", b, []int{0, 16, 20, 32}, []string{
+		"bd2ebf9a3ba00361",
+		"743da9f41b91ec83",
+		"62dd1b471902bd4e",
+		"31b1e431dabc4eb8",
+	})
+}
+
+func TestStreaming(t *testing.T) {
+	key, _ := hex.DecodeString("2b7e151628aed2a6abf7158809cf4f3c")
+	b, _ := aes.NewCipher(key)
+	msg, _ := hex.DecodeString(exampleMessage)
+
+	oneShot, _ := New(b)
+	oneShot.Write(msg)
+	exp := hex.EncodeToString(oneShot.Sum(nil))
+
+	for _, step := range []int{1, 3, 16, 17} {
+		mac, _ := New(b)
+		for i := 0; i < len(msg); i += step {
+			end := i + step
+			if end > len(msg) {
+				end = len(msg)
+			}
+			mac.Write(msg[i:end])
+
+			// Sum does not change the state
+			mac.Sum(nil)
+		}
+
+		if resHex := hex.EncodeToString(mac.Sum(nil)); resHex != exp {
+			t.Errorf("step %d: Not equal %s!=%s", step, resHex, exp)
+		}
+
+		mac.Reset()
+		mac.Write(msg)
+		if resHex := hex.EncodeToString(mac.Sum(nil)); resHex != exp {
+			t.Errorf("reset: Not equal %s!=%s", resHex, exp)
+		}
+	}
+}
+
+func TestInvalidBlockSize(t *testing.T) {
+	if _, err := New(utils.NewDummyCipher(32)); err == nil {
+		t.Error("256 bits block accepted")
+	}
+}
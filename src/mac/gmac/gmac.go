@@ -0,0 +1,196 @@
+// Package gmac implements GMAC, the authentication-only mode of GCM, as
+// defined in NIST SP 800-38D (https://doi.org/10.6028/NIST.SP.800-38D), for
+// any 128 bits cipher.Block. The tag is the GCM tag of an empty plaintext
+// with the written data as additional authenticated data.
+//
+// A nonce must never be used twice with the same key: two tags computed
+// under the same nonce reveal the hash subkey H, which allows forging tags
+// for any message under that nonce.
+//
+//	mac, err := gmac.New(b, nonce)
+//	mac.Write(message)
+//	tag := mac.Sum(nil)
+package gmac
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+const BlockSize int = 16
+const Size int = 16
+
+// NonceSize is the recommended nonce size of 96 bits, other sizes are
+// hashed with GHASH.
+const NonceSize int = 12
+
+// Element of GF(2^128), with the bit order of GCM: the bit 0 is the most
+// significant bit of hi.
+type element struct {
+	hi, lo uint64
+}
+
+type gmac struct {
+	// Hash subkey H = CIPH_K(0^128)
+	h element
+	// Encrypted pre-counter block CIPH_K(J_0)
+	ej0 [BlockSize]byte
+	// GHASH accumulator
+	y   element
+	buf [BlockSize]byte
+	nx  int
+	// Length of the data in bytes
+	len uint64
+}
+
+// New returns a new GMAC using the cipher b under nonce.
+// Returns an error if the block size of b is not 128 bits or if the nonce
+// is empty.
+// The nonce must be unique for a given key, including across calls to
+// Reset, which keeps the same nonce.
+func New(b cipher.Block, nonce []byte) (hash.Hash, error) {
+	if b.BlockSize() != BlockSize {
+		return nil, errors.New("gmac: block size must be 128 bits")
+	}
+	if len(nonce) == 0 {
+		return nil, errors.New("gmac: nonce must not be empty")
+	}
+
+	var hb [BlockSize]byte
+	b.Encrypt(hb[:], hb[:])
+	g := &gmac{h: load(hb[:])}
+
+	// J_0 = IV || 0^31 || 1 for 96 bits IVs, else
+	// J_0 = GHASH(IV || 0^s || 0^64 || [len(IV)]_64)
+	// c.f. SP 800-38D 7.1 step 2
+	var j0 [BlockSize]byte
+	if len(nonce) == NonceSize {
+		copy(j0[:], nonce)
+		j0[BlockSize-1] = 1
+	} else {
+		g.Write(nonce)
+		g.lengths(j0[:0], 0, uint64(len(nonce)))
+		g.Reset()
+	}
+	b.Encrypt(g.ej0[:], j0[:])
+
+	return g, nil
+}
+
+func load(b []byte) element {
+	return element{binary.BigEndian.Uint64(b), binary.BigEndian.Uint64(b[8:])}
+}
+
+// mul computes x * y in GF(2^128), in constant time
+// c.f. SP 800-38D 6.3
+func mul(x, y element) element {
+	var z element
+	v := y
+
+	for i := 0; i < 128; i++ {
+		// Bit i of x, from the most significant
+		var bit uint64
+		if i < 64 {
+			bit = x.hi >> (63 - i) & 1
+		} else {
+			bit = x.lo >> (127 - i) & 1
+		}
+		z.hi ^= v.hi & -bit
+		z.lo ^= v.lo & -bit
+
+		// V = V >> 1, reduced by R = 11100001 || 0^120
+		lsb := v.lo & 1
+		v.lo = v.lo>>1 | v.hi<<63
+		v.hi = v.hi>>1 ^ 0xe1<<56&-lsb
+	}
+
+	return z
+}
+
+// block absorbs the full blocks of p in the GHASH accumulator
+// c.f. SP 800-38D 6.4
+func (g *gmac) block(p []byte) {
+	for len(p) >= BlockSize {
+		x := load(p)
+		g.y = mul(element{g.y.hi ^ x.hi, g.y.lo ^ x.lo}, g.h)
+		p = p[BlockSize:]
+	}
+}
+
+func (g *gmac) Write(p []byte) (n int, err error) {
+	n = len(p)
+	g.len += uint64(n)
+
+	// Fill the pending block first
+	if g.nx > 0 {
+		c := copy(g.buf[g.nx:], p)
+		g.nx += c
+		p = p[c:]
+
+		if g.nx < BlockSize {
+			return n, nil
+		}
+
+		g.block(g.buf[:])
+		g.nx = 0
+	}
+
+	full := len(p) &^ (BlockSize - 1)
+	g.block(p[:full])
+
+	// Keep the rest for later
+	g.nx = copy(g.buf[:], p[full:])
+
+	return n, nil
+}
+
+// lengths pads the pending data with zeros, absorbs the block of the bit
+// lengths of A and C and appends the GHASH result to b.
+// The receiver is modified.
+func (g *gmac) lengths(b []byte, lenA, lenC uint64) []byte {
+	if g.nx > 0 {
+		for i := g.nx; i < BlockSize; i++ {
+			g.buf[i] = 0
+		}
+		g.block(g.buf[:])
+		g.nx = 0
+	}
+
+	var l [BlockSize]byte
+	binary.BigEndian.PutUint64(l[:], lenA<<3)
+	binary.BigEndian.PutUint64(l[8:], lenC<<3)
+	g.block(l[:])
+
+	b = binary.BigEndian.AppendUint64(b, g.y.hi)
+	return binary.BigEndian.AppendUint64(b, g.y.lo)
+}
+
+func (g0 *gmac) Sum(b []byte) []byte {
+	// Work on a copy so that the caller can keep writing
+	g := *g0
+
+	// T = GCTR_K(J_0, S) with S = GHASH_H(A || 0^v || [len(A)]_64 || 0^64)
+	// c.f. SP 800-38D 7.1 steps 5 and 6
+	var s [BlockSize]byte
+	g.lengths(s[:0], g.len, 0)
+	subtle.XORBytes(s[:], s[:], g.ej0[:])
+
+	return append(b, s[:]...)
+}
+
+func (g *gmac) Reset() {
+	g.y = element{}
+	g.nx = 0
+	g.len = 0
+}
+
+func (g *gmac) Size() int {
+	return Size
+}
+
+func (g *gmac) BlockSize() int {
+	return BlockSize
+}
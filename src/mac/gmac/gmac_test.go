@@ -0,0 +1,98 @@
+package gmac
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	crand "crypto/rand"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+
+	"github.com/loicbacciga/crypto-go/src/cipher/utils"
+)
+
+func TestVectors(t *testing.T) {
+	vectors := []struct {
+		keyHex, nonceHex, dataHex, expHex string
+	}{
+		// GCM test case 1 (McGrew, Viega), empty plaintext and data
+		{
+			"00000000000000000000000000000000",
+			"000000000000000000000000",
+			"",
+			"58e2fccefa7e3061367f1d57a4e7455a",
+		},
+		// IEEE 802.1AE 2.1.1, 54 bytes packet authentication with GCM-AES-128
+		{
+			"ad7a2bd03eac835a6f620fdcb506b345",
+			"12153524c0895e81b2c28465",
+			"d609b1f056637a0d46df998d88e5222ab2c2846512153524c0895e8108000f10" +
+				"1112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f30" +
+				"313233340001",
+			"f09478a9b09007d06f46e9b6a1da25dd",
+		},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		nonce, _ := hex.DecodeString(v.nonceHex)
+		data, _ := hex.DecodeString(v.dataHex)
+		b, _ := aes.NewCipher(key)
+
+		mac, err := New(b, nonce)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		mac.Write(data)
+
+		if resHex := hex.EncodeToString(mac.Sum(nil)); resHex != v.expHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.expHex)
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	tries := 200
+
+	for tr := 0; tr < tries; tr++ {
+		key := make([]byte, 16)
+		crand.Read(key)
+		// Other nonce sizes go through GHASH
+		nonce := make([]byte, []int{NonceSize, 1, 8, 16, 60}[tr%5])
+		crand.Read(nonce)
+		data := make([]byte, rand.Intn(300))
+		crand.Read(data)
+
+		b, _ := aes.NewCipher(key)
+		gcm, _ := cipher.NewGCMWithNonceSize(b, len(nonce))
+		expHex := hex.EncodeToString(gcm.Seal(nil, nonce, nil, data))
+
+		mac, _ := New(b, nonce)
+		// Write in two parts, Sum in the middle must not change the result
+		split := rand.Intn(len(data) + 1)
+		mac.Write(data[:split])
+		mac.Sum(nil)
+		mac.Write(data[split:])
+
+		if resHex := hex.EncodeToString(mac.Sum(nil)); resHex != expHex {
+			t.Errorf("Not equal %s!=%s", resHex, expHex)
+		}
+
+		mac.Reset()
+		mac.Write(data)
+		if resHex := hex.EncodeToString(mac.Sum(nil)); resHex != expHex {
+			t.Errorf("reset: Not equal %s!=%s", resHex, expHex)
+		}
+	}
+}
+
+func TestInvalidParameters(t *testing.T) {
+	if _, err := New(utils.NewDummyCipher(8), make([]byte, NonceSize)); err == nil {
+		t.Error("64 bits block cipher accepted")
+	}
+
+	b, _ := aes.NewCipher(make([]byte, 16))
+	if _, err := New(b, nil); err == nil {
+		t.Error("empty nonce accepted")
+	}
+}
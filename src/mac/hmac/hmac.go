@@ -0,0 +1,91 @@
+// Package hmac implements the Keyed-Hash Message Authentication Code (HMAC)
+// as defined in RFC 2104 (https://datatracker.ietf.org/doc/html/rfc2104)
+// and FIPS 198-1.
+//
+// Any hash of the library can be used, e.g.
+//
+//	mac := hmac.New(sha256.New256, key)
+//	mac.Write(message)
+//	tag := mac.Sum(nil)
+package hmac
+
+import (
+	"crypto/subtle"
+	"hash"
+)
+
+type hmac struct {
+	opad, ipad   []byte
+	outer, inner hash.Hash
+}
+
+// New returns a new HMAC hash using the given hash constructor and key.
+// Keys longer than the block size of the hash are hashed first.
+func New(h func() hash.Hash, key []byte) hash.Hash {
+	hm := &hmac{
+		outer: h(),
+		inner: h(),
+	}
+
+	blockSize := hm.inner.BlockSize()
+	hm.ipad = make([]byte, blockSize)
+	hm.opad = make([]byte, blockSize)
+
+	// Step 1-3: make the key exactly one block long
+	if len(key) > blockSize {
+		hm.outer.Write(key)
+		key = hm.outer.Sum(nil)
+	}
+	copy(hm.ipad, key)
+	copy(hm.opad, key)
+
+	// Step 4 and 7: xor with ipad and opad
+	for i := range hm.ipad {
+		hm.ipad[i] ^= 0x36
+	}
+	for i := range hm.opad {
+		hm.opad[i] ^= 0x5c
+	}
+
+	hm.Reset()
+
+	return hm
+}
+
+// Equal compares two MACs for equality without leaking timing information.
+func Equal(mac1, mac2 []byte) bool {
+	// subtle.ConstantTimeCompare already returns 0 on different lengths,
+	// the length of a MAC is not secret.
+	return subtle.ConstantTimeCompare(mac1, mac2) == 1
+}
+
+func (h *hmac) Write(p []byte) (n int, err error) {
+	return h.inner.Write(p)
+}
+
+func (h *hmac) Sum(b []byte) []byte {
+	origLen := len(b)
+
+	// H(K xor ipad || text)
+	b = h.inner.Sum(b)
+
+	// H(K xor opad || H(K xor ipad || text))
+	h.outer.Reset()
+	h.outer.Write(h.opad)
+	h.outer.Write(b[origLen:])
+
+	return h.outer.Sum(b[:origLen])
+}
+
+func (h *hmac) Reset() {
+	h.inner.Reset()
+	h.inner.Write(h.ipad)
+}
+
+func (h *hmac) Size() int {
+	return h.outer.Size()
+}
+
+func (h *hmac) BlockSize() int {
+	return h.inner.BlockSize()
+}
@@ -0,0 +1,222 @@
+// Package iso9797 implements the CBC-MAC algorithms of ISO/IEC 9797-1:2011
+// with the padding methods 1 to 3, and the ANSI X9.9 and X9.19 (retail MAC)
+// MACs with DES.
+//
+// MACs are computed in one shot, as padding method 3 prepends the length of
+// the data.
+package iso9797
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+
+	"github.com/loicbacciga/crypto-go/src/cipher/des"
+	"github.com/loicbacciga/crypto-go/src/cipher/modes/cbc"
+	"github.com/loicbacciga/crypto-go/src/mac/cmac"
+)
+
+// Padding is a padding method of ISO/IEC 9797-1 6.3.
+type Padding int
+
+const (
+	// Padding1 appends zeros, at least one block is processed.
+	Padding1 Padding = 1 + iota
+	// Padding2 appends a one bit and zeros.
+	Padding2
+	// Padding3 appends zeros and prepends a block with the length of the
+	// data in bits.
+	Padding3
+)
+
+// Algorithm is a MAC algorithm of ISO/IEC 9797-1 7.
+type Algorithm int
+
+const (
+	// Algorithm1 is the plain CBC-MAC with key K.
+	Algorithm1 Algorithm = 1 + iota
+	// Algorithm2 encrypts the CBC-MAC with K'.
+	Algorithm2
+	// Algorithm3 decrypts the CBC-MAC with K' and encrypts it with K,
+	// the retail MAC.
+	Algorithm3
+	// Algorithm4 encrypts the first block with K'', derived from K', and
+	// the CBC-MAC with K'. The padded data must be at least two blocks.
+	Algorithm4
+	// Algorithm5 is CMAC, it uses its own padding.
+	Algorithm5
+	// Algorithm6 encrypts the last block with K' instead of K (LMAC).
+	Algorithm6
+)
+
+// Params are the options of a MAC computation.
+type Params struct {
+	Algorithm Algorithm
+	// Padding method, must be 0 for Algorithm5
+	Padding Padding
+	// Size is the length of the MAC in bytes, at most the block size.
+	// 0 means the block size.
+	Size int
+}
+
+// Pad applies the padding method p to data, for the given block size.
+// c.f. ISO/IEC 9797-1 6.3
+func Pad(data []byte, blockSize int, p Padding) ([]byte, error) {
+	res := bytes.Clone(data)
+
+	switch p {
+	case Padding1:
+		if len(res) == 0 {
+			return make([]byte, blockSize), nil
+		}
+	case Padding2:
+		res = append(res, 0x80)
+	case Padding3:
+		if blockSize < 8 {
+			return nil, errors.New("iso9797: padding method 3 needs at least 64 bits blocks")
+		}
+		l := make([]byte, blockSize)
+		binary.BigEndian.PutUint64(l[blockSize-8:], uint64(len(data))*8)
+		res = append(l, res...)
+	default:
+		return nil, errors.New("iso9797: unknown padding method")
+	}
+
+	if r := len(res) % blockSize; r != 0 {
+		res = append(res, make([]byte, blockSize-r)...)
+	}
+
+	return res, nil
+}
+
+// DeriveKey derives the key K' from K, by complementing alternate nibbles
+// starting with the first one.
+// c.f. ISO/IEC 9797-1 7.2
+func DeriveKey(key []byte) []byte {
+	res := make([]byte, len(key))
+	for i := range key {
+		res[i] = key[i] ^ 0xf0
+	}
+
+	return res
+}
+
+// Sum computes the MAC of data with the block cipher created by newCipher.
+// key2 is K', for algorithms 2, 3, 4 and 6. If it is nil, it is derived
+// from key with DeriveKey.
+func Sum(newCipher func(key []byte) (cipher.Block, error), key, key2, data []byte, p Params) ([]byte, error) {
+	b, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	blockSize := b.BlockSize()
+
+	size := p.Size
+	if size == 0 {
+		size = blockSize
+	}
+	if size < 0 || size > blockSize {
+		return nil, errors.New("iso9797: invalid MAC size")
+	}
+
+	// CMAC pads by itself
+	if p.Algorithm == Algorithm5 {
+		if p.Padding != 0 {
+			return nil, errors.New("iso9797: algorithm 5 uses its own padding")
+		}
+		mac, err := cmac.New(b)
+		if err != nil {
+			return nil, err
+		}
+		mac.Write(data)
+		return mac.Sum(nil)[:size], nil
+	}
+
+	if p.Algorithm < Algorithm1 || p.Algorithm > Algorithm6 {
+		return nil, errors.New("iso9797: unknown MAC algorithm")
+	}
+
+	padded, err := Pad(data, blockSize, p.Padding)
+	if err != nil {
+		return nil, err
+	}
+
+	// The second key K'
+	var b2 cipher.Block
+	if p.Algorithm != Algorithm1 {
+		if key2 == nil {
+			key2 = DeriveKey(key)
+		}
+		if b2, err = newCipher(key2); err != nil {
+			return nil, err
+		}
+	}
+
+	// Initial transformation 2: H_1 = e_K''(e_K(D_1))
+	h := make([]byte, blockSize)
+	if p.Algorithm == Algorithm4 {
+		if len(padded) < 2*blockSize {
+			return nil, errors.New("iso9797: algorithm 4 needs at least two blocks")
+		}
+		b3, err := newCipher(DeriveKey(key2))
+		if err != nil {
+			return nil, err
+		}
+		b.Encrypt(h, padded[:blockSize])
+		b3.Encrypt(h, h)
+		padded = padded[blockSize:]
+	}
+
+	// Iterations on the remaining blocks, with H_i as IV
+	last := len(padded) - blockSize
+	if p.Algorithm != Algorithm6 {
+		last = len(padded)
+	}
+	if last > 0 {
+		enc, err := cbc.NewEncrypter(b, h)
+		if err != nil {
+			return nil, err
+		}
+		ct := make([]byte, last)
+		enc.CryptBlocks(ct, padded[:last])
+		copy(h, ct[last-blockSize:])
+	}
+
+	// Final iteration and output transformation
+	switch p.Algorithm {
+	case Algorithm2, Algorithm4:
+		// G = e_K'(H_q)
+		b2.Encrypt(h, h)
+	case Algorithm3:
+		// G = e_K(d_K'(H_q))
+		b2.Decrypt(h, h)
+		b.Encrypt(h, h)
+	case Algorithm6:
+		// H_q = e_K'(D_q xor H_q-1)
+		for i := range h {
+			h[i] ^= padded[last+i]
+		}
+		b2.Encrypt(h, h)
+	}
+
+	return h[:size], nil
+}
+
+// X99 computes the ANSI X9.9 MAC of data with a DES key, which is
+// MAC algorithm 1 with padding method 1.
+// size is the length of the MAC in bytes, usually 4.
+func X99(key, data []byte, size int) ([]byte, error) {
+	return Sum(des.New, key, nil, data, Params{Algorithm: Algorithm1, Padding: Padding1, Size: size})
+}
+
+// X919 computes the ANSI X9.19 retail MAC of data with a double length DES
+// key K || K', which is MAC algorithm 3 with padding method 1.
+// size is the length of the MAC in bytes, usually 4 or 8.
+func X919(key, data []byte, size int) ([]byte, error) {
+	if len(key) != 2*des.BlockSize {
+		return nil, errors.New("iso9797: X9.19 key must be 16 bytes")
+	}
+
+	return Sum(des.New, key[:8], key[8:], data, Params{Algorithm: Algorithm3, Padding: Padding1, Size: size})
+}
@@ -0,0 +1,131 @@
+package iso9797
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/loicbacciga/crypto-go/src/cipher/des"
+)
+
+// Data strings and keys of ISO/IEC 9797-1 annex B
+var (
+	data1 = []byte("Now is the time for all ")
+	data2 = []byte("Now is the time for it")
+	key1  = []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	key2  = []byte{0xfe, 0xdc, 0xba, 0x98, 0x76, 0x54, 0x32, 0x10}
+)
+
+// Vectors of annex B, completed with values checked with OpenSSL
+func TestAlgorithms(t *testing.T) {
+	vectors := []struct {
+		p          Params
+		key2       []byte
+		exp1, exp2 string
+	}{
+		{Params{Algorithm: Algorithm1, Padding: Padding1}, nil, "70a30640cc76dd8b", "e45b3ad2b7cc0856"},
+		{Params{Algorithm: Algorithm1, Padding: Padding2}, nil, "10e1f0f108341b6d", "a924c72136149211"},
+		{Params{Algorithm: Algorithm1, Padding: Padding3}, nil, "2c58fb8ff12aaeac", "b1ecd6fc8b37c392"},
+		{Params{Algorithm: Algorithm2, Padding: Padding1}, key2, "541567cbbae5d014", "9ebc16438bad047c"},
+		{Params{Algorithm: Algorithm3, Padding: Padding1}, key2, "a1c72e74ea3fa9b6", "2e2b1428cc78254f"},
+		{Params{Algorithm: Algorithm4, Padding: Padding1}, key2, "ad3502b7ac4a48a0", "05f1084c1de3a33d"},
+		{Params{Algorithm: Algorithm5}, nil, "a96db53d7d11648d", "6754059c9614ae95"},
+		{Params{Algorithm: Algorithm6, Padding: Padding1}, key2, "13c1bc4e9d5de7b5", "a6f40f4f54bc0d63"},
+		// Truncated
+		{Params{Algorithm: Algorithm3, Padding: Padding1, Size: 4}, key2, "a1c72e74", "2e2b1428"},
+	}
+
+	for _, v := range vectors {
+		res1, err := Sum(des.New, key1, v.key2, data1, v.p)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		res2, err := Sum(des.New, key1, v.key2, data2, v.p)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if res1Hex := hex.EncodeToString(res1); res1Hex != v.exp1 {
+			t.Errorf("%v: Not equal %s!=%s", v.p, res1Hex, v.exp1)
+		}
+		if res2Hex := hex.EncodeToString(res2); res2Hex != v.exp2 {
+			t.Errorf("%v: Not equal %s!=%s", v.p, res2Hex, v.exp2)
+		}
+	}
+}
+
+func TestDerivedKey(t *testing.T) {
+	res, err := Sum(des.New, key1, nil, data1, Params{Algorithm: Algorithm2, Padding: Padding1})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resHex := hex.EncodeToString(res); resHex != "10f9bc67a03cd5d8" {
+		t.Errorf("Not equal %s!=10f9bc67a03cd5d8", resHex)
+	}
+}
+
+func TestX9(t *testing.T) {
+	res, err := X99(key1, data1, 4)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resHex := hex.EncodeToString(res); resHex != "70a30640" {
+		t.Errorf("Not equal %s!=70a30640", resHex)
+	}
+
+	res, err = X919(append(key1, key2...), data2, 8)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if resHex := hex.EncodeToString(res); resHex != "2e2b1428cc78254f" {
+		t.Errorf("Not equal %s!=2e2b1428cc78254f", resHex)
+	}
+
+	if _, err := X919(key1, data2, 8); err == nil {
+		t.Error("short X9.19 key accepted")
+	}
+}
+
+func TestPad(t *testing.T) {
+	vectors := []struct {
+		data string
+		p    Padding
+		exp  string
+	}{
+		{"", Padding1, "0000000000000000"},
+		{"01", Padding1, "0100000000000000"},
+		{"0102030405060708", Padding1, "0102030405060708"},
+		{"", Padding2, "8000000000000000"},
+		{"0102030405060708", Padding2, "01020304050607088000000000000000"},
+		{"", Padding3, "0000000000000000"},
+		{"010203", Padding3, "00000000000000180102030000000000"},
+	}
+
+	for _, v := range vectors {
+		data, _ := hex.DecodeString(v.data)
+		res, err := Pad(data, 8, v.p)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if resHex := hex.EncodeToString(res); resHex != v.exp {
+			t.Errorf("Not equal %s!=%s", resHex, v.exp)
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	invalid := []Params{
+		{Algorithm: 0, Padding: Padding1},
+		{Algorithm: 7, Padding: Padding1},
+		{Algorithm: Algorithm1, Padding: 4},
+		{Algorithm: Algorithm5, Padding: Padding1},
+		{Algorithm: Algorithm1, Padding: Padding1, Size: 9},
+		// Less than two blocks
+		{Algorithm: Algorithm4, Padding: Padding2},
+	}
+
+	for _, p := range invalid {
+		if _, err := Sum(des.New, key1, key2, []byte("short"), p); err == nil {
+			t.Errorf("%v accepted", p)
+		}
+	}
+}
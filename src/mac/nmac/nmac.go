@@ -0,0 +1,72 @@
+// Package nmac implements the nested MAC NMAC of Bellare, Canetti and
+// Krawczyk ("Keying Hash Functions for Message Authentication", 1996):
+// NMAC(k1, k2, m) = F_k1(F_k2(m)), where F_k is the hash function with its
+// initial chaining value replaced by k.
+//
+// Any hash of the library giving access to its chaining value can be used
+// (MD5, SHA-1, SHA-2), e.g. nmac.New(sha256.New256, k1, k2).
+package nmac
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+
+	lh "github.com/loicbacciga/crypto-go/src/hash"
+)
+
+type nmac struct {
+	outer, inner lh.Chaining
+	k1, k2       []byte
+}
+
+// New returns a new NMAC using the hash h, with the outer key k1 and inner
+// key k2, which are chaining values of h.
+// Returns an error if h does not expose its chaining value or the keys do
+// not have the size of a chaining value.
+func New(h func() hash.Hash, k1, k2 []byte) (hash.Hash, error) {
+	outer, ok := h().(lh.Chaining)
+	if !ok {
+		return nil, errors.New("nmac: hash does not give access to its chaining value")
+	}
+	inner := h().(lh.Chaining)
+
+	if len(k1) != outer.ChainingSize() || len(k2) != outer.ChainingSize() {
+		return nil, errors.New("nmac: keys must be the size of a chaining value")
+	}
+
+	n := &nmac{
+		outer: outer,
+		inner: inner,
+		k1:    bytes.Clone(k1),
+		k2:    bytes.Clone(k2),
+	}
+	n.Reset()
+
+	return n, nil
+}
+
+func (n *nmac) Write(p []byte) (int, error) {
+	return n.inner.Write(p)
+}
+
+func (n *nmac) Sum(b []byte) []byte {
+	n.outer.SetChainingValue(n.k1)
+	n.outer.Write(n.inner.Sum(nil))
+
+	return n.outer.Sum(b)
+}
+
+func (n *nmac) Reset() {
+	// The key sizes were checked by New
+	n.inner.SetChainingValue(n.k2)
+	n.outer.SetChainingValue(n.k1)
+}
+
+func (n *nmac) Size() int {
+	return n.outer.Size()
+}
+
+func (n *nmac) BlockSize() int {
+	return n.inner.BlockSize()
+}
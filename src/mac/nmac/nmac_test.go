@@ -0,0 +1,111 @@
+package nmac
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding"
+	"encoding/binary"
+	"hash"
+	"testing"
+
+	lmd2 "github.com/loicbacciga/crypto-go/src/hash/md2"
+	lmd5 "github.com/loicbacciga/crypto-go/src/hash/md5"
+	lsha1 "github.com/loicbacciga/crypto-go/src/hash/sha1"
+	lsha256 "github.com/loicbacciga/crypto-go/src/hash/sha256"
+	lsha512 "github.com/loicbacciga/crypto-go/src/hash/sha512"
+)
+
+type testHash struct {
+	name  string
+	new   func() hash.Hash
+	goNew func() hash.Hash
+	// Words of the chaining value, in bytes
+	wordSize int
+	// The chaining value is little-endian
+	littleEndian bool
+}
+
+var testHashes = []testHash{
+	{"MD5", lmd5.New, md5.New, 4, true},
+	{"SHA-1", lsha1.New, sha1.New, 4, false},
+	{"SHA-224", lsha256.New224, sha256.New224, 4, false},
+	{"SHA-256", lsha256.New256, sha256.New, 4, false},
+	{"SHA-384", lsha512.New384, sha512.New384, 8, false},
+	{"SHA-512", lsha512.New512, sha512.New, 8, false},
+	{"SHA-512/256", lsha512.New512_256, sha512.New512_256, 8, false},
+}
+
+// goKeyed computes F_k(m) with the Go hash, by unmarshaling a state whose
+// chaining value is k
+func goKeyed(t *testing.T, th testHash, k, m []byte) []byte {
+	d := th.goNew()
+	state, _ := d.(encoding.BinaryMarshaler).MarshalBinary()
+
+	// Layout: magic (4 bytes) || chaining value (big-endian words) || ...
+	for i := 0; i < len(k); i += th.wordSize {
+		w := k[i : i+th.wordSize]
+		if th.littleEndian {
+			binary.BigEndian.PutUint32(state[4+i:], binary.LittleEndian.Uint32(w))
+		} else {
+			copy(state[4+i:], w)
+		}
+	}
+
+	if err := d.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		t.Fatal(err.Error())
+	}
+	d.Write(m)
+
+	return d.Sum(nil)
+}
+
+func TestNMAC(t *testing.T) {
+	msgs := [][]byte{
+		nil,
+		[]byte("The quick brown fox jumps over the lazy dog"),
+		bytes.Repeat([]byte{0xa5}, 300),
+	}
+
+	for _, th := range testHashes {
+		size := th.new().(interface{ ChainingSize() int }).ChainingSize()
+		k1 := bytes.Repeat([]byte{0x5c}, size)
+		k2 := bytes.Repeat([]byte{0x36}, size)
+		for i := range k1 {
+			k1[i] ^= byte(i)
+			k2[i] ^= byte(3 * i)
+		}
+
+		for _, m := range msgs {
+			mac, err := New(th.new, k1, k2)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			mac.Write(m)
+			res := mac.Sum(nil)
+
+			exp := goKeyed(t, th, k1, goKeyed(t, th, k2, m))
+			if !bytes.Equal(res, exp) {
+				t.Errorf("%s: Not equal %x!=%x", th.name, res, exp)
+			}
+
+			// Reset keeps the keys
+			mac.Reset()
+			mac.Write(m)
+			if res := mac.Sum(nil); !bytes.Equal(res, exp) {
+				t.Errorf("%s reset: Not equal %x!=%x", th.name, res, exp)
+			}
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	if _, err := New(lmd2.New, make([]byte, 16), make([]byte, 16)); err == nil {
+		t.Error("MD2 accepted")
+	}
+	if _, err := New(lsha256.New256, make([]byte, 16), make([]byte, 32)); err == nil {
+		t.Error("short key accepted")
+	}
+}
@@ -0,0 +1,227 @@
+// Package pmac implements PMAC1, the parallelizable MAC of Black and
+// Rogaway ("A Block-Cipher Mode of Operation for Parallelizable Message
+// Authentication", 2002, and PMAC1 in Rogaway's "Efficient Instantiations
+// of Tweakable Blockciphers", 2004), over any 128 bits cipher.Block.
+//
+// Large writes are processed in parallel.
+package pmac
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+const BlockSize = 16
+
+// Number of blocks of a write from which it is processed in parallel
+const parallelBlocks = 1024
+
+type pmac struct {
+	b cipher.Block
+	// l[i] = L.x^i, with L = E_K(0^n)
+	l [64][BlockSize]byte
+	// lInv = L.x^-1
+	lInv [BlockSize]byte
+	// Sum of the encrypted blocks
+	sigma [BlockSize]byte
+	// Offset of the last processed block
+	offset [BlockSize]byte
+	// Number of processed blocks
+	count uint64
+	// Unprocessed data, which may be the last block
+	buf []byte
+}
+
+// New returns a new PMAC1 using the cipher b.
+// Returns an error if the block size of b is not 128 bits.
+func New(b cipher.Block) (hash.Hash, error) {
+	if b.BlockSize() != BlockSize {
+		return nil, errors.New("pmac: block size must be 128 bits")
+	}
+
+	p := &pmac{
+		b:   b,
+		buf: make([]byte, 0, BlockSize),
+	}
+
+	b.Encrypt(p.l[0][:], p.l[0][:])
+	for i := 1; i < len(p.l); i++ {
+		p.l[i] = double(p.l[i-1])
+	}
+	p.lInv = half(p.l[0])
+
+	return p, nil
+}
+
+// double computes the multiplication by x in GF(2^128), with the
+// polynomial x^128 + x^7 + x^2 + x + 1.
+func double(src [BlockSize]byte) [BlockSize]byte {
+	var dst [BlockSize]byte
+
+	var carry byte
+	for i := BlockSize - 1; i >= 0; i-- {
+		dst[i] = src[i]<<1 | carry
+		carry = src[i] >> 7
+	}
+	dst[BlockSize-1] ^= 0x87 & -carry
+
+	return dst
+}
+
+// half computes the division by x in GF(2^128).
+func half(src [BlockSize]byte) [BlockSize]byte {
+	var dst [BlockSize]byte
+
+	var carry byte
+	for i := 0; i < BlockSize; i++ {
+		dst[i] = src[i]>>1 | carry<<7
+		carry = src[i] & 1
+	}
+	// x^-1 = x^127 + x^6 + x + 1
+	dst[0] ^= 0x80 & -carry
+	dst[BlockSize-1] ^= 0x43 & -carry
+
+	return dst
+}
+
+// offsetAt returns the offset of block i (from 1), the sum of the L.x^k for
+// the bits k set in the Gray code of i.
+func (p *pmac) offsetAt(i uint64) [BlockSize]byte {
+	var res [BlockSize]byte
+
+	gray := i ^ (i >> 1)
+	for k := 0; gray != 0; k++ {
+		if gray&1 == 1 {
+			subtle.XORBytes(res[:], res[:], p.l[k][:])
+		}
+		gray >>= 1
+	}
+
+	return res
+}
+
+// processBlocks encrypts the blocks of src, the first one being block
+// count + 1, and adds them to sigma.
+// offset is the offset of block count, and is updated.
+func (p *pmac) processBlocks(sigma, offset *[BlockSize]byte, count uint64, src []byte) {
+	var tmp [BlockSize]byte
+
+	for i := 0; i < len(src); i += BlockSize {
+		count++
+		subtle.XORBytes(offset[:], offset[:], p.l[bits.TrailingZeros64(count)][:])
+
+		subtle.XORBytes(tmp[:], src[i:i+BlockSize], offset[:])
+		p.b.Encrypt(tmp[:], tmp[:])
+		subtle.XORBytes(sigma[:], sigma[:], tmp[:])
+	}
+}
+
+// process processes whole blocks, in parallel if there are many.
+func (p *pmac) process(src []byte) {
+	n := len(src) / BlockSize
+	workers := runtime.GOMAXPROCS(0)
+
+	if n < parallelBlocks || workers == 1 {
+		p.processBlocks(&p.sigma, &p.offset, p.count, src)
+		p.count += uint64(n)
+		return
+	}
+
+	// Split into chunks of whole blocks, the offsets only depend on the
+	// index of the blocks
+	chunk := (n + workers - 1) / workers
+	sigmas := make([][BlockSize]byte, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * chunk
+		end := start + chunk
+		if end > n {
+			end = n
+		}
+		if start >= end {
+			break
+		}
+
+		wg.Add(1)
+		go func(w, start, end int) {
+			first := p.count + uint64(start)
+			offset := p.offsetAt(first)
+			p.processBlocks(&sigmas[w], &offset, first, src[start*BlockSize:end*BlockSize])
+			wg.Done()
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	for i := range sigmas {
+		subtle.XORBytes(p.sigma[:], p.sigma[:], sigmas[i][:])
+	}
+	p.count += uint64(n)
+	p.offset = p.offsetAt(p.count)
+}
+
+func (p *pmac) Write(src []byte) (n int, err error) {
+	n = len(src)
+
+	// Complete the buffer, it is processed only if more data follows
+	if len(p.buf) > 0 {
+		l := BlockSize - len(p.buf)
+		if l > len(src) {
+			l = len(src)
+		}
+		p.buf = append(p.buf, src[:l]...)
+		src = src[l:]
+
+		if len(src) == 0 {
+			return n, nil
+		}
+		p.process(p.buf)
+		p.buf = p.buf[:0]
+	}
+
+	// Keep the last block, even if it is complete
+	if len(src) > 0 {
+		whole := (len(src) - 1) / BlockSize * BlockSize
+		p.process(src[:whole])
+		p.buf = append(p.buf, src[whole:]...)
+	}
+
+	return n, nil
+}
+
+func (p *pmac) Sum(b []byte) []byte {
+	sigma := p.sigma
+
+	if len(p.buf) == BlockSize {
+		subtle.XORBytes(sigma[:], sigma[:], p.buf)
+		subtle.XORBytes(sigma[:], sigma[:], p.lInv[:])
+	} else {
+		// Padding 10*
+		subtle.XORBytes(sigma[:], sigma[:], p.buf)
+		sigma[len(p.buf)] ^= 0x80
+	}
+
+	p.b.Encrypt(sigma[:], sigma[:])
+
+	return append(b, sigma[:]...)
+}
+
+func (p *pmac) Reset() {
+	p.sigma = [BlockSize]byte{}
+	p.offset = [BlockSize]byte{}
+	p.count = 0
+	p.buf = p.buf[:0]
+}
+
+func (p *pmac) Size() int {
+	return BlockSize
+}
+
+func (p *pmac) BlockSize() int {
+	return BlockSize
+}
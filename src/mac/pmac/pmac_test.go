@@ -0,0 +1,93 @@
+package pmac
+
+import (
+	"crypto/aes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/loicbacciga/crypto-go/src/cipher/utils"
+)
+
+func TestVectors(t *testing.T) {
+	key, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	b, _ := aes.NewCipher(key)
+
+	msg := make([]byte, 34)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+
+	vectors := []struct {
+		msg []byte
+		exp string
+	}{
+		{nil, "4399572cd6ea5341b8d35876a7098af7"},
+		{msg[:3], "256ba5193c1b991b4df0c51f388a9e27"},
+		{msg[:16], "ebbd822fa458daf6dfdad7c27da76338"},
+		{msg[:20], "0412ca150bbf79058d8c75a58c993f55"},
+		{msg[:32], "e97ac04e9e5e3399ce5355cd7407bc75"},
+		{msg[:34], "5cba7d5eb24f7c86ccc54604e53d5512"},
+		{make([]byte, 1000), "c2c9fa1d9985f6f0d2aff915a0e8d910"},
+	}
+
+	for _, v := range vectors {
+		mac, _ := New(b)
+		mac.Write(v.msg)
+		if resHex := hex.EncodeToString(mac.Sum(nil)); resHex != v.exp {
+			t.Errorf("Not equal %s!=%s", resHex, v.exp)
+		}
+	}
+}
+
+func TestParallel(t *testing.T) {
+	key, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	b, _ := aes.NewCipher(key)
+
+	msg := make([]byte, 5*parallelBlocks*BlockSize+7)
+	for i := range msg {
+		msg[i] = byte(i * 7)
+	}
+
+	// Parallel
+	mac, _ := New(b)
+	mac.Write(msg)
+	exp := hex.EncodeToString(mac.Sum(nil))
+
+	// Sequential, with writes smaller than the threshold
+	seq, _ := New(b)
+	for i := 0; i < len(msg); i += 1000 {
+		end := i + 1000
+		if end > len(msg) {
+			end = len(msg)
+		}
+		seq.Write(msg[i:end])
+	}
+	if resHex := hex.EncodeToString(seq.Sum(nil)); resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+
+	// Parallel write after a partial block
+	mac.Reset()
+	mac.Write(msg[:5])
+	mac.Write(msg[5:])
+	if resHex := hex.EncodeToString(mac.Sum(nil)); resHex != exp {
+		t.Errorf("Not equal %s!=%s", resHex, exp)
+	}
+}
+
+func TestHalf(t *testing.T) {
+	for _, s := range []string{"00000000000000000000000000000001", "80000000000000000000000000000000", "0123456789abcdeffedcba9876543210"} {
+		var x [BlockSize]byte
+		hex.Decode(x[:], []byte(s))
+
+		if res := double(half(x)); res != x {
+			t.Errorf("Not equal %x!=%x", res, x)
+		}
+	}
+}
+
+func TestInvalidBlockSize(t *testing.T) {
+	if _, err := New(utils.NewDummyCipher(8)); err == nil {
+		t.Error("64 bits block accepted")
+	}
+}
@@ -0,0 +1,209 @@
+// Package poly1305 implements the Poly1305 one-time authenticator as
+// defined in RFC 8439 (https://datatracker.ietf.org/doc/html/rfc8439), and
+// Poly1305-AES as defined in "The Poly1305-AES message-authentication code"
+// (Bernstein 2005, https://cr.yp.to/mac/poly1305-20050329.pdf) for any 128
+// bits cipher.Block.
+//
+// A Poly1305 key must only authenticate one message: two tags under the
+// same key reveal it and allow forgeries. With Poly1305-AES the same goes
+// for the nonce, which must never be reused under a given key.
+package poly1305
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+)
+
+const BlockSize int = 16
+const Size int = 16
+
+// KeySize is the size of a one-time key r || s.
+const KeySize int = 32
+
+// NonceSize is the size of the Poly1305-AES nonce, one cipher block.
+const NonceSize int = 16
+
+// Clamping of r, c.f. RFC8439 2.5.1
+const (
+	rMask0 = 0x0ffffffc0fffffff
+	rMask1 = 0x0ffffffc0ffffffc
+)
+
+// Limbs of p = 2^130 - 5
+const (
+	p0 = 0xfffffffffffffffb
+	p1 = 0xffffffffffffffff
+	p2 = 0x3
+)
+
+type mac struct {
+	r0, r1 uint64
+	s0, s1 uint64
+	// Accumulator h = h2 * 2^128 + h1 * 2^64 + h0, partially reduced
+	h0, h1, h2 uint64
+	buf        [BlockSize]byte
+	nx         int
+}
+
+// New returns a new Poly1305 MAC with the one-time key r || s.
+// Returns an error if the key is not 32 bytes.
+func New(key []byte) (hash.Hash, error) {
+	if len(key) != KeySize {
+		return nil, errors.New("poly1305: key must be 32 bytes")
+	}
+
+	return &mac{
+		r0: binary.LittleEndian.Uint64(key) & rMask0,
+		r1: binary.LittleEndian.Uint64(key[8:]) & rMask1,
+		s0: binary.LittleEndian.Uint64(key[16:]),
+		s1: binary.LittleEndian.Uint64(key[24:]),
+	}, nil
+}
+
+// NewWithCipher returns a new Poly1305-AES MAC, or Poly1305 with another
+// 128 bits cipher: s is CIPH_k(nonce) where b is keyed with k, and r is the
+// 16 bytes second half of the key.
+// Returns an error if the block size of b is not 128 bits or if r or the
+// nonce are not 16 bytes.
+// The nonce must be unique for a given key, including across calls to
+// Reset, which keeps the same nonce.
+func NewWithCipher(b cipher.Block, r, nonce []byte) (hash.Hash, error) {
+	if b.BlockSize() != BlockSize {
+		return nil, errors.New("poly1305: block size must be 128 bits")
+	}
+	if len(r) != 16 {
+		return nil, errors.New("poly1305: r must be 16 bytes")
+	}
+	if len(nonce) != NonceSize {
+		return nil, errors.New("poly1305: nonce must be 16 bytes")
+	}
+
+	key := make([]byte, KeySize)
+	copy(key, r)
+	b.Encrypt(key[16:], nonce)
+
+	return New(key)
+}
+
+// block absorbs the 16B blocks of p, hibit is 1 for full blocks and 0 for
+// the padded last block
+// c.f. RFC8439 2.5.1
+func (m *mac) block(p []byte, hibit uint64) {
+	h0, h1, h2 := m.h0, m.h1, m.h2
+	r0, r1 := m.r0, m.r1
+
+	for len(p) >= BlockSize {
+		// h += block with the 2^128 bit
+		var c uint64
+		h0, c = bits.Add64(h0, binary.LittleEndian.Uint64(p), 0)
+		h1, c = bits.Add64(h1, binary.LittleEndian.Uint64(p[8:]), c)
+		h2 += c + hibit
+
+		// h *= r, h2 is at most 7 and r is clamped so that the products
+		// fit in 4 words
+		h0r0hi, h0r0lo := bits.Mul64(h0, r0)
+		h1r0hi, h1r0lo := bits.Mul64(h1, r0)
+		h0r1hi, h0r1lo := bits.Mul64(h0, r1)
+		h1r1hi, h1r1lo := bits.Mul64(h1, r1)
+		h2r0 := h2 * r0
+		h2r1 := h2 * r1
+
+		t0 := h0r0lo
+		t1, c := bits.Add64(h0r0hi, h1r0lo, 0)
+		t2, c2 := bits.Add64(h1r0hi, h1r1lo, c)
+		t3 := h1r1hi + c2
+		t1, c = bits.Add64(t1, h0r1lo, 0)
+		t2, c = bits.Add64(t2, h0r1hi, c)
+		t3 += c
+		t2, c = bits.Add64(t2, h2r0, 0)
+		t3 += h2r1 + c
+
+		// Reduce with 2^130 = 5 mod p: the part above 2^130, times 4, is
+		// (t3, t2 & ^3), add it and its quarter to the low 130 bits
+		h0, h1, h2 = t0, t1, t2&3
+		cc0, cc1 := t2&^3, t3
+		h0, c = bits.Add64(h0, cc0, 0)
+		h1, c = bits.Add64(h1, cc1, c)
+		h2 += c
+		cc0, cc1 = cc0>>2|cc1<<62, cc1>>2
+		h0, c = bits.Add64(h0, cc0, 0)
+		h1, c = bits.Add64(h1, cc1, c)
+		h2 += c
+
+		p = p[BlockSize:]
+	}
+
+	m.h0, m.h1, m.h2 = h0, h1, h2
+}
+
+func (m *mac) Write(p []byte) (n int, err error) {
+	n = len(p)
+
+	// Fill the pending block first
+	if m.nx > 0 {
+		c := copy(m.buf[m.nx:], p)
+		m.nx += c
+		p = p[c:]
+
+		if m.nx < BlockSize {
+			return n, nil
+		}
+
+		m.block(m.buf[:], 1)
+		m.nx = 0
+	}
+
+	full := len(p) &^ (BlockSize - 1)
+	m.block(p[:full], 1)
+
+	// Keep the rest for later
+	m.nx = copy(m.buf[:], p[full:])
+
+	return n, nil
+}
+
+func (m0 *mac) Sum(b []byte) []byte {
+	// Work on a copy so that the caller can keep writing
+	m := *m0
+
+	// The last partial block is padded with 1 then zeros
+	if m.nx > 0 {
+		m.buf[m.nx] = 1
+		for i := m.nx + 1; i < BlockSize; i++ {
+			m.buf[i] = 0
+		}
+		m.block(m.buf[:], 0)
+	}
+
+	// Final reduction mod p in constant time: keep h - p if it does not
+	// borrow
+	t0, c := bits.Sub64(m.h0, p0, 0)
+	t1, c := bits.Sub64(m.h1, p1, c)
+	_, c = bits.Sub64(m.h2, p2, c)
+	mask := c - 1
+	h0 := m.h0&^mask | t0&mask
+	h1 := m.h1&^mask | t1&mask
+
+	// tag = (h + s) mod 2^128
+	h0, c = bits.Add64(h0, m.s0, 0)
+	h1, _ = bits.Add64(h1, m.s1, c)
+
+	b = binary.LittleEndian.AppendUint64(b, h0)
+	return binary.LittleEndian.AppendUint64(b, h1)
+}
+
+func (m *mac) Reset() {
+	m.h0, m.h1, m.h2 = 0, 0, 0
+	m.nx = 0
+}
+
+func (m *mac) Size() int {
+	return Size
+}
+
+func (m *mac) BlockSize() int {
+	return BlockSize
+}
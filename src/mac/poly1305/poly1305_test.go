@@ -0,0 +1,138 @@
+package poly1305
+
+import (
+	"crypto/aes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/loicbacciga/crypto-go/src/cipher/utils"
+)
+
+func TestRFC(t *testing.T) {
+	vectors := []struct {
+		keyHex, msgHex, expHex string
+	}{
+		// RFC 8439 2.5.2
+		{
+			"85d6be7857556d337f4452fe42d506a80103808afb0db2fd4abff6af4149f51b",
+			hex.EncodeToString([]byte("Cryptographic Forum Research Group")),
+			"a8061dc1305136c6c22b8baf0c0127a9",
+		},
+		// RFC 8439 A.3 edge cases of the reduction, checked with OpenSSL
+		{"0200000000000000000000000000000000000000000000000000000000000000", "ffffffffffffffffffffffffffffffff", "03000000000000000000000000000000"},
+		{"02000000000000000000000000000000ffffffffffffffffffffffffffffffff", "02000000000000000000000000000000", "03000000000000000000000000000000"},
+		{"0100000000000000000000000000000000000000000000000000000000000000", "fffffffffffffffffffffffffffffffff0ffffffffffffffffffffffffffffff11000000000000000000000000000000", "05000000000000000000000000000000"},
+		{"0100000000000000000000000000000000000000000000000000000000000000", "fffffffffffffffffffffffffffffffffbfefefefefefefefefefefefefefefe01010101010101010101010101010101", "00000000000000000000000000000000"},
+		{"0200000000000000000000000000000000000000000000000000000000000000", "fdffffffffffffffffffffffffffffff", "faffffffffffffffffffffffffffffff"},
+		{strings.Repeat("ff", 32), strings.Repeat("ff", 100), "b99c030d7ce939bb6607393e68656f22"},
+	}
+
+	for _, v := range vectors {
+		key, _ := hex.DecodeString(v.keyHex)
+		msg, _ := hex.DecodeString(v.msgHex)
+
+		mac, err := New(key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		mac.Write(msg)
+
+		if resHex := hex.EncodeToString(mac.Sum(nil)); resHex != v.expHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.expHex)
+		}
+	}
+}
+
+func TestPoly1305AES(t *testing.T) {
+	// Examples of the Poly1305-AES paper, appendix B
+	vectors := []struct {
+		kHex, rHex, nonceHex, msgHex, expHex string
+	}{
+		{
+			"ec074c835580741701425b623235add6",
+			"851fc40c3467ac0be05cc20404f3f700",
+			"fb447350c4e868c52ac3275cf9d4327e",
+			"f3f6",
+			"f4c633c3044fc145f84f335cb81953de",
+		},
+		{
+			"75deaa25c09f208e1dc4ce6b5cad3fbf",
+			"a0f3080000f46400d0c7e9076c834403",
+			"61ee09218d29b0aaed7e154a2c5509cc",
+			"",
+			"dd3fab2251f11ac759f0887129cc2ee7",
+		},
+	}
+
+	for _, v := range vectors {
+		k, _ := hex.DecodeString(v.kHex)
+		r, _ := hex.DecodeString(v.rHex)
+		nonce, _ := hex.DecodeString(v.nonceHex)
+		msg, _ := hex.DecodeString(v.msgHex)
+		b, _ := aes.NewCipher(k)
+
+		mac, err := NewWithCipher(b, r, nonce)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		mac.Write(msg)
+
+		if resHex := hex.EncodeToString(mac.Sum(nil)); resHex != v.expHex {
+			t.Errorf("Not equal %s!=%s", resHex, v.expHex)
+		}
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	key := make([]byte, KeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	msg := make([]byte, 1000)
+	for i := range msg {
+		msg[i] = byte(i % 251)
+	}
+	// Checked with OpenSSL
+	expHex := "6e9c2f823e9a252acd5b8e324b17d738"
+
+	for _, chunk := range []int{1, 7, 15, 16, 17, 1000} {
+		mac, _ := New(key)
+		for i := 0; i < len(msg); i += chunk {
+			end := i + chunk
+			if end > len(msg) {
+				end = len(msg)
+			}
+			mac.Write(msg[i:end])
+			// Sum in the middle must not change the result
+			mac.Sum(nil)
+		}
+
+		if resHex := hex.EncodeToString(mac.Sum(nil)); resHex != expHex {
+			t.Errorf("chunk %d: Not equal %s!=%s", chunk, resHex, expHex)
+		}
+
+		mac.Reset()
+		mac.Write(msg)
+		if resHex := hex.EncodeToString(mac.Sum(nil)); resHex != expHex {
+			t.Errorf("reset: Not equal %s!=%s", resHex, expHex)
+		}
+	}
+}
+
+func TestInvalidParameters(t *testing.T) {
+	if _, err := New(make([]byte, 16)); err == nil {
+		t.Error("key of 16 bytes accepted")
+	}
+
+	b, _ := aes.NewCipher(make([]byte, 16))
+	if _, err := NewWithCipher(utils.NewDummyCipher(8), make([]byte, 16), make([]byte, 16)); err == nil {
+		t.Error("64 bits block cipher accepted")
+	}
+	if _, err := NewWithCipher(b, make([]byte, 32), make([]byte, 16)); err == nil {
+		t.Error("r of 32 bytes accepted")
+	}
+	if _, err := NewWithCipher(b, make([]byte, 16), make([]byte, 12)); err == nil {
+		t.Error("nonce of 12 bytes accepted")
+	}
+}
@@ -0,0 +1,69 @@
+// Package xcbc implements the XCBC-MAC of Black and Rogaway, as defined for
+// AES in RFC 3566 (https://datatracker.ietf.org/doc/html/rfc3566), over any
+// block cipher, e.g.
+//
+//	mac, err := xcbc.New96(aes.NewCipher, key)
+//	mac.Write(message)
+//	tag := mac.Sum(nil)
+package xcbc
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"hash"
+
+	"github.com/loicbacciga/crypto-go/src/mac/cmac"
+)
+
+// Size96 is the length of an XCBC-MAC-96 tag, in bytes.
+const Size96 = 12
+
+// New returns a new XCBC-MAC using the cipher created by newCipher with key.
+// Returns the error of newCipher if key is invalid.
+// c.f. RFC3566 4
+func New(newCipher func(key []byte) (cipher.Block, error), key []byte) (hash.Hash, error) {
+	b, err := newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// K1 = E(K, 0x01...), K2 = E(K, 0x02...), K3 = E(K, 0x03...)
+	blockSize := b.BlockSize()
+	k := make([][]byte, 3)
+	for i := range k {
+		k[i] = bytes.Repeat([]byte{byte(i + 1)}, blockSize)
+		b.Encrypt(k[i], k[i])
+	}
+
+	b1, err := newCipher(k[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return cmac.NewWithSubkeys(b1, k[1], k[2]), nil
+}
+
+type truncated struct {
+	hash.Hash
+	size int
+}
+
+// New96 returns a new XCBC-MAC-96, the XCBC-MAC truncated to its first 96
+// bits, as used by IPsec.
+// c.f. RFC3566 4.3
+func New96(newCipher func(key []byte) (cipher.Block, error), key []byte) (hash.Hash, error) {
+	mac, err := New(newCipher, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &truncated{mac, Size96}, nil
+}
+
+func (t *truncated) Sum(b []byte) []byte {
+	return append(b, t.Hash.Sum(nil)[:t.size]...)
+}
+
+func (t *truncated) Size() int {
+	return t.size
+}
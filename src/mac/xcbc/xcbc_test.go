@@ -0,0 +1,58 @@
+package xcbc
+
+import (
+	"crypto/aes"
+	"encoding/hex"
+	"testing"
+)
+
+// Test vectors of RFC 3566 section 4.6
+func TestRFC3566(t *testing.T) {
+	key, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	msg := make([]byte, 34)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+
+	vectors := []struct {
+		msg []byte
+		exp string
+	}{
+		{nil, "75f0251d528ac01c4573dfd584d79f29"},
+		{msg[:3], "5b376580ae2f19afe7219ceef172756f"},
+		{msg[:16], "d2a246fa349b68a79998a4394ff7a263"},
+		{msg[:20], "47f51b4564966215b8985c63055ed308"},
+		{msg[:32], "f54f0ec8d2b9f3d36807734bd5283fd4"},
+		{msg[:34], "becbb3bccdb518a30677d5481fb6b4d8"},
+		{make([]byte, 1000), "f0dafee895db30253761103b5d84528f"},
+	}
+
+	for _, v := range vectors {
+		mac, err := New(aes.NewCipher, key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		mac.Write(v.msg)
+		if resHex := hex.EncodeToString(mac.Sum(nil)); resHex != v.exp {
+			t.Errorf("Not equal %s!=%s", resHex, v.exp)
+		}
+
+		mac96, err := New96(aes.NewCipher, key)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		mac96.Write(v.msg)
+		if resHex := hex.EncodeToString(mac96.Sum(nil)); resHex != v.exp[:2*Size96] {
+			t.Errorf("Not equal %s!=%s", resHex, v.exp[:2*Size96])
+		}
+		if mac96.Size() != Size96 {
+			t.Errorf("Not equal %d!=%d", mac96.Size(), Size96)
+		}
+	}
+}
+
+func TestInvalidKey(t *testing.T) {
+	if _, err := New(aes.NewCipher, make([]byte, 15)); err == nil {
+		t.Error("invalid key accepted")
+	}
+}
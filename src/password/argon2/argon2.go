@@ -0,0 +1,350 @@
+// Package argon2 implements the Argon2 memory-hard password hashing
+// function as defined in RFC 9106 (https://datatracker.ietf.org/doc/html/rfc9106),
+// in its three variants Argon2d, Argon2i and Argon2id.
+//
+// Hashes can be stored and verified in the PHC string format, e.g.
+//
+//	$argon2id$v=19$m=65536,t=3,p=4$c29tZXNhbHQ$Zh/vvW8pvLyPRkarwyqdekZFu1wFlTf4pVh/Ma2+zM0
+package argon2
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/loicbacciga/crypto-go/src/hash/blake2b"
+)
+
+// Variant is the type of Argon2 (y in the RFC).
+type Variant uint32
+
+const (
+	Argon2d Variant = iota
+	Argon2i
+	Argon2id
+)
+
+// Version is the only Argon2 version implemented, 0x13.
+const Version = 0x13
+
+// Number of slices in a lane (SL in the RFC)
+const syncPoints = 4
+
+// Number of addresses in a block, for the data-independent variants
+const addressesPerBlock = blockWords
+
+func (v Variant) String() string {
+	switch v {
+	case Argon2d:
+		return "argon2d"
+	case Argon2i:
+		return "argon2i"
+	case Argon2id:
+		return "argon2id"
+	default:
+		return "unknown"
+	}
+}
+
+// Params are the cost parameters of Argon2.
+type Params struct {
+	Variant Variant
+	// Number of passes (t)
+	Time uint32
+	// Memory size in KiB (m), at least 8 * Threads
+	Memory uint32
+	// Degree of parallelism (p), the number of lanes computed in parallel
+	Threads uint32
+	// Length of the tag in bytes (T), at least 4
+	KeyLen uint32
+}
+
+// DefaultParams are the parameters of the second recommended option of
+// RFC 9106 section 4, for memory-constrained environments.
+var DefaultParams = Params{
+	Variant: Argon2id,
+	Time:    3,
+	Memory:  64 * 1024,
+	Threads: 4,
+	KeyLen:  32,
+}
+
+// validate checks the parameters ranges of RFC9106 3.1
+func (p *Params) validate() error {
+	if p.Variant > Argon2id {
+		return errors.New("argon2: unknown variant")
+	}
+	if p.Time < 1 {
+		return errors.New("argon2: number of passes must be at least 1")
+	}
+	if p.Threads < 1 || p.Threads > 1<<24-1 {
+		return errors.New("argon2: degree of parallelism must be between 1 and 2^24-1")
+	}
+	if uint64(p.Memory) < 8*uint64(p.Threads) {
+		return errors.New("argon2: memory must be at least 8 * parallelism KiB")
+	}
+	if p.KeyLen < 4 {
+		return errors.New("argon2: tag length must be at least 4 bytes")
+	}
+	return nil
+}
+
+// Key derives a key from password and salt.
+// The salt should be random and at least 16 bytes long.
+func Key(password, salt []byte, p Params) ([]byte, error) {
+	return KeyWithSecret(password, salt, nil, nil, p)
+}
+
+// KeyWithSecret is like Key with the optional secret value K and associated
+// data X of the RFC.
+func KeyWithSecret(password, salt, secret, data []byte, p Params) ([]byte, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	if len(salt) < 8 {
+		return nil, errors.New("argon2: salt must be at least 8 bytes")
+	}
+
+	h0 := initHash(password, salt, secret, data, p)
+	b := initBlocks(&h0, p)
+	processBlocks(b, p)
+
+	return extractKey(b, p), nil
+}
+
+// initHash computes H_0
+// c.f. RFC9106 3.2 step 1
+func initHash(password, salt, secret, data []byte, p Params) [blake2b.Size + 8]byte {
+	var h0 [blake2b.Size + 8]byte
+
+	h, _ := blake2b.New512(nil)
+	le32 := func(x uint32) {
+		h.Write(binary.LittleEndian.AppendUint32(nil, x))
+	}
+
+	le32(p.Threads)
+	le32(p.KeyLen)
+	le32(p.Memory)
+	le32(p.Time)
+	le32(Version)
+	le32(uint32(p.Variant))
+	le32(uint32(len(password)))
+	h.Write(password)
+	le32(uint32(len(salt)))
+	h.Write(salt)
+	le32(uint32(len(secret)))
+	h.Write(secret)
+	le32(uint32(len(data)))
+	h.Write(data)
+
+	// The 8 last bytes are filled with the block and lane indices later
+	h.Sum(h0[:0])
+
+	return h0
+}
+
+// hashPrime is the variable-length hash function H'
+// c.f. RFC9106 3.3
+func hashPrime(out, in []byte) {
+	outLen := binary.LittleEndian.AppendUint32(nil, uint32(len(out)))
+
+	if len(out) <= blake2b.Size {
+		h, _ := blake2b.New(len(out), nil)
+		h.Write(outLen)
+		h.Write(in)
+		copy(out, h.Sum(nil))
+		return
+	}
+
+	// V_1 = H^(64)(LE32(T) || A)
+	h, _ := blake2b.New512(nil)
+	h.Write(outLen)
+	h.Write(in)
+	v := h.Sum(nil)
+
+	// Output the first 32 bytes of each V_i, V_i = H^(64)(V_{i-1})
+	r := (len(out)+31)/32 - 2
+	for i := 0; i < r; i++ {
+		if i > 0 {
+			h.Reset()
+			h.Write(v)
+			v = h.Sum(v[:0])
+		}
+		copy(out[32*i:], v[:32])
+	}
+
+	// V_{r+1} = H^(T-32*r)(V_r)
+	h, _ = blake2b.New(len(out)-32*r, nil)
+	h.Write(v)
+	copy(out[32*r:], h.Sum(nil))
+}
+
+// memoryBlocks returns the number of blocks m' and of columns q
+func memoryBlocks(p Params) (uint32, uint32) {
+	m := p.Memory / (syncPoints * p.Threads) * (syncPoints * p.Threads)
+	return m, m / p.Threads
+}
+
+// initBlocks allocates the memory and computes the two first blocks of
+// each lane
+// c.f. RFC9106 3.2 steps 2 to 4
+func initBlocks(h0 *[blake2b.Size + 8]byte, p Params) []block {
+	m, q := memoryBlocks(p)
+	b := make([]block, m)
+
+	var buf [1024]byte
+	for lane := uint32(0); lane < p.Threads; lane++ {
+		for j := uint32(0); j < 2; j++ {
+			// B[i][j] = H'^(1024)(H_0 || LE32(j) || LE32(i))
+			binary.LittleEndian.PutUint32(h0[blake2b.Size:], j)
+			binary.LittleEndian.PutUint32(h0[blake2b.Size+4:], lane)
+			hashPrime(buf[:], h0[:])
+
+			blk := &b[lane*q+j]
+			for k := range blk {
+				blk[k] = binary.LittleEndian.Uint64(buf[8*k:])
+			}
+		}
+	}
+
+	return b
+}
+
+// processBlocks fills the memory, the lanes of a slice are computed in
+// parallel.
+// c.f. RFC9106 3.2 steps 5 and 6
+func processBlocks(b []block, p Params) {
+	var wg sync.WaitGroup
+
+	for pass := uint32(0); pass < p.Time; pass++ {
+		for slice := uint32(0); slice < syncPoints; slice++ {
+			wg.Add(int(p.Threads))
+			for lane := uint32(0); lane < p.Threads; lane++ {
+				go func(lane uint32) {
+					processSegment(b, p, pass, slice, lane)
+					wg.Done()
+				}(lane)
+			}
+			wg.Wait()
+		}
+	}
+}
+
+// processSegment computes the blocks of one segment
+func processSegment(b []block, p Params, pass, slice, lane uint32) {
+	m, q := memoryBlocks(p)
+	segmentLength := q / syncPoints
+
+	// Data-independent addressing, c.f. RFC9106 3.4.1.2
+	independent := p.Variant == Argon2i || (p.Variant == Argon2id && pass == 0 && slice < syncPoints/2)
+	var addresses, input, zero block
+	if independent {
+		input[0] = uint64(pass)
+		input[1] = uint64(lane)
+		input[2] = uint64(slice)
+		input[3] = uint64(m)
+		input[4] = uint64(p.Time)
+		input[5] = uint64(p.Variant)
+	}
+	nextAddresses := func() {
+		input[6]++
+		compress(&addresses, &zero, &input, false)
+		compress(&addresses, &zero, &addresses, false)
+	}
+
+	index := uint32(0)
+	if pass == 0 && slice == 0 {
+		// The two first blocks are already computed
+		index = 2
+		if independent {
+			nextAddresses()
+		}
+	}
+
+	offset := lane*q + slice*segmentLength + index
+	for ; index < segmentLength; index, offset = index+1, offset+1 {
+		prev := offset - 1
+		if index == 0 && slice == 0 {
+			// Wrap around to the last block of the lane
+			prev += q
+		}
+
+		// J_1 || J_2
+		var rand uint64
+		if independent {
+			if index%addressesPerBlock == 0 {
+				nextAddresses()
+			}
+			rand = addresses[index%addressesPerBlock]
+		} else {
+			rand = b[prev][0]
+		}
+
+		ref := refIndex(rand, p, q, segmentLength, pass, slice, lane, index)
+
+		// From the second pass, the new block is xored with the old one
+		compress(&b[offset], &b[prev], &b[ref], pass > 0)
+	}
+}
+
+// refIndex maps J_1 and J_2 to the index of the reference block
+// c.f. RFC9106 3.4.1 and 3.4.2
+func refIndex(rand uint64, p Params, q, segmentLength, pass, slice, lane, index uint32) uint32 {
+	// l = J_2 mod p
+	refLane := uint32(rand>>32) % p.Threads
+	if pass == 0 && slice == 0 {
+		refLane = lane
+	}
+
+	// Size of the reference set W and its start position
+	var size, start uint32
+	if pass == 0 {
+		start = 0
+		size = slice * segmentLength
+		if slice == 0 || refLane == lane {
+			size += index
+		}
+	} else {
+		start = ((slice + 1) % syncPoints) * segmentLength
+		size = (syncPoints - 1) * segmentLength
+		if refLane == lane {
+			size += index
+		}
+	}
+	if index == 0 || refLane == lane {
+		// Exclude the previous block
+		size--
+	}
+
+	// x = J_1^2 / 2^32, y = (|W| * x) / 2^32, zz = |W| - 1 - y
+	x := (rand & 0xffffffff) * (rand & 0xffffffff) >> 32
+	y := (uint64(size) * x) >> 32
+	zz := uint64(size) - 1 - y
+
+	return refLane*q + uint32((uint64(start)+zz)%uint64(q))
+}
+
+// extractKey computes the final tag
+// c.f. RFC9106 3.2 steps 7 and 8
+func extractKey(b []block, p Params) []byte {
+	_, q := memoryBlocks(p)
+
+	// C = B[0][q-1] xor ... xor B[p-1][q-1]
+	c := b[q-1]
+	for lane := uint32(1); lane < p.Threads; lane++ {
+		last := &b[lane*q+q-1]
+		for i := range c {
+			c[i] ^= last[i]
+		}
+	}
+
+	var buf [1024]byte
+	for i, w := range c {
+		binary.LittleEndian.PutUint64(buf[8*i:], w)
+	}
+
+	key := make([]byte, p.KeyLen)
+	hashPrime(key, buf[:])
+
+	return key
+}
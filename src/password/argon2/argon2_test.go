@@ -0,0 +1,154 @@
+package argon2
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// RFC 9106 section 5
+var rfcPassword = bytes.Repeat([]byte{0x01}, 32)
+var rfcSalt = bytes.Repeat([]byte{0x02}, 16)
+var rfcSecret = bytes.Repeat([]byte{0x03}, 8)
+var rfcData = bytes.Repeat([]byte{0x04}, 12)
+
+func TestRFC9106(t *testing.T) {
+	expHexs := map[Variant]string{
+		Argon2d:  "512b391b6f1162975371d30919734294f868e3be3984f3c1a13a4db9fabe4acb",
+		Argon2i:  "c814d9d1dc7f37aa13f0d77f2494bda1c8de6b016dd388d29952a4c4672b6ce8",
+		Argon2id: "0d640df58d78766c08c037a34a8b53c9d01ef0452d75b65eb52520e96b01e659",
+	}
+
+	for variant, expHex := range expHexs {
+		p := Params{Variant: variant, Time: 3, Memory: 32, Threads: 4, KeyLen: 32}
+
+		res, err := KeyWithSecret(rfcPassword, rfcSalt, rfcSecret, rfcData, p)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if resHex := hex.EncodeToString(res); resHex != expHex {
+			t.Errorf("%s: %s != %s", variant, resHex, expHex)
+		}
+	}
+}
+
+func TestPHC(t *testing.T) {
+	// Reference implementation:
+	// echo -n password | argon2 somesalt -id -t 2 -m 16 -p 1
+	encoded := "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+
+	p, salt, key, err := Decode(encoded)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	exp := Params{Variant: Argon2id, Time: 2, Memory: 65536, Threads: 1, KeyLen: 32}
+	if p != exp {
+		t.Errorf("%v != %v", p, exp)
+	}
+
+	if res := Encode(p, salt, key); res != encoded {
+		t.Errorf("%s != %s", res, encoded)
+	}
+
+	if err := Verify(encoded, []byte("password")); err != nil {
+		t.Error(err.Error())
+	}
+	if err := Verify(encoded, []byte("Password")); err != ErrMismatchedHashAndPassword {
+		t.Errorf("wrong password: %v", err)
+	}
+}
+
+func TestHash(t *testing.T) {
+	p := Params{Variant: Argon2i, Time: 1, Memory: 64, Threads: 2, KeyLen: 16}
+
+	encoded, err := Hash([]byte("secret"), p)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := Verify(encoded, []byte("secret")); err != nil {
+		t.Error(err.Error())
+	}
+	if err := Verify(encoded, []byte("secreT")); err != ErrMismatchedHashAndPassword {
+		t.Errorf("wrong password: %v", err)
+	}
+
+	// Two hashes of the same password use different salts
+	encoded2, _ := Hash([]byte("secret"), p)
+	if encoded == encoded2 {
+		t.Error("same salt used twice")
+	}
+}
+
+func TestVerifyLimits(t *testing.T) {
+	encoded := "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+
+	limits := []Limits{
+		{MaxMemory: 65535, MaxTime: 2, MaxThreads: 1},
+		{MaxMemory: 65536, MaxTime: 1, MaxThreads: 1},
+		{MaxMemory: 65536, MaxTime: 2, MaxThreads: 0},
+	}
+	for _, l := range limits {
+		if err := VerifyWithLimits(encoded, []byte("password"), l); err != ErrLimitsExceeded {
+			t.Errorf("%v: %v", l, err)
+		}
+	}
+
+	l := Limits{MaxMemory: 65536, MaxTime: 2, MaxThreads: 1}
+	if err := VerifyWithLimits(encoded, []byte("password"), l); err != nil {
+		t.Error(err.Error())
+	}
+
+	// Would allocate 4 TiB and start 2^24-1 goroutines without the limits
+	huge := []string{
+		"$argon2id$v=19$m=4294967295,t=1,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=4294967295,t=1,p=16777215$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=64,t=4294967295,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+	}
+	for _, encoded := range huge {
+		if err := Verify(encoded, []byte("password")); err != ErrLimitsExceeded {
+			t.Errorf("%q: %v", encoded, err)
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ",
+		"$argon2x$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=16$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$t=2,m=65536,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=65536,t=0,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=4,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ=$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+	}
+
+	for _, encoded := range invalid {
+		if _, _, _, err := Decode(encoded); err == nil {
+			t.Errorf("%q accepted", encoded)
+		}
+	}
+}
+
+func TestInvalidParameters(t *testing.T) {
+	params := []Params{
+		{Variant: 3, Time: 1, Memory: 64, Threads: 1, KeyLen: 32},
+		{Variant: Argon2id, Time: 0, Memory: 64, Threads: 1, KeyLen: 32},
+		{Variant: Argon2id, Time: 1, Memory: 15, Threads: 2, KeyLen: 32},
+		{Variant: Argon2id, Time: 1, Memory: 64, Threads: 0, KeyLen: 32},
+		{Variant: Argon2id, Time: 1, Memory: 64, Threads: 1, KeyLen: 3},
+	}
+
+	for _, p := range params {
+		if _, err := Key([]byte("password"), []byte("somesalt"), p); err == nil {
+			t.Errorf("%v accepted", p)
+		}
+	}
+
+	if _, err := Key([]byte("password"), []byte("salt"), DefaultParams); err == nil {
+		t.Error("short salt accepted")
+	}
+}
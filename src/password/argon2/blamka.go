@@ -0,0 +1,83 @@
+package argon2
+
+import (
+	"math/bits"
+)
+
+// Number of 64-bit words in a block
+const blockWords = 1024 / 8
+
+type block [blockWords]uint64
+
+// fBlaMka is the multiplication-hardened addition of Argon2
+func fBlaMka(x, y uint64) uint64 {
+	return x + y + 2*uint64(uint32(x))*uint64(uint32(y))
+}
+
+// gb is the modified BLAKE2b mixing function
+// c.f. RFC9106 3.6
+func gb(v []uint64, a, b, c, d int) {
+	v[a] = fBlaMka(v[a], v[b])
+	v[d] = bits.RotateLeft64(v[d]^v[a], -32)
+	v[c] = fBlaMka(v[c], v[d])
+	v[b] = bits.RotateLeft64(v[b]^v[c], -24)
+	v[a] = fBlaMka(v[a], v[b])
+	v[d] = bits.RotateLeft64(v[d]^v[a], -16)
+	v[c] = fBlaMka(v[c], v[d])
+	v[b] = bits.RotateLeft64(v[b]^v[c], -63)
+}
+
+// permute is the permutation P on 16 words (eight 16-byte registers)
+// c.f. RFC9106 3.6
+func permute(v []uint64) {
+	gb(v, 0, 4, 8, 12)
+	gb(v, 1, 5, 9, 13)
+	gb(v, 2, 6, 10, 14)
+	gb(v, 3, 7, 11, 15)
+	gb(v, 0, 5, 10, 15)
+	gb(v, 1, 6, 11, 12)
+	gb(v, 2, 7, 8, 13)
+	gb(v, 3, 4, 9, 14)
+}
+
+// compress is the compression function G.
+// out = G(x, y), or out ^= G(x, y) if xor is set.
+// c.f. RFC9106 3.5
+func compress(out, x, y *block, xor bool) {
+	// R = X xor Y
+	var r, q block
+	for i := range r {
+		r[i] = x[i] ^ y[i]
+	}
+	q = r
+
+	// Apply P on the rows of R, the 8 registers of a row are consecutive
+	for i := 0; i < 8; i++ {
+		permute(q[16*i : 16*(i+1)])
+	}
+
+	// Apply P on the columns, gathering the 8 registers of each column
+	var col [16]uint64
+	for i := 0; i < 8; i++ {
+		for j := 0; j < 8; j++ {
+			col[2*j] = q[2*i+16*j]
+			col[2*j+1] = q[2*i+16*j+1]
+		}
+		permute(col[:])
+		for j := 0; j < 8; j++ {
+			q[2*i+16*j] = col[2*j]
+			q[2*i+16*j+1] = col[2*j+1]
+		}
+	}
+
+	// Z xor R
+	if xor {
+		for i := range out {
+			out[i] ^= q[i] ^ r[i]
+		}
+	} else {
+		for i := range out {
+			out[i] = q[i] ^ r[i]
+		}
+	}
+}
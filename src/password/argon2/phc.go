@@ -0,0 +1,166 @@
+package argon2
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Length of the salts generated by Hash
+const saltLen = 16
+
+// ErrMismatchedHashAndPassword is returned by Verify when the password does
+// not match the hash.
+var ErrMismatchedHashAndPassword = errors.New("argon2: hashed password does not match the given password")
+
+// The PHC format uses base64 without padding
+var b64 = base64.RawStdEncoding
+
+// Hash derives a key from password and a random salt, and returns it in
+// the PHC string format.
+func Hash(password []byte, p Params) (string, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key, err := Key(password, salt, p)
+	if err != nil {
+		return "", err
+	}
+
+	return Encode(p, salt, key), nil
+}
+
+// Encode returns the PHC string of a key,
+// $<variant>$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>.
+func Encode(p Params, salt, key []byte) string {
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		p.Variant, Version, p.Memory, p.Time, p.Threads,
+		b64.EncodeToString(salt), b64.EncodeToString(key))
+}
+
+// Decode parses a PHC string produced by Encode.
+// The returned KeyLen is the length of the key.
+func Decode(encoded string) (p Params, salt, key []byte, err error) {
+	fields := strings.Split(encoded, "$")
+	if len(fields) != 6 || fields[0] != "" {
+		return p, nil, nil, errors.New("argon2: invalid PHC string")
+	}
+
+	// Variant
+	switch fields[1] {
+	case Argon2d.String():
+		p.Variant = Argon2d
+	case Argon2i.String():
+		p.Variant = Argon2i
+	case Argon2id.String():
+		p.Variant = Argon2id
+	default:
+		return p, nil, nil, errors.New("argon2: unknown variant " + fields[1])
+	}
+
+	// Version
+	if fields[2] != "v="+strconv.Itoa(Version) {
+		return p, nil, nil, errors.New("argon2: unsupported version " + fields[2])
+	}
+
+	// Parameters, in the order m, t, p
+	params := strings.Split(fields[3], ",")
+	names := []string{"m=", "t=", "p="}
+	values := []*uint32{&p.Memory, &p.Time, &p.Threads}
+	if len(params) != len(names) {
+		return p, nil, nil, errors.New("argon2: invalid parameters " + fields[3])
+	}
+	for i, param := range params {
+		if !strings.HasPrefix(param, names[i]) {
+			return p, nil, nil, errors.New("argon2: invalid parameters " + fields[3])
+		}
+		v, err := strconv.ParseUint(param[len(names[i]):], 10, 32)
+		if err != nil {
+			return p, nil, nil, errors.New("argon2: invalid parameters " + fields[3])
+		}
+		*values[i] = uint32(v)
+	}
+
+	// Salt and key
+	if salt, err = b64.DecodeString(fields[4]); err != nil {
+		return p, nil, nil, errors.New("argon2: invalid salt encoding")
+	}
+	if key, err = b64.DecodeString(fields[5]); err != nil {
+		return p, nil, nil, errors.New("argon2: invalid hash encoding")
+	}
+	p.KeyLen = uint32(len(key))
+
+	if err := p.validate(); err != nil {
+		return p, nil, nil, err
+	}
+
+	return p, salt, key, nil
+}
+
+// Limits are the largest cost parameters accepted when verifying a PHC
+// string, which may come from an untrusted source.
+type Limits struct {
+	// Memory size in KiB
+	MaxMemory uint32
+	// Number of passes
+	MaxTime uint32
+	// Degree of parallelism
+	MaxThreads uint32
+}
+
+// DefaultLimits are the limits used by Verify. They accept both recommended
+// options of RFC 9106 section 4.
+var DefaultLimits = Limits{
+	MaxMemory:  2 * 1024 * 1024,
+	MaxTime:    16,
+	MaxThreads: 64,
+}
+
+// ErrLimitsExceeded is returned by Verify when the parameters of the PHC
+// string exceed the limits.
+var ErrLimitsExceeded = errors.New("argon2: parameters exceed the limits")
+
+// check returns ErrLimitsExceeded if p exceeds the limits
+func (l *Limits) check(p Params) error {
+	if p.Memory > l.MaxMemory || p.Time > l.MaxTime || p.Threads > l.MaxThreads {
+		return ErrLimitsExceeded
+	}
+	return nil
+}
+
+// Verify checks that password matches a PHC string, whose parameters must
+// not exceed DefaultLimits.
+// Returns ErrMismatchedHashAndPassword if it does not, or another error if
+// the string cannot be decoded.
+func Verify(encoded string, password []byte) error {
+	return VerifyWithLimits(encoded, password, DefaultLimits)
+}
+
+// VerifyWithLimits is like Verify with the given limits on the parameters.
+// Returns ErrLimitsExceeded before computing anything if they are exceeded.
+func VerifyWithLimits(encoded string, password []byte, l Limits) error {
+	p, salt, key, err := Decode(encoded)
+	if err != nil {
+		return err
+	}
+	if err := l.check(p); err != nil {
+		return err
+	}
+
+	res, err := Key(password, salt, p)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare(res, key) != 1 {
+		return ErrMismatchedHashAndPassword
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package bcrypt
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/loicbacciga/crypto-go/src/cipher/blowfish"
+)
+
+// bcrypt, c.f. "A Future-Adaptable Password Scheme" (Provos, Mazieres 1999)
+// Hashes are in the modular crypt format $2b$<cost>$<salt><hash>.
+
+const (
+	MinCost     = 4
+	MaxCost     = 31
+	DefaultCost = 10
+)
+
+// Maximum length of a password, including the terminating NUL byte
+const maxPasswordLen = 72
+
+const (
+	saltLen    = 16
+	encSaltLen = 22
+	// Only 23 of the 24 bytes of the ciphertext are kept
+	hashLen    = 23
+	encHashLen = 31
+)
+
+// ErrMismatchedHashAndPassword is returned by CompareHashAndPassword when
+// the password does not match the hash.
+var ErrMismatchedHashAndPassword = errors.New("bcrypt: hashed password does not match the given password")
+
+// ErrPasswordTooLong is returned by GenerateFromPassword when the password
+// is longer than 72 bytes.
+var ErrPasswordTooLong = errors.New("bcrypt: password length exceeds 72 bytes")
+
+// bcrypt uses its own base64 alphabet, without padding
+var b64 = base64.NewEncoding("./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789").WithPadding(base64.NoPadding)
+
+// The text encrypted 64 times by EksBlowfish
+var magicText = []byte("OrpheanBeholderScryDoubt")
+
+// GenerateFromPassword returns the bcrypt hash of password, using a random
+// salt and 2^cost iterations.
+func GenerateFromPassword(password []byte, cost int) ([]byte, error) {
+	if cost < MinCost || cost > MaxCost {
+		return nil, fmt.Errorf("bcrypt: cost %d is outside [%d, %d]", cost, MinCost, MaxCost)
+	}
+	if len(password) > maxPasswordLen {
+		return nil, ErrPasswordTooLong
+	}
+
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return encode('b', cost, salt, Key(password, salt, cost)), nil
+}
+
+// Key returns the raw 23 bytes bcrypt hash of password with a 16 bytes
+// salt.
+// Passwords longer than 72 bytes are truncated.
+func Key(password, salt []byte, cost int) []byte {
+	if len(salt) != saltLen {
+		log.Panic("bcrypt: salt must be 16 bytes")
+	}
+	if cost < MinCost || cost > MaxCost {
+		log.Panic("bcrypt: invalid cost")
+	}
+
+	// The key includes the terminating NUL byte
+	key := make([]byte, len(password)+1)
+	copy(key, password)
+	if len(key) > maxPasswordLen {
+		key = key[:maxPasswordLen]
+	}
+
+	c := eksSetup(uint(cost), salt, key)
+
+	text := make([]byte, len(magicText))
+	copy(text, magicText)
+	for i := 0; i < 64; i++ {
+		// ECB mode
+		for j := 0; j < len(text); j += blowfish.BlockSize {
+			c.Encrypt(text[j:], text[j:])
+		}
+	}
+
+	return text[:hashLen]
+}
+
+// eksSetup is the expensive key schedule EksBlowfishSetup
+func eksSetup(cost uint, salt, key []byte) *blowfish.Cipher {
+	c, _ := blowfish.NewSalted(key, salt)
+
+	rounds := uint64(1) << cost
+	for i := uint64(0); i < rounds; i++ {
+		c.ExpandKey(key)
+		c.ExpandKey(salt)
+	}
+
+	return c
+}
+
+// CompareHashAndPassword checks that password matches a bcrypt hash.
+// Returns ErrMismatchedHashAndPassword if it does not, or another error if
+// the hash cannot be decoded.
+func CompareHashAndPassword(hashedPassword, password []byte) error {
+	minor, cost, salt, hash, err := decode(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	res := encode(minor, cost, salt, Key(password, salt, cost))
+	if subtle.ConstantTimeCompare(res, encode(minor, cost, salt, hash)) != 1 {
+		return ErrMismatchedHashAndPassword
+	}
+
+	return nil
+}
+
+// Cost returns the cost of a bcrypt hash.
+func Cost(hashedPassword []byte) (int, error) {
+	_, cost, _, _, err := decode(hashedPassword)
+	return cost, err
+}
+
+// encode returns $2<minor>$<cost>$<salt><hash>
+func encode(minor byte, cost int, salt, hash []byte) []byte {
+	res := []byte("$2")
+	if minor != 0 {
+		res = append(res, minor)
+	}
+	res = append(res, fmt.Sprintf("$%02d$", cost)...)
+	res = append(res, b64.EncodeToString(salt)...)
+	res = append(res, b64.EncodeToString(hash)...)
+
+	return res
+}
+
+// decode parses a hash produced by encode.
+// The versions $2$, $2a$, $2b$ and $2y$ are accepted, and all compute
+// the same function.
+func decode(h []byte) (minor byte, cost int, salt, hash []byte, err error) {
+	if len(h) < 3 || h[0] != '$' || h[1] != '2' {
+		return 0, 0, nil, nil, errors.New("bcrypt: invalid hash prefix")
+	}
+	h = h[2:]
+
+	if h[0] != '$' {
+		minor = h[0]
+		if minor != 'a' && minor != 'b' && minor != 'y' {
+			return 0, 0, nil, nil, errors.New("bcrypt: unsupported version $2" + string(minor) + "$")
+		}
+		h = h[1:]
+	}
+
+	if len(h) != 4+encSaltLen+encHashLen || h[0] != '$' || h[3] != '$' {
+		return 0, 0, nil, nil, errors.New("bcrypt: invalid hash format")
+	}
+
+	cost, err = strconv.Atoi(string(h[1:3]))
+	if err != nil || cost < MinCost || cost > MaxCost {
+		return 0, 0, nil, nil, errors.New("bcrypt: invalid cost " + string(h[1:3]))
+	}
+	h = h[4:]
+
+	if salt, err = b64.DecodeString(string(h[:encSaltLen])); err != nil {
+		return 0, 0, nil, nil, errors.New("bcrypt: invalid salt encoding")
+	}
+	if hash, err = b64.DecodeString(string(h[encSaltLen:])); err != nil {
+		return 0, 0, nil, nil, errors.New("bcrypt: invalid hash encoding")
+	}
+
+	return minor, cost, salt, hash, nil
+}
@@ -0,0 +1,80 @@
+package bcrypt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Vectors generated with libxcrypt and OpenBSD
+var vectors = []struct {
+	password string
+	hash     string
+}{
+	{"", "$2b$04$......................w74bL5gU7LSJClZClCa.Pkz14aTv/XO"},
+	{"password", "$2b$05$abcdefghijklmnopqrstuuWG29KuyeAicPCJODk1zjyGvyQUU2awu"},
+	{"U*U", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW"},
+	// Truncated to 72 bytes, with unused bits in the salt
+	{strings.Repeat("0123456789", 8), "$2y$06$ABCDEFGHIJKLMNOPQRSTUVY3mV4jGEbnOb0LydplLq52gcKalS.3O"},
+}
+
+func TestVectors(t *testing.T) {
+	for _, v := range vectors {
+		if err := CompareHashAndPassword([]byte(v.hash), []byte(v.password)); err != nil {
+			t.Errorf("%s: %s", v.hash, err.Error())
+		}
+		if err := CompareHashAndPassword([]byte(v.hash), []byte("x"+v.password)); err != ErrMismatchedHashAndPassword {
+			t.Errorf("%s: wrong password: %v", v.hash, err)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	hash, err := GenerateFromPassword([]byte("secret"), MinCost)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !strings.HasPrefix(string(hash), "$2b$04$") || len(hash) != 60 {
+		t.Errorf("Invalid hash %s", hash)
+	}
+	if cost, err := Cost(hash); err != nil || cost != MinCost {
+		t.Errorf("Not equal %d!=%d", cost, MinCost)
+	}
+	if err := CompareHashAndPassword(hash, []byte("secret")); err != nil {
+		t.Error(err.Error())
+	}
+
+	long := bytes.Repeat([]byte{'a'}, 72)
+	hash, err = GenerateFromPassword(long, MinCost)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := CompareHashAndPassword(hash, long); err != nil {
+		t.Error(err.Error())
+	}
+	if _, err := GenerateFromPassword(make([]byte, 73), MinCost); err != ErrPasswordTooLong {
+		t.Errorf("long password: %v", err)
+	}
+	if _, err := GenerateFromPassword(nil, MaxCost+1); err == nil {
+		t.Error("invalid cost accepted")
+	}
+}
+
+func TestInvalidHash(t *testing.T) {
+	hashes := []string{
+		"",
+		"$1$saltsalt$qjXMvbEw8oaL.CzflDtaK/",
+		"$2x$05$abcdefghijklmnopqrstuuWG29KuyeAicPCJODk1zjyGvyQUU2awu",
+		"$2b$5$abcdefghijklmnopqrstuuWG29KuyeAicPCJODk1zjyGvyQUU2awu",
+		"$2b$03$abcdefghijklmnopqrstuuWG29KuyeAicPCJODk1zjyGvyQUU2awu",
+		"$2b$05$abcdefghijklmnopqrstuuWG29KuyeAicPCJODk1zjyGvyQUU2aw",
+		"$2b$05$abcdefghijklmnopqrstu*WG29KuyeAicPCJODk1zjyGvyQUU2awu",
+	}
+
+	for _, h := range hashes {
+		if err := CompareHashAndPassword([]byte(h), nil); err == nil || err == ErrMismatchedHashAndPassword {
+			t.Errorf("%q: %v", h, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package crypt
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+
+	"github.com/loicbacciga/crypto-go/src/password/argon2"
+	"github.com/loicbacciga/crypto-go/src/password/bcrypt"
+)
+
+// Unix crypt(3) compatible password hashes, in the modular crypt format
+// $<id>$<salt>$<hash>.
+// Supported ids are 1 (MD5-crypt), 5 (SHA256-crypt), 6 (SHA512-crypt),
+// 2, 2a, 2b, 2y (bcrypt), argon2d, argon2i, argon2id (PHC format), and
+// 13 characters strings without id are the traditional DES crypt.
+
+// ErrMismatchedHashAndPassword is returned by Verify when the password does
+// not match the hash.
+var ErrMismatchedHashAndPassword = errors.New("crypt: hashed password does not match the given password")
+
+// The alphabet of the crypt base64 encoding
+const itoa64 = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Limits are the largest costs accepted when verifying a hash, which may
+// come from an untrusted source.
+type Limits struct {
+	// Number of rounds of SHA256-crypt and SHA512-crypt
+	MaxRounds int
+	// bcrypt cost
+	MaxBcryptCost int
+	// Parameters of argon2
+	Argon2 argon2.Limits
+}
+
+// DefaultLimits are the limits used by Verify. They accept the rounds and
+// costs used in practice, which take at most about a second.
+var DefaultLimits = Limits{
+	MaxRounds:     1000000,
+	MaxBcryptCost: 16,
+	Argon2:        argon2.DefaultLimits,
+}
+
+// ErrLimitsExceeded is returned by Verify when the costs of the hash exceed
+// the limits.
+var ErrLimitsExceeded = errors.New("crypt: parameters exceed the limits")
+
+// Verify checks that password matches the crypt hash hashString, whose costs
+// do not exceed DefaultLimits.
+// Returns ErrMismatchedHashAndPassword if it does not, or another error if
+// the hash is invalid or its algorithm is not supported.
+func Verify(hashString string, password []byte) error {
+	return VerifyWithLimits(hashString, password, DefaultLimits)
+}
+
+// VerifyWithLimits is like Verify with the given limits on the costs.
+// Returns ErrLimitsExceeded before computing anything if they are exceeded.
+func VerifyWithLimits(hashString string, password []byte, l Limits) error {
+	switch {
+	case strings.HasPrefix(hashString, "$2"):
+		cost, err := bcrypt.Cost([]byte(hashString))
+		if err != nil {
+			return err
+		}
+		if cost > l.MaxBcryptCost {
+			return ErrLimitsExceeded
+		}
+		err = bcrypt.CompareHashAndPassword([]byte(hashString), password)
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return ErrMismatchedHashAndPassword
+		}
+		return err
+	case strings.HasPrefix(hashString, "$argon2"):
+		err := argon2.VerifyWithLimits(hashString, password, l.Argon2)
+		switch err {
+		case argon2.ErrMismatchedHashAndPassword:
+			return ErrMismatchedHashAndPassword
+		case argon2.ErrLimitsExceeded:
+			return ErrLimitsExceeded
+		}
+		return err
+	}
+
+	res, err := crypt(password, hashString, l.MaxRounds)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(res), []byte(hashString)) != 1 {
+		return ErrMismatchedHashAndPassword
+	}
+
+	return nil
+}
+
+// Crypt hashes password with the algorithm and salt of setting, which is
+// either a hash or its prefix up to the salt, e.g. "$6$rounds=10000$salt" or
+// the 2 salt characters of DES crypt.
+// bcrypt and argon2 are not supported, their packages generate these hashes.
+func Crypt(password []byte, setting string) (string, error) {
+	return crypt(password, setting, maxRounds)
+}
+
+// crypt is Crypt, returning ErrLimitsExceeded for more than maxShaRounds
+// rounds of SHA-crypt.
+func crypt(password []byte, setting string, maxShaRounds int) (string, error) {
+	switch {
+	case strings.HasPrefix(setting, md5Prefix):
+		return md5Crypt(password, setting), nil
+	case strings.HasPrefix(setting, sha256Prefix):
+		return shaCrypt(password, setting, sha256Crypt, maxShaRounds)
+	case strings.HasPrefix(setting, sha512Prefix):
+		return shaCrypt(password, setting, sha512Crypt, maxShaRounds)
+	case strings.HasPrefix(setting, "$"):
+		return "", errors.New("crypt: unsupported algorithm")
+	}
+
+	return desCrypt(password, setting)
+}
+
+// b64From24 appends to dst the n characters encoding the 24 bits of
+// b2 || b1 || b0, from the least significant bits.
+func b64From24(dst []byte, b2, b1, b0 byte, n int) []byte {
+	w := uint32(b2)<<16 | uint32(b1)<<8 | uint32(b0)
+	for i := 0; i < n; i++ {
+		dst = append(dst, itoa64[w&0x3f])
+		w >>= 6
+	}
+
+	return dst
+}
+
+// salt returns the salt of s, which ends at the first '$' or at n
+// characters.
+func salt(s string, n int) string {
+	if i := strings.IndexByte(s, '$'); i >= 0 {
+		s = s[:i]
+	}
+	if len(s) > n {
+		s = s[:n]
+	}
+
+	return s
+}
@@ -0,0 +1,116 @@
+package crypt
+
+import (
+	"strings"
+	"testing"
+)
+
+// Vectors generated with libxcrypt, and from "Unix crypt using SHA-256 and
+// SHA-512" for $5$ and $6$
+var vectors = []struct {
+	password string
+	hash     string
+}{
+	// DES
+	{"password", "abJnggxhB/yWI"},
+	{"", "..X8NBuQ4l6uQ"},
+	{"test1234long", "zZska7stIxjcY"},
+	{"U*U*U*U*", "..uHrDQ9FAjfI"},
+	// MD5
+	{"password", "$1$saltsalt$qjXMvbEw8oaL.CzflDtaK/"},
+	{"", "$1$x$fwjfZtMwarkdetsjiQreU1"},
+	{strings.Repeat("a", 40), "$1$01234567$zM//BAdZY/K4F9EY3vFuE/"},
+	// SHA-256
+	{"Hello world!", "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5"},
+	{"This is just a test", "$5$rounds=5000$toolongsaltstrin$Un/5jzAHMgOGZ5.mWJpuVolil07guHPvOW8mGRcvxa5"},
+	// SHA-512
+	{"Hello world!", "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1"},
+	{"", "$6$rounds=1234$abc$ZY9QBxwZvUDfTJXfQ4.weVfufAd1mWOLByh9Q9LUf5.HJGM0Cv4Bsz/gMckLXlhCXVCyMcCpOMTeSAAOX1CyE."},
+	{"the minimum number is still observed", "$6$rounds=1000$roundstoolow$kUMsbe306n21p9R.FRkW3IGn.S9NPN0x50YhH1xhLsPuWGsUSklZt58jaTfF4ZEQpyUNGc0dqbpBYYBaHHrsX."},
+	// bcrypt
+	{"password", "$2b$05$abcdefghijklmnopqrstuuWG29KuyeAicPCJODk1zjyGvyQUU2awu"},
+	// Argon2
+	{"password", "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"},
+}
+
+func TestVerify(t *testing.T) {
+	for _, v := range vectors {
+		if err := Verify(v.hash, []byte(v.password)); err != nil {
+			t.Errorf("%s: %s", v.hash, err.Error())
+		}
+		if err := Verify(v.hash, []byte("x"+v.password)); err != ErrMismatchedHashAndPassword {
+			t.Errorf("%s: wrong password: %v", v.hash, err)
+		}
+	}
+}
+
+func TestCrypt(t *testing.T) {
+	settings := map[string]string{
+		"ab":                         "abJnggxhB/yWI",
+		"$1$saltsalt":                "$1$saltsalt$qjXMvbEw8oaL.CzflDtaK/",
+		"$1$saltsaltsalt$":           "$1$saltsalt$qjXMvbEw8oaL.CzflDtaK/",
+		"$5$rounds=10$roundstoolow":  "$5$rounds=1000$roundstoolow$",
+		"$6$saltstring$ignored hash": "$6$saltstring$",
+	}
+
+	for setting, exp := range settings {
+		res, err := Crypt([]byte("password"), setting)
+		if err != nil {
+			t.Errorf("%s: %s", setting, err.Error())
+		}
+		if !strings.HasPrefix(res, exp) {
+			t.Errorf("Not equal %s!=%s", res, exp)
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	hashes := []string{
+		"",
+		"a",
+		"a*Jnggxh",
+		"$3$abc$def",
+		"$5$rounds=abc$salt$hash",
+		"$2b$05$abcdef",
+		"$argon2id$v=19$m=65536",
+	}
+
+	for _, h := range hashes {
+		if err := Verify(h, nil); err == nil || err == ErrMismatchedHashAndPassword {
+			t.Errorf("%q: %v", h, err)
+		}
+	}
+}
+
+func TestVerifyLimits(t *testing.T) {
+	// 999999999 rounds of SHA-512 would take minutes without the limits
+	huge := []string{
+		"$5$rounds=999999999$salt$ignored",
+		"$6$rounds=999999999$salt$ignored",
+		"$6$rounds=1000001$salt$ignored",
+		"$2b$31$abcdefghijklmnopqrstuuWG29KuyeAicPCJODk1zjyGvyQUU2awu",
+		"$argon2id$v=19$m=4294967295,t=1,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+	}
+	for _, h := range huge {
+		if err := Verify(h, []byte("password")); err != ErrLimitsExceeded {
+			t.Errorf("%q: %v", h, err)
+		}
+	}
+
+	sha := "$6$rounds=1234$abc$ZY9QBxwZvUDfTJXfQ4.weVfufAd1mWOLByh9Q9LUf5.HJGM0Cv4Bsz/gMckLXlhCXVCyMcCpOMTeSAAOX1CyE."
+	l := DefaultLimits
+	l.MaxRounds = 1233
+	if err := VerifyWithLimits(sha, nil, l); err != ErrLimitsExceeded {
+		t.Errorf("%s: %v", sha, err)
+	}
+	l.MaxRounds = 1234
+	if err := VerifyWithLimits(sha, nil, l); err != nil {
+		t.Errorf("%s: %s", sha, err.Error())
+	}
+
+	bc := "$2b$05$abcdefghijklmnopqrstuuWG29KuyeAicPCJODk1zjyGvyQUU2awu"
+	l.MaxBcryptCost = 4
+	if err := VerifyWithLimits(bc, []byte("password"), l); err != ErrLimitsExceeded {
+		t.Errorf("%s: %v", bc, err)
+	}
+}
@@ -0,0 +1,60 @@
+package crypt
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"github.com/loicbacciga/crypto-go/src/cipher/des"
+)
+
+// Traditional DES-based crypt(3), from Version 7 Unix
+
+// desCrypt computes the 13 characters hash <salt><hash>
+// Only the first 8 characters of password are used.
+func desCrypt(password []byte, setting string) (string, error) {
+	if len(setting) < 2 {
+		return "", errors.New("crypt: invalid DES salt")
+	}
+
+	// 12 bits salt
+	var saltBits uint16
+	for i := 0; i < 2; i++ {
+		v := strings.IndexByte(itoa64, setting[i])
+		if v < 0 {
+			return "", errors.New("crypt: invalid DES salt")
+		}
+		saltBits |= uint16(v) << (6 * i)
+	}
+
+	// The key is the 7 bits of each character
+	key := make([]byte, des.BlockSize)
+	for i := 0; i < len(key) && i < len(password); i++ {
+		key[i] = password[i] << 1
+	}
+
+	c, err := des.NewSalted(key, saltBits)
+	if err != nil {
+		return "", err
+	}
+	block := make([]byte, des.BlockSize)
+	for i := 0; i < 25; i++ {
+		c.Encrypt(block, block)
+	}
+
+	// 64 bits output, padded to 66 bits
+	res := []byte(setting[:2])
+	out := binary.BigEndian.Uint64(block)
+	for i := 0; i < 11; i++ {
+		shift := 58 - 6*i
+		var v uint64
+		if shift >= 0 {
+			v = out >> shift
+		} else {
+			v = out << -shift
+		}
+		res = append(res, itoa64[v&0x3f])
+	}
+
+	return string(res), nil
+}
@@ -0,0 +1,72 @@
+package crypt
+
+import (
+	"github.com/loicbacciga/crypto-go/src/hash/md5"
+)
+
+// MD5-crypt, c.f. FreeBSD crypt-md5.c (Poul-Henning Kamp)
+
+const md5Prefix = "$1$"
+
+// md5Crypt computes the MD5-crypt hash $1$<salt>$<hash>
+func md5Crypt(password []byte, setting string) string {
+	s := salt(setting[len(md5Prefix):], 8)
+
+	alt := md5.New()
+	alt.Write(password)
+	alt.Write([]byte(s))
+	alt.Write(password)
+	final := alt.Sum(nil)
+
+	h := md5.New()
+	h.Write(password)
+	h.Write([]byte(md5Prefix))
+	h.Write([]byte(s))
+	for l := len(password); l > 0; l -= md5.Size {
+		if l > md5.Size {
+			h.Write(final)
+		} else {
+			h.Write(final[:l])
+		}
+	}
+	for i := len(password); i > 0; i >>= 1 {
+		if i&1 == 1 {
+			h.Write([]byte{0})
+		} else {
+			h.Write(password[:1])
+		}
+	}
+	final = h.Sum(nil)
+
+	// Slow it down
+	for i := 0; i < 1000; i++ {
+		h := md5.New()
+		if i&1 == 1 {
+			h.Write(password)
+		} else {
+			h.Write(final)
+		}
+		if i%3 != 0 {
+			h.Write([]byte(s))
+		}
+		if i%7 != 0 {
+			h.Write(password)
+		}
+		if i&1 == 1 {
+			h.Write(final)
+		} else {
+			h.Write(password)
+		}
+		final = h.Sum(nil)
+	}
+
+	res := []byte(md5Prefix + s + "$")
+	res = b64From24(res, final[0], final[6], final[12], 4)
+	res = b64From24(res, final[1], final[7], final[13], 4)
+	res = b64From24(res, final[2], final[8], final[14], 4)
+	res = b64From24(res, final[3], final[9], final[15], 4)
+	res = b64From24(res, final[4], final[10], final[5], 4)
+	res = b64From24(res, 0, 0, final[11], 2)
+
+	return string(res)
+}
@@ -0,0 +1,189 @@
+package crypt
+
+import (
+	"errors"
+	"hash"
+	"strconv"
+	"strings"
+
+	"github.com/loicbacciga/crypto-go/src/hash/sha256"
+	"github.com/loicbacciga/crypto-go/src/hash/sha512"
+)
+
+// SHA256-crypt and SHA512-crypt, c.f. "Unix crypt using SHA-256 and SHA-512"
+// (Drepper)
+
+const (
+	sha256Prefix = "$5$"
+	sha512Prefix = "$6$"
+
+	roundsPrefix  = "rounds="
+	defaultRounds = 5000
+	minRounds     = 1000
+	maxRounds     = 999999999
+	maxSaltLen    = 16
+)
+
+type shaCryptAlgo struct {
+	prefix string
+	new    func() hash.Hash
+	// order lists the bytes of the digest encoded by each group of 4
+	// characters, the remaining bytes are encoded by the last group.
+	order [][3]int
+	last  [3]int
+	lastN int
+}
+
+var sha256Crypt = &shaCryptAlgo{
+	prefix: sha256Prefix,
+	new:    sha256.New256,
+	order: [][3]int{
+		{0, 10, 20}, {21, 1, 11}, {12, 22, 2}, {3, 13, 23}, {24, 4, 14},
+		{15, 25, 5}, {6, 16, 26}, {27, 7, 17}, {18, 28, 8}, {9, 19, 29},
+	},
+	last:  [3]int{-1, 31, 30},
+	lastN: 3,
+}
+
+var sha512Crypt = &shaCryptAlgo{
+	prefix: sha512Prefix,
+	new:    sha512.New512,
+	order: [][3]int{
+		{0, 21, 42}, {22, 43, 1}, {44, 2, 23}, {3, 24, 45}, {25, 46, 4},
+		{47, 5, 26}, {6, 27, 48}, {28, 49, 7}, {50, 8, 29}, {9, 30, 51},
+		{31, 52, 10}, {53, 11, 32}, {12, 33, 54}, {34, 55, 13}, {56, 14, 35},
+		{15, 36, 57}, {37, 58, 16}, {59, 17, 38}, {18, 39, 60}, {40, 61, 19},
+		{62, 20, 41},
+	},
+	last:  [3]int{-1, -1, 63},
+	lastN: 2,
+}
+
+// shaCrypt computes the hash $<id>$[rounds=<rounds>$]<salt>$<hash>
+// Returns ErrLimitsExceeded if the setting has more than limit rounds.
+func shaCrypt(password []byte, setting string, algo *shaCryptAlgo, limit int) (string, error) {
+	setting = setting[len(algo.prefix):]
+
+	// Number of rounds, only written back if specified
+	rounds := defaultRounds
+	customRounds := false
+	if strings.HasPrefix(setting, roundsPrefix) {
+		setting = setting[len(roundsPrefix):]
+		i := strings.IndexByte(setting, '$')
+		if i < 0 {
+			return "", errors.New("crypt: invalid rounds")
+		}
+		r, err := strconv.ParseUint(setting[:i], 10, 64)
+		if err != nil {
+			return "", errors.New("crypt: invalid rounds")
+		}
+		setting = setting[i+1:]
+
+		switch {
+		case r < minRounds:
+			rounds = minRounds
+		case r > maxRounds:
+			rounds = maxRounds
+		default:
+			rounds = int(r)
+		}
+		customRounds = true
+	}
+	if rounds > limit {
+		return "", ErrLimitsExceeded
+	}
+
+	s := []byte(salt(setting, maxSaltLen))
+
+	// Digest B
+	h := algo.new()
+	h.Write(password)
+	h.Write(s)
+	h.Write(password)
+	b := h.Sum(nil)
+
+	// Digest A
+	h = algo.new()
+	h.Write(password)
+	h.Write(s)
+	l := len(password)
+	for ; l > len(b); l -= len(b) {
+		h.Write(b)
+	}
+	h.Write(b[:l])
+	for l := len(password); l > 0; l >>= 1 {
+		if l&1 == 1 {
+			h.Write(b)
+		} else {
+			h.Write(password)
+		}
+	}
+	a := h.Sum(nil)
+
+	// Sequence P
+	h = algo.new()
+	for i := 0; i < len(password); i++ {
+		h.Write(password)
+	}
+	p := repeat(h.Sum(nil), len(password))
+
+	// Sequence S
+	h = algo.new()
+	for i := 0; i < 16+int(a[0]); i++ {
+		h.Write(s)
+	}
+	sSeq := repeat(h.Sum(nil), len(s))
+
+	// Rounds
+	c := a
+	for i := 0; i < rounds; i++ {
+		h = algo.new()
+		if i&1 == 1 {
+			h.Write(p)
+		} else {
+			h.Write(c)
+		}
+		if i%3 != 0 {
+			h.Write(sSeq)
+		}
+		if i%7 != 0 {
+			h.Write(p)
+		}
+		if i&1 == 1 {
+			h.Write(c)
+		} else {
+			h.Write(p)
+		}
+		c = h.Sum(nil)
+	}
+
+	res := []byte(algo.prefix)
+	if customRounds {
+		res = append(res, roundsPrefix+strconv.Itoa(rounds)+"$"...)
+	}
+	res = append(res, s...)
+	res = append(res, '$')
+
+	at := func(i int) byte {
+		if i < 0 {
+			return 0
+		}
+		return c[i]
+	}
+	for _, o := range algo.order {
+		res = b64From24(res, c[o[0]], c[o[1]], c[o[2]], 4)
+	}
+	res = b64From24(res, at(algo.last[0]), at(algo.last[1]), at(algo.last[2]), algo.lastN)
+
+	return string(res), nil
+}
+
+// repeat returns the first n bytes of the repetition of b
+func repeat(b []byte, n int) []byte {
+	res := make([]byte, n)
+	for i := 0; i < n; i += len(b) {
+		copy(res[i:], b)
+	}
+
+	return res
+}
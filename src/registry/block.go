@@ -0,0 +1,274 @@
+package registry
+
+import (
+	"crypto/cipher"
+	"fmt"
+	"strconv"
+
+	"github.com/loicbacciga/crypto-go/src/cipher/aria"
+	"github.com/loicbacciga/crypto-go/src/cipher/blowfish"
+	"github.com/loicbacciga/crypto-go/src/cipher/camellia"
+	"github.com/loicbacciga/crypto-go/src/cipher/cast5"
+	"github.com/loicbacciga/crypto-go/src/cipher/des"
+	"github.com/loicbacciga/crypto-go/src/cipher/idea"
+	"github.com/loicbacciga/crypto-go/src/cipher/kuznyechik"
+	"github.com/loicbacciga/crypto-go/src/cipher/magma"
+	"github.com/loicbacciga/crypto-go/src/cipher/present"
+	"github.com/loicbacciga/crypto-go/src/cipher/rc2"
+	"github.com/loicbacciga/crypto-go/src/cipher/rc5"
+	"github.com/loicbacciga/crypto-go/src/cipher/serpent"
+	"github.com/loicbacciga/crypto-go/src/cipher/simon"
+	"github.com/loicbacciga/crypto-go/src/cipher/sm4"
+	"github.com/loicbacciga/crypto-go/src/cipher/speck"
+	"github.com/loicbacciga/crypto-go/src/cipher/tea"
+	"github.com/loicbacciga/crypto-go/src/cipher/twofish"
+)
+
+// BlockCipher identifies a block cipher implemented in this library.
+type BlockCipher uint
+
+const (
+	DES BlockCipher = 1 + iota
+	TripleDES
+	DESX
+	Blowfish
+	Twofish
+	Serpent
+	Camellia
+	SM4
+	ARIA
+	Kuznyechik
+	Magma
+	IDEA
+	CAST5
+	RC2
+	RC5
+	RC6
+	Speck
+	Simon
+	PRESENT
+	TEA
+	XTEA
+	maxBlockCipher
+)
+
+type blockCipherInfo struct {
+	name      string
+	aliases   []string
+	keySizes  []int
+	blockSize int
+	new       func(key []byte) (cipher.Block, error)
+}
+
+var blockCiphers = [maxBlockCipher]blockCipherInfo{
+	DES: {
+		name:      "DES",
+		keySizes:  []int{8},
+		blockSize: des.BlockSize,
+		new:       des.New,
+	},
+	TripleDES: {
+		name:      "3DES",
+		aliases:   []string{"TDES", "TripleDES", "DES-EDE3"},
+		keySizes:  []int{16, 24},
+		blockSize: des.BlockSize,
+		new:       des.NewTriple,
+	},
+	DESX: {
+		name:      "DESX",
+		aliases:   []string{"DES-X"},
+		keySizes:  []int{des.DESXKeySize},
+		blockSize: des.BlockSize,
+		new:       des.NewDESX,
+	},
+	Blowfish: {
+		name:      "Blowfish",
+		aliases:   []string{"BF"},
+		keySizes:  keySizeRange(blowfish.MinKeySize, blowfish.MaxKeySize),
+		blockSize: blowfish.BlockSize,
+		new:       blowfish.New,
+	},
+	Twofish: {
+		name:      "Twofish",
+		keySizes:  []int{16, 24, 32},
+		blockSize: twofish.BlockSize,
+		new:       twofish.New,
+	},
+	Serpent: {
+		name:      "Serpent",
+		keySizes:  keySizeRange(serpent.MinKeySize, serpent.MaxKeySize),
+		blockSize: serpent.BlockSize,
+		new:       serpent.New,
+	},
+	Camellia: {
+		name:      "Camellia",
+		keySizes:  []int{16, 24, 32},
+		blockSize: camellia.BlockSize,
+		new:       camellia.New,
+	},
+	SM4: {
+		name:      "SM4",
+		keySizes:  []int{sm4.KeySize},
+		blockSize: sm4.BlockSize,
+		new:       sm4.New,
+	},
+	ARIA: {
+		name:      "ARIA",
+		keySizes:  []int{16, 24, 32},
+		blockSize: aria.BlockSize,
+		new:       aria.New,
+	},
+	Kuznyechik: {
+		name:      "Kuznyechik",
+		aliases:   []string{"GOST3412-2015-128", "Grasshopper"},
+		keySizes:  []int{kuznyechik.KeySize},
+		blockSize: kuznyechik.BlockSize,
+		new:       kuznyechik.New,
+	},
+	Magma: {
+		name:      "Magma",
+		aliases:   []string{"GOST3412-2015-64"},
+		keySizes:  []int{magma.KeySize},
+		blockSize: magma.BlockSize,
+		new:       magma.New,
+	},
+	IDEA: {
+		name:      "IDEA",
+		keySizes:  []int{idea.KeySize},
+		blockSize: idea.BlockSize,
+		new:       idea.New,
+	},
+	CAST5: {
+		name:      "CAST5",
+		aliases:   []string{"CAST-128", "CAST"},
+		keySizes:  keySizeRange(cast5.MinKeySize, cast5.MaxKeySize),
+		blockSize: cast5.BlockSize,
+		new:       cast5.New,
+	},
+	RC2: {
+		name:      "RC2",
+		keySizes:  keySizeRange(rc2.MinKeySize, rc2.MaxKeySize),
+		blockSize: rc2.BlockSize,
+		new:       rc2.New,
+	},
+	RC5: {
+		name:      "RC5",
+		aliases:   []string{"RC5-32/12"},
+		keySizes:  keySizeRange(0, rc5.MaxKeySize),
+		blockSize: rc5.DefaultWordSize / 4,
+		new:       rc5.New,
+	},
+	RC6: {
+		name:      "RC6",
+		keySizes:  keySizeRange(0, rc5.MaxKeySize),
+		blockSize: rc5.RC6BlockSize,
+		new:       rc5.NewRC6,
+	},
+	Speck: {
+		name:      "Speck",
+		aliases:   []string{"Speck128"},
+		keySizes:  []int{16, 24, 32},
+		blockSize: 16,
+		new:       speck.New,
+	},
+	Simon: {
+		name:      "Simon",
+		aliases:   []string{"Simon128"},
+		keySizes:  []int{16, 24, 32},
+		blockSize: 16,
+		new:       simon.New,
+	},
+	PRESENT: {
+		name:      "PRESENT",
+		keySizes:  []int{10, 16},
+		blockSize: present.BlockSize,
+		new:       present.New,
+	},
+	TEA: {
+		name:      "TEA",
+		keySizes:  []int{tea.KeySize},
+		blockSize: tea.BlockSize,
+		new:       tea.New,
+	},
+	XTEA: {
+		name:      "XTEA",
+		keySizes:  []int{tea.KeySize},
+		blockSize: tea.BlockSize,
+		new:       tea.NewXTEA,
+	},
+}
+
+// keySizeRange returns all the key sizes between from and to bytes
+func keySizeRange(from, to int) []int {
+	res := make([]int, 0, to-from+1)
+	for size := from; size <= to; size++ {
+		res = append(res, size)
+	}
+	return res
+}
+
+// Available reports whether the given block cipher is implemented.
+func (c BlockCipher) Available() bool {
+	return c > 0 && c < maxBlockCipher && blockCiphers[c].new != nil
+}
+
+// New returns a new cipher.Block using the given key.
+// Returns an error if the cipher is not available or the key size is not
+// supported.
+func (c BlockCipher) New(key []byte) (cipher.Block, error) {
+	if !c.Available() {
+		return nil, fmt.Errorf("registry: block cipher #%d is unavailable", c)
+	}
+
+	for _, size := range blockCiphers[c].keySizes {
+		if len(key) == size {
+			return blockCiphers[c].new(key)
+		}
+	}
+
+	return nil, fmt.Errorf("registry: invalid key size %d for %s", len(key), c)
+}
+
+// KeySizes returns the supported key sizes, in bytes, of the block cipher.
+func (c BlockCipher) KeySizes() []int {
+	if !c.Available() {
+		return nil
+	}
+	return append([]int(nil), blockCiphers[c].keySizes...)
+}
+
+// BlockSize returns the block size, in bytes, of the block cipher.
+func (c BlockCipher) BlockSize() int {
+	if !c.Available() {
+		panic("registry: BlockSize of unknown block cipher")
+	}
+	return blockCiphers[c].blockSize
+}
+
+func (c BlockCipher) String() string {
+	if !c.Available() {
+		return "unknown block cipher value " + strconv.Itoa(int(c))
+	}
+	return blockCiphers[c].name
+}
+
+// BlockCiphers returns all the available block ciphers.
+func BlockCiphers() []BlockCipher {
+	res := make([]BlockCipher, 0, maxBlockCipher)
+	for c := BlockCipher(1); c < maxBlockCipher; c++ {
+		if c.Available() {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// BlockCipherByName returns the block cipher with the given name, e.g. "3DES".
+func BlockCipherByName(name string) (BlockCipher, error) {
+	for _, c := range BlockCiphers() {
+		if matches(name, blockCiphers[c].name, blockCiphers[c].aliases) {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("registry: unknown block cipher %q", name)
+}
@@ -0,0 +1,222 @@
+package registry
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"hash"
+	"strconv"
+
+	"github.com/loicbacciga/crypto-go/src/hash/blake2b"
+	"github.com/loicbacciga/crypto-go/src/hash/md2"
+	"github.com/loicbacciga/crypto-go/src/hash/md5"
+	"github.com/loicbacciga/crypto-go/src/hash/sha1"
+	"github.com/loicbacciga/crypto-go/src/hash/sha256"
+	"github.com/loicbacciga/crypto-go/src/hash/sha512"
+)
+
+// Hash identifies a hash function implemented in this library.
+type Hash uint
+
+const (
+	MD2 Hash = 1 + iota
+	SHA1
+	SHA224
+	SHA256
+	SHA384
+	SHA512
+	SHA512_224
+	SHA512_256
+	BLAKE2b_256
+	BLAKE2b_384
+	BLAKE2b_512
+	MD5
+	maxHash
+)
+
+type hashInfo struct {
+	name      string
+	aliases   []string
+	oid       asn1.ObjectIdentifier
+	size      int
+	blockSize int
+	new       func() hash.Hash
+}
+
+var hashes = [maxHash]hashInfo{
+	MD2: {
+		name:      "MD2",
+		oid:       asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 2},
+		size:      md2.Size,
+		blockSize: md2.BlockSize,
+		new:       md2.New,
+	},
+	SHA1: {
+		name:      "SHA-1",
+		oid:       asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26},
+		size:      sha1.Size,
+		blockSize: sha1.BlockSize,
+		new:       sha1.New,
+	},
+	SHA224: {
+		name:      "SHA-224",
+		oid:       asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 4},
+		size:      sha256.Size224,
+		blockSize: sha256.BlockSize,
+		new:       sha256.New224,
+	},
+	SHA256: {
+		name:      "SHA-256",
+		oid:       asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1},
+		size:      sha256.Size256,
+		blockSize: sha256.BlockSize,
+		new:       sha256.New256,
+	},
+	SHA384: {
+		name:      "SHA-384",
+		oid:       asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2},
+		size:      sha512.Size384,
+		blockSize: sha512.BlockSize,
+		new:       sha512.New384,
+	},
+	SHA512: {
+		name:      "SHA-512",
+		oid:       asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3},
+		size:      sha512.Size512,
+		blockSize: sha512.BlockSize,
+		new:       sha512.New512,
+	},
+	SHA512_224: {
+		name:      "SHA-512/224",
+		aliases:   []string{"SHA512_224"},
+		oid:       asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 5},
+		size:      sha512.Size512_224,
+		blockSize: sha512.BlockSize,
+		new:       sha512.New512_224,
+	},
+	SHA512_256: {
+		name:      "SHA-512/256",
+		aliases:   []string{"SHA512_256"},
+		oid:       asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 6},
+		size:      sha512.Size512_256,
+		blockSize: sha512.BlockSize,
+		new:       sha512.New512_256,
+	},
+	BLAKE2b_256: {
+		name:      "BLAKE2b-256",
+		oid:       asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 1722, 12, 2, 1, 8},
+		size:      blake2b.Size256,
+		blockSize: blake2b.BlockSize,
+		new:       unkeyed(blake2b.New256),
+	},
+	BLAKE2b_384: {
+		name:      "BLAKE2b-384",
+		oid:       asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 1722, 12, 2, 1, 12},
+		size:      blake2b.Size384,
+		blockSize: blake2b.BlockSize,
+		new:       unkeyed(blake2b.New384),
+	},
+	BLAKE2b_512: {
+		name:      "BLAKE2b-512",
+		oid:       asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 1722, 12, 2, 1, 16},
+		size:      blake2b.Size,
+		blockSize: blake2b.BlockSize,
+		new:       unkeyed(blake2b.New512),
+	},
+	MD5: {
+		name:      "MD5",
+		oid:       asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 5},
+		size:      md5.Size,
+		blockSize: md5.BlockSize,
+		new:       md5.New,
+	},
+}
+
+// unkeyed turns the constructor of a keyed hash into a plain hash
+// constructor, without key.
+func unkeyed(f func(key []byte) (hash.Hash, error)) func() hash.Hash {
+	return func() hash.Hash {
+		h, err := f(nil)
+		if err != nil {
+			panic(err)
+		}
+		return h
+	}
+}
+
+// Available reports whether the given hash function is implemented.
+func (h Hash) Available() bool {
+	return h > 0 && h < maxHash && hashes[h].new != nil
+}
+
+// New returns a new hash.Hash calculating the given hash function.
+// New panics if the hash function is not available.
+func (h Hash) New() hash.Hash {
+	if !h.Available() {
+		panic("registry: requested hash function #" + strconv.Itoa(int(h)) + " is unavailable")
+	}
+	return hashes[h].new()
+}
+
+// Size returns the length, in bytes, of a digest resulting from the given
+// hash function.
+func (h Hash) Size() int {
+	if !h.Available() {
+		panic("registry: Size of unknown hash function")
+	}
+	return hashes[h].size
+}
+
+// BlockSize returns the block size, in bytes, of the given hash function.
+func (h Hash) BlockSize() int {
+	if !h.Available() {
+		panic("registry: BlockSize of unknown hash function")
+	}
+	return hashes[h].blockSize
+}
+
+// OID returns the ASN.1 object identifier of the hash function, or nil if
+// it has none.
+func (h Hash) OID() asn1.ObjectIdentifier {
+	if !h.Available() {
+		return nil
+	}
+	return hashes[h].oid
+}
+
+func (h Hash) String() string {
+	if !h.Available() {
+		return "unknown hash value " + strconv.Itoa(int(h))
+	}
+	return hashes[h].name
+}
+
+// Hashes returns all the available hash functions.
+func Hashes() []Hash {
+	res := make([]Hash, 0, maxHash)
+	for h := Hash(1); h < maxHash; h++ {
+		if h.Available() {
+			res = append(res, h)
+		}
+	}
+	return res
+}
+
+// HashByName returns the hash function with the given name, e.g. "SHA-256".
+func HashByName(name string) (Hash, error) {
+	for _, h := range Hashes() {
+		if matches(name, hashes[h].name, hashes[h].aliases) {
+			return h, nil
+		}
+	}
+	return 0, fmt.Errorf("registry: unknown hash %q", name)
+}
+
+// HashByOID returns the hash function with the given ASN.1 object identifier.
+func HashByOID(oid asn1.ObjectIdentifier) (Hash, error) {
+	for _, h := range Hashes() {
+		if hashes[h].oid != nil && hashes[h].oid.Equal(oid) {
+			return h, nil
+		}
+	}
+	return 0, fmt.Errorf("registry: unknown hash OID %s", oid)
+}
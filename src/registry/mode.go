@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"crypto/cipher"
+	"fmt"
+	"strconv"
+
+	"github.com/loicbacciga/crypto-go/src/cipher/modes/cbc"
+	"github.com/loicbacciga/crypto-go/src/cipher/modes/ecb"
+)
+
+// Mode identifies a block cipher mode of operation implemented in this
+// library.
+type Mode uint
+
+const (
+	ECB Mode = 1 + iota
+	CBC
+	maxMode
+)
+
+type modeInfo struct {
+	name         string
+	aliases      []string
+	needsIV      bool
+	newEncrypter func(b cipher.Block, iv []byte) (cipher.BlockMode, error)
+	newDecrypter func(b cipher.Block, iv []byte) (cipher.BlockMode, error)
+}
+
+var modes = [maxMode]modeInfo{
+	ECB: {
+		name:    "ECB",
+		needsIV: false,
+		newEncrypter: func(b cipher.Block, iv []byte) (cipher.BlockMode, error) {
+			return ecb.NewEncrypter(b), nil
+		},
+		newDecrypter: func(b cipher.Block, iv []byte) (cipher.BlockMode, error) {
+			return ecb.NewDecrypter(b), nil
+		},
+	},
+	CBC: {
+		name:         "CBC",
+		needsIV:      true,
+		newEncrypter: cbc.NewEncrypter,
+		newDecrypter: cbc.NewDecrypter,
+	},
+}
+
+// Available reports whether the given mode is implemented.
+func (m Mode) Available() bool {
+	return m > 0 && m < maxMode && modes[m].newEncrypter != nil
+}
+
+// NeedsIV reports whether the mode takes an IV.
+// The IV is ignored by modes which do not need one.
+func (m Mode) NeedsIV() bool {
+	if !m.Available() {
+		panic("registry: NeedsIV of unknown mode")
+	}
+	return modes[m].needsIV
+}
+
+// NewEncrypter returns a BlockMode which encrypts with b in the given mode.
+func (m Mode) NewEncrypter(b cipher.Block, iv []byte) (cipher.BlockMode, error) {
+	if !m.Available() {
+		return nil, fmt.Errorf("registry: mode #%d is unavailable", m)
+	}
+	return modes[m].newEncrypter(b, iv)
+}
+
+// NewDecrypter returns a BlockMode which decrypts with b in the given mode.
+func (m Mode) NewDecrypter(b cipher.Block, iv []byte) (cipher.BlockMode, error) {
+	if !m.Available() {
+		return nil, fmt.Errorf("registry: mode #%d is unavailable", m)
+	}
+	return modes[m].newDecrypter(b, iv)
+}
+
+func (m Mode) String() string {
+	if !m.Available() {
+		return "unknown mode value " + strconv.Itoa(int(m))
+	}
+	return modes[m].name
+}
+
+// Modes returns all the available modes of operation.
+func Modes() []Mode {
+	res := make([]Mode, 0, maxMode)
+	for m := Mode(1); m < maxMode; m++ {
+		if m.Available() {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+// ModeByName returns the mode of operation with the given name, e.g. "CBC".
+func ModeByName(name string) (Mode, error) {
+	for _, m := range Modes() {
+		if matches(name, modes[m].name, modes[m].aliases) {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("registry: unknown mode %q", name)
+}
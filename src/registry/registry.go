@@ -0,0 +1,42 @@
+// Package registry maps algorithm identifiers and names to the
+// constructors of the library, so that algorithms can be selected by name
+// (e.g. from a configuration file) and enumerated.
+//
+// Hashes, block ciphers, stream ciphers and modes of operation each have
+// their own identifier type, in the spirit of crypto.Hash.
+package registry
+
+import (
+	"strings"
+)
+
+// normalize puts a name in the canonical form used for lookups.
+// Lookups are case insensitive and ignore '-', '_' and spaces, so that
+// "SHA-256", "sha256" and "Sha_256" are the same name.
+func normalize(name string) string {
+	name = strings.ToUpper(name)
+
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == ' ' {
+			return -1
+		}
+		return r
+	}, name)
+}
+
+// matches tells if name is the given canonical name or one of its aliases
+func matches(name, canonical string, aliases []string) bool {
+	name = normalize(name)
+
+	if name == normalize(canonical) {
+		return true
+	}
+
+	for _, alias := range aliases {
+		if name == normalize(alias) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,212 @@
+package registry
+
+import (
+	"bytes"
+	godes "crypto/des"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/hex"
+	"testing"
+)
+
+func TestHashByName(t *testing.T) {
+	names := map[string]Hash{
+		"MD2":         MD2,
+		"md5":         MD5,
+		"sha1":        SHA1,
+		"SHA-256":     SHA256,
+		"sha_384":     SHA384,
+		"SHA-512/256": SHA512_256,
+		"SHA512_224":  SHA512_224,
+	}
+
+	for name, exp := range names {
+		h, err := HashByName(name)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if h != exp {
+			t.Errorf("%s: %s != %s", name, h, exp)
+		}
+	}
+
+	if _, err := HashByName("SHA-3"); err == nil {
+		t.Error("unknown hash found")
+	}
+}
+
+func TestHashByOID(t *testing.T) {
+	oid := asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+
+	h, err := HashByOID(oid)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if h != SHA256 {
+		t.Errorf("%s != %s", h, SHA256)
+	}
+
+	if _, err := HashByOID(asn1.ObjectIdentifier{1, 2, 3}); err == nil {
+		t.Error("unknown OID found")
+	}
+}
+
+func TestHashes(t *testing.T) {
+	for _, h := range Hashes() {
+		d := h.New()
+
+		if d.Size() != h.Size() {
+			t.Errorf("%s: size %d != %d", h, d.Size(), h.Size())
+		}
+		if d.BlockSize() != h.BlockSize() {
+			t.Errorf("%s: block size %d != %d", h, d.BlockSize(), h.BlockSize())
+		}
+
+		// Names round trip
+		h2, err := HashByName(h.String())
+		if err != nil || h2 != h {
+			t.Errorf("%s: name lookup failed", h)
+		}
+	}
+
+	msg := []byte("abc")
+	exp := sha256.Sum256(msg)
+	d := SHA256.New()
+	d.Write(msg)
+
+	if res := d.Sum(nil); !bytes.Equal(res, exp[:]) {
+		t.Errorf("Not equal %s!=%s", hex.EncodeToString(res), hex.EncodeToString(exp[:]))
+	}
+}
+
+func TestUnavailableHash(t *testing.T) {
+	if Hash(0).Available() || maxHash.Available() {
+		t.Error("invalid hash available")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("New of invalid hash did not panic")
+		}
+	}()
+	maxHash.New()
+}
+
+func TestBlockCipher(t *testing.T) {
+	c, err := BlockCipherByName("des-ede3")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if c != TripleDES {
+		t.Fatalf("%s != %s", c, TripleDES)
+	}
+
+	key := make([]byte, 24)
+	for i := range key {
+		key[i] = byte(i)
+	}
+
+	b, err := c.New(key)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	goB, _ := godes.NewTripleDESCipher(key)
+
+	src := []byte("8 bytes!")
+	dst := make([]byte, c.BlockSize())
+	goDst := make([]byte, c.BlockSize())
+	b.Encrypt(dst, src)
+	goB.Encrypt(goDst, src)
+
+	if !bytes.Equal(dst, goDst) {
+		t.Errorf("Not equal %s!=%s", hex.EncodeToString(dst), hex.EncodeToString(goDst))
+	}
+
+	if _, err := DES.New(key); err == nil {
+		t.Error("wrong key size accepted")
+	}
+
+	// Two keys 3DES
+	if _, err := c.New(key[:16]); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestBlockCiphers(t *testing.T) {
+	for _, c := range BlockCiphers() {
+		sizes := c.KeySizes()
+		key := make([]byte, sizes[len(sizes)-1])
+		for i := range key {
+			key[i] = byte(i)
+		}
+
+		b, err := c.New(key)
+		if err != nil {
+			t.Fatalf("%s: %s", c, err.Error())
+		}
+		if b.BlockSize() != c.BlockSize() {
+			t.Errorf("%s: block size %d != %d", c, b.BlockSize(), c.BlockSize())
+		}
+
+		// Names round trip
+		c2, err := BlockCipherByName(c.String())
+		if err != nil || c2 != c {
+			t.Errorf("%s: name lookup failed", c)
+		}
+	}
+}
+
+func TestStreamCipher(t *testing.T) {
+	c, err := StreamCipherByName("chacha20")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = c.New(make([]byte, c.KeySize()), make([]byte, c.NonceSize()))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, err := c.New(make([]byte, 16), make([]byte, c.NonceSize())); err == nil {
+		t.Error("wrong key size accepted")
+	}
+}
+
+func TestModes(t *testing.T) {
+	b, err := DES.New(make([]byte, 8))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	iv := make([]byte, b.BlockSize())
+	ptxt := []byte("sixteen bytes!!!")
+
+	for _, m := range Modes() {
+		m2, err := ModeByName(m.String())
+		if err != nil || m2 != m {
+			t.Errorf("%s: name lookup failed", m)
+		}
+
+		enc, err := m.NewEncrypter(b, iv)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		dec, err := m.NewDecrypter(b, iv)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		ctxt := make([]byte, len(ptxt))
+		enc.CryptBlocks(ctxt, ptxt)
+		res := make([]byte, len(ctxt))
+		dec.CryptBlocks(res, ctxt)
+
+		if !bytes.Equal(res, ptxt) {
+			t.Errorf("%s: %s != %s", m, hex.EncodeToString(res), hex.EncodeToString(ptxt))
+		}
+	}
+
+	if _, err := CBC.NewEncrypter(b, nil); err == nil {
+		t.Error("missing IV accepted")
+	}
+}
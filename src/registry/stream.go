@@ -0,0 +1,94 @@
+package registry
+
+import (
+	"crypto/cipher"
+	"fmt"
+	"strconv"
+
+	"github.com/loicbacciga/crypto-go/src/cipher/chacha20"
+)
+
+// StreamCipher identifies a stream cipher implemented in this library.
+type StreamCipher uint
+
+const (
+	ChaCha20 StreamCipher = 1 + iota
+	maxStreamCipher
+)
+
+type streamCipherInfo struct {
+	name      string
+	aliases   []string
+	keySize   int
+	nonceSize int
+	new       func(key, nonce []byte) (cipher.Stream, error)
+}
+
+var streamCiphers = [maxStreamCipher]streamCipherInfo{
+	ChaCha20: {
+		name:      "ChaCha20",
+		keySize:   256 / 8,
+		nonceSize: 96 / 8,
+		new:       chacha20.New,
+	},
+}
+
+// Available reports whether the given stream cipher is implemented.
+func (c StreamCipher) Available() bool {
+	return c > 0 && c < maxStreamCipher && streamCiphers[c].new != nil
+}
+
+// New returns a new cipher.Stream using the given key and nonce.
+// Returns an error if the cipher is not available or the key or nonce
+// sizes are wrong.
+func (c StreamCipher) New(key, nonce []byte) (cipher.Stream, error) {
+	if !c.Available() {
+		return nil, fmt.Errorf("registry: stream cipher #%d is unavailable", c)
+	}
+	return streamCiphers[c].new(key, nonce)
+}
+
+// KeySize returns the key size, in bytes, of the stream cipher.
+func (c StreamCipher) KeySize() int {
+	if !c.Available() {
+		panic("registry: KeySize of unknown stream cipher")
+	}
+	return streamCiphers[c].keySize
+}
+
+// NonceSize returns the nonce size, in bytes, of the stream cipher.
+func (c StreamCipher) NonceSize() int {
+	if !c.Available() {
+		panic("registry: NonceSize of unknown stream cipher")
+	}
+	return streamCiphers[c].nonceSize
+}
+
+func (c StreamCipher) String() string {
+	if !c.Available() {
+		return "unknown stream cipher value " + strconv.Itoa(int(c))
+	}
+	return streamCiphers[c].name
+}
+
+// StreamCiphers returns all the available stream ciphers.
+func StreamCiphers() []StreamCipher {
+	res := make([]StreamCipher, 0, maxStreamCipher)
+	for c := StreamCipher(1); c < maxStreamCipher; c++ {
+		if c.Available() {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// StreamCipherByName returns the stream cipher with the given name, e.g.
+// "ChaCha20".
+func StreamCipherByName(name string) (StreamCipher, error) {
+	for _, c := range StreamCiphers() {
+		if matches(name, streamCiphers[c].name, streamCiphers[c].aliases) {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("registry: unknown stream cipher %q", name)
+}
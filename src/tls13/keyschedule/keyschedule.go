@@ -0,0 +1,296 @@
+// Package keyschedule implements the TLS 1.3 key schedule as defined in
+// RFC 8446 section 7 (https://datatracker.ietf.org/doc/html/rfc8446#section-7).
+//
+// The secrets are derived in order:
+//
+//	es := NewEarlySecret(sha256.New256, psk)
+//	hs := es.HandshakeSecret(sharedSecret)
+//	ms := hs.MasterSecret()
+//
+// Each stage exposes the traffic secrets which can be derived from it.
+package keyschedule
+
+import (
+	"encoding/binary"
+	"hash"
+	"io"
+	"log"
+
+	"github.com/loicbacciga/crypto-go/src/kdf/hkdf"
+	"github.com/loicbacciga/crypto-go/src/mac/hmac"
+)
+
+// Labels of RFC 8446 7.1 and 7.5
+const (
+	labelDerived                = "derived"
+	labelExternalBinder         = "ext binder"
+	labelResumptionBinder       = "res binder"
+	labelClientEarlyTraffic     = "c e traffic"
+	labelEarlyExporter          = "e exp master"
+	labelClientHandshakeTraffic = "c hs traffic"
+	labelServerHandshakeTraffic = "s hs traffic"
+	labelClientAppTraffic       = "c ap traffic"
+	labelServerAppTraffic       = "s ap traffic"
+	labelExporter               = "exp master"
+	labelResumption             = "res master"
+	labelKey                    = "key"
+	labelIV                     = "iv"
+	labelFinished               = "finished"
+	labelTrafficUpdate          = "traffic upd"
+	labelResumptionPSK          = "resumption"
+	labelExporterValue          = "exporter"
+)
+
+// ExpandLabel implements HKDF-Expand-Label.
+// It panics if label or context are too long to be encoded, or if length
+// is larger than what HKDF can produce.
+// c.f. RFC8446 7.1
+func ExpandLabel(h func() hash.Hash, secret []byte, label string, context []byte, length int) []byte {
+	fullLabel := "tls13 " + label
+	if len(fullLabel) > 255 || len(context) > 255 || length > 0xffff {
+		log.Panic("tls13: label, context or length too long")
+	}
+
+	// struct {
+	//     uint16 length = Length;
+	//     opaque label<7..255> = "tls13 " + Label;
+	//     opaque context<0..255> = Context;
+	// } HkdfLabel;
+	hkdfLabel := make([]byte, 0, 2+1+len(fullLabel)+1+len(context))
+	hkdfLabel = binary.BigEndian.AppendUint16(hkdfLabel, uint16(length))
+	hkdfLabel = append(hkdfLabel, byte(len(fullLabel)))
+	hkdfLabel = append(hkdfLabel, fullLabel...)
+	hkdfLabel = append(hkdfLabel, byte(len(context)))
+	hkdfLabel = append(hkdfLabel, context...)
+
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.Expand(h, secret, hkdfLabel), out); err != nil {
+		log.Panic("tls13: " + err.Error())
+	}
+
+	return out
+}
+
+// DeriveSecret implements Derive-Secret.
+// transcript is the running hash of the handshake messages, nil stands for
+// the empty transcript.
+// c.f. RFC8446 7.1
+func DeriveSecret(h func() hash.Hash, secret []byte, label string, transcript hash.Hash) []byte {
+	if transcript == nil {
+		transcript = h()
+	}
+
+	return ExpandLabel(h, secret, label, transcript.Sum(nil), transcript.Size())
+}
+
+// EarlySecret is the first secret of the key schedule.
+type EarlySecret struct {
+	h      func() hash.Hash
+	secret []byte
+}
+
+// NewEarlySecret derives the early secret from a pre-shared key.
+// If psk is nil, a string of HashLen zeros is used, as done when no PSK is
+// negotiated.
+func NewEarlySecret(h func() hash.Hash, psk []byte) *EarlySecret {
+	zeros := make([]byte, h().Size())
+	if psk == nil {
+		psk = zeros
+	}
+
+	return &EarlySecret{
+		h:      h,
+		secret: hkdf.Extract(h, psk, zeros),
+	}
+}
+
+// Secret returns the early secret itself.
+func (s *EarlySecret) Secret() []byte {
+	return s.secret
+}
+
+// ExternalBinderKey returns the binder key for external PSKs.
+func (s *EarlySecret) ExternalBinderKey() []byte {
+	return DeriveSecret(s.h, s.secret, labelExternalBinder, nil)
+}
+
+// ResumptionBinderKey returns the binder key for resumption PSKs.
+func (s *EarlySecret) ResumptionBinderKey() []byte {
+	return DeriveSecret(s.h, s.secret, labelResumptionBinder, nil)
+}
+
+// ClientEarlyTrafficSecret derives the 0-RTT traffic secret.
+// transcript covers the ClientHello.
+func (s *EarlySecret) ClientEarlyTrafficSecret(transcript hash.Hash) []byte {
+	return DeriveSecret(s.h, s.secret, labelClientEarlyTraffic, transcript)
+}
+
+// EarlyExporterMasterSecret derives the early exporter master secret.
+// transcript covers the ClientHello.
+func (s *EarlySecret) EarlyExporterMasterSecret(transcript hash.Hash) *ExporterMasterSecret {
+	return &ExporterMasterSecret{
+		h:      s.h,
+		secret: DeriveSecret(s.h, s.secret, labelEarlyExporter, transcript),
+	}
+}
+
+// HandshakeSecret derives the handshake secret from the (EC)DHE shared
+// secret.
+// If sharedSecret is nil (PSK-only handshake), a string of HashLen zeros is
+// used.
+func (s *EarlySecret) HandshakeSecret(sharedSecret []byte) *HandshakeSecret {
+	if sharedSecret == nil {
+		sharedSecret = make([]byte, s.h().Size())
+	}
+
+	derived := DeriveSecret(s.h, s.secret, labelDerived, nil)
+
+	return &HandshakeSecret{
+		h:      s.h,
+		secret: hkdf.Extract(s.h, sharedSecret, derived),
+	}
+}
+
+// HandshakeSecret is the second secret of the key schedule.
+type HandshakeSecret struct {
+	h      func() hash.Hash
+	secret []byte
+}
+
+// Secret returns the handshake secret itself.
+func (s *HandshakeSecret) Secret() []byte {
+	return s.secret
+}
+
+// ClientHandshakeTrafficSecret derives the client handshake traffic secret.
+// transcript covers ClientHello...ServerHello.
+func (s *HandshakeSecret) ClientHandshakeTrafficSecret(transcript hash.Hash) []byte {
+	return DeriveSecret(s.h, s.secret, labelClientHandshakeTraffic, transcript)
+}
+
+// ServerHandshakeTrafficSecret derives the server handshake traffic secret.
+// transcript covers ClientHello...ServerHello.
+func (s *HandshakeSecret) ServerHandshakeTrafficSecret(transcript hash.Hash) []byte {
+	return DeriveSecret(s.h, s.secret, labelServerHandshakeTraffic, transcript)
+}
+
+// MasterSecret derives the master secret.
+func (s *HandshakeSecret) MasterSecret() *MasterSecret {
+	zeros := make([]byte, s.h().Size())
+	derived := DeriveSecret(s.h, s.secret, labelDerived, nil)
+
+	return &MasterSecret{
+		h:      s.h,
+		secret: hkdf.Extract(s.h, zeros, derived),
+	}
+}
+
+// MasterSecret is the last secret of the key schedule.
+type MasterSecret struct {
+	h      func() hash.Hash
+	secret []byte
+}
+
+// Secret returns the master secret itself.
+func (s *MasterSecret) Secret() []byte {
+	return s.secret
+}
+
+// ClientApplicationTrafficSecret derives the first client application
+// traffic secret.
+// transcript covers ClientHello...server Finished.
+func (s *MasterSecret) ClientApplicationTrafficSecret(transcript hash.Hash) []byte {
+	return DeriveSecret(s.h, s.secret, labelClientAppTraffic, transcript)
+}
+
+// ServerApplicationTrafficSecret derives the first server application
+// traffic secret.
+// transcript covers ClientHello...server Finished.
+func (s *MasterSecret) ServerApplicationTrafficSecret(transcript hash.Hash) []byte {
+	return DeriveSecret(s.h, s.secret, labelServerAppTraffic, transcript)
+}
+
+// ExporterMasterSecret derives the exporter master secret.
+// transcript covers ClientHello...server Finished.
+func (s *MasterSecret) ExporterMasterSecret(transcript hash.Hash) *ExporterMasterSecret {
+	return &ExporterMasterSecret{
+		h:      s.h,
+		secret: DeriveSecret(s.h, s.secret, labelExporter, transcript),
+	}
+}
+
+// ResumptionMasterSecret derives the resumption master secret.
+// transcript covers ClientHello...client Finished.
+func (s *MasterSecret) ResumptionMasterSecret(transcript hash.Hash) []byte {
+	return DeriveSecret(s.h, s.secret, labelResumption, transcript)
+}
+
+// ExporterMasterSecret is an (early) exporter master secret.
+type ExporterMasterSecret struct {
+	h      func() hash.Hash
+	secret []byte
+}
+
+// Secret returns the exporter master secret itself.
+func (s *ExporterMasterSecret) Secret() []byte {
+	return s.secret
+}
+
+// Exporter implements TLS-Exporter.
+// c.f. RFC8446 7.5
+func (s *ExporterMasterSecret) Exporter(label string, context []byte, length int) []byte {
+	secret := DeriveSecret(s.h, s.secret, label, nil)
+
+	contextHash := s.h()
+	contextHash.Write(context)
+
+	return ExpandLabel(s.h, secret, labelExporterValue, contextHash.Sum(nil), length)
+}
+
+// TrafficKey derives the write key and IV from a traffic secret.
+// c.f. RFC8446 7.3
+func TrafficKey(h func() hash.Hash, trafficSecret []byte, keyLen, ivLen int) (key, iv []byte) {
+	key = ExpandLabel(h, trafficSecret, labelKey, nil, keyLen)
+	iv = ExpandLabel(h, trafficSecret, labelIV, nil, ivLen)
+
+	return key, iv
+}
+
+// NextTrafficSecret derives the next application traffic secret after a
+// KeyUpdate.
+// c.f. RFC8446 7.2
+func NextTrafficSecret(h func() hash.Hash, trafficSecret []byte) []byte {
+	return ExpandLabel(h, trafficSecret, labelTrafficUpdate, nil, h().Size())
+}
+
+// FinishedKey derives the key used to compute the Finished message from a
+// handshake traffic secret (or a binder key).
+// c.f. RFC8446 4.4.4
+func FinishedKey(h func() hash.Hash, baseKey []byte) []byte {
+	return ExpandLabel(h, baseKey, labelFinished, nil, h().Size())
+}
+
+// VerifyData computes the verify_data of a Finished message.
+// transcript covers the handshake messages up to, but not including, the
+// Finished message.
+// c.f. RFC8446 4.4.4
+func VerifyData(h func() hash.Hash, baseKey []byte, transcript hash.Hash) []byte {
+	mac := hmac.New(h, FinishedKey(h, baseKey))
+	mac.Write(transcript.Sum(nil))
+
+	return mac.Sum(nil)
+}
+
+// ResumptionPSK derives the PSK associated with a ticket.
+// c.f. RFC8446 4.6.1
+func ResumptionPSK(h func() hash.Hash, resumptionMasterSecret, ticketNonce []byte) []byte {
+	return ExpandLabel(h, resumptionMasterSecret, labelResumptionPSK, ticketNonce, h().Size())
+}
+
+// Binder computes a PSK binder.
+// transcript covers the partial ClientHello, up to and including the
+// PreSharedKeyExtension.identities field.
+// c.f. RFC8446 4.2.11.2
+func Binder(h func() hash.Hash, binderKey []byte, transcript hash.Hash) []byte {
+	return VerifyData(h, binderKey, transcript)
+}
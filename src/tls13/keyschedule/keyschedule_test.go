@@ -0,0 +1,134 @@
+package keyschedule
+
+import (
+	"encoding/hex"
+	"hash"
+	"testing"
+
+	"github.com/loicbacciga/crypto-go/src/hash/sha256"
+)
+
+// Values from RFC 8448 (https://datatracker.ietf.org/doc/html/rfc8448).
+// The traces only give the transcript hashes, not all the messages.
+
+func fromHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// fixedHash is a hash.Hash whose Sum is a fixed transcript hash
+type fixedHash struct {
+	sum []byte
+}
+
+func (f *fixedHash) Write(p []byte) (int, error) { return len(p), nil }
+func (f *fixedHash) Sum(b []byte) []byte         { return append(b, f.sum...) }
+func (f *fixedHash) Reset()                      {}
+func (f *fixedHash) Size() int                   { return len(f.sum) }
+func (f *fixedHash) BlockSize() int              { return sha256.BlockSize }
+
+func transcript(s string) hash.Hash {
+	return &fixedHash{fromHex(s)}
+}
+
+func check(t *testing.T, name string, res []byte, expHex string) {
+	t.Helper()
+
+	if resHex := hex.EncodeToString(res); resHex != expHex {
+		t.Errorf("%s: %s != %s", name, resHex, expHex)
+	}
+}
+
+// RFC 8448 section 3
+func TestSimple1RTT(t *testing.T) {
+	h := sha256.New256
+
+	es := NewEarlySecret(h, nil)
+	check(t, "early secret", es.Secret(), "33ad0a1c607ec03b09e6cd9893680ce210adf300aa1f2660e1b22e10f170f92a")
+	check(t, "derived", DeriveSecret(h, es.Secret(), "derived", nil), "6f2615a108c702c5678f54fc9dbab69716c076189c48250cebeac3576c3611ba")
+
+	hs := es.HandshakeSecret(fromHex("8bd4054fb55b9d63fdfbacf9f04b9f0d35e6d63f537563efd46272900f89492d"))
+	check(t, "handshake secret", hs.Secret(), "1dc826e93606aa6fdc0aadc12f741b01046aa6b99f691ed221a9f0ca043fbeac")
+
+	// ClientHello...ServerHello
+	th := "860c06edc07858ee8e78f0e7428c58edd6b43f2ca3e6e95f02ed063cf0e1cad8"
+	chts := hs.ClientHandshakeTrafficSecret(transcript(th))
+	shts := hs.ServerHandshakeTrafficSecret(transcript(th))
+	check(t, "client handshake traffic secret", chts, "b3eddb126e067f35a780b3abf45e2d8f3b1a950738f52e9600746a0e27a55a21")
+	check(t, "server handshake traffic secret", shts, "b67b7d690cc16c4e75e54213cb2d37b4e9c912bcded9105d42befd59d391ad38")
+
+	key, iv := TrafficKey(h, shts, 16, 12)
+	check(t, "server handshake key", key, "3fce516009c21727d0f2e4e86ee403bc")
+	check(t, "server handshake iv", iv, "5d313eb2671276ee13000b30")
+	key, iv = TrafficKey(h, chts, 16, 12)
+	check(t, "client handshake key", key, "dbfaa693d1762c5b666af5d950258d01")
+	check(t, "client handshake iv", iv, "5bd3c71b836e0b76bb73265f")
+
+	check(t, "server finished key", FinishedKey(h, shts), "008d3b66f816ea559f96b537e885c31fc068bf492c652f01f288a1d8cdc19fc8")
+	check(t, "client finished key", FinishedKey(h, chts), "b80ad01015fb2f0bd65ff7d4da5d6bf83f84821d1f87fdc7d3c75b5a7b42d9c4")
+
+	// ClientHello...CertificateVerify
+	th = "edb7725fa7a3473b031ec8ef65a2485493900138a2b91291407d7951a06110ed"
+	check(t, "server verify data", VerifyData(h, shts, transcript(th)), "9b9b141d906337fbd2cbdce71df4deda4ab42c309572cb7fffee5454b78f0718")
+
+	ms := hs.MasterSecret()
+	check(t, "master secret", ms.Secret(), "18df06843d13a08bf2a449844c5f8a478001bc4d4c627984d5a41da8d0402919")
+
+	// ClientHello...server Finished
+	th = "9608102a0f1ccc6db6250b7b7e417b1a000eaada3daae4777a7686c9ff83df13"
+	cats := ms.ClientApplicationTrafficSecret(transcript(th))
+	sats := ms.ServerApplicationTrafficSecret(transcript(th))
+	check(t, "client application traffic secret", cats, "9e40646ce79a7f9dc05af8889bce6552875afa0b06df0087f792ebb7c17504a5")
+	check(t, "server application traffic secret", sats, "a11af9f05531f856ad47116b45a950328204b4f44bfb6b3a4b4f1f3fcb631643")
+	check(t, "exporter master secret", ms.ExporterMasterSecret(transcript(th)).Secret(), "fe22f881176eda18eb8f44529e6792c50c9a3f89452f68d8ae311b4309d3cf50")
+
+	key, iv = TrafficKey(h, sats, 16, 12)
+	check(t, "server application key", key, "9f02283b6c9c07efc26bb9f2ac92e356")
+	check(t, "server application iv", iv, "cf782b88dd83549aadf1e984")
+	key, iv = TrafficKey(h, cats, 16, 12)
+	check(t, "client application key", key, "17422dda596ed5d9acd890e3c63f5051")
+	check(t, "client application iv", iv, "5b78923dee08579033e523d9")
+
+	// ClientHello...client Finished
+	th = "209145a96ee8e2a122ff810047cc952684658d6049e86429426db87c54ad143d"
+	rms := ms.ResumptionMasterSecret(transcript(th))
+	check(t, "resumption master secret", rms, "7df235f2031d2a051287d02b0241b0bfdaf86cc856231f2d5aba46c434ec196c")
+	check(t, "resumption PSK", ResumptionPSK(h, rms, []byte{0, 0}), "4ecd0eb6ec3b4d87f5d6028f922ca4c5851a277fd41311c9e62d2c9492e1c4f3")
+}
+
+// RFC 8448 section 4
+func TestResumedBinder(t *testing.T) {
+	h := sha256.New256
+
+	es := NewEarlySecret(h, fromHex("4ecd0eb6ec3b4d87f5d6028f922ca4c5851a277fd41311c9e62d2c9492e1c4f3"))
+	check(t, "early secret", es.Secret(), "9b2188e9b2fc6d64d71dc329900e20bb41915000f678aa839cbb797cb7d8332c")
+
+	binderKey := es.ResumptionBinderKey()
+	check(t, "binder key", binderKey, "69fe131a3bbad5d63c64eebcc30e395b9d8107726a13d074e389dbc8a4e47256")
+	check(t, "binder finished key", FinishedKey(h, binderKey), "5588673e72cb59c87d220caffe94f2dea9a3b1609f7d50e90a48227db9ed7eaa")
+
+	// Truncated ClientHello
+	th := "63224b2e4573f2d3454ca84b9d009a04f6be9e05711a8396473aefa01e924a14"
+	check(t, "binder", Binder(h, binderKey, transcript(th)), "3add4fb2d8fdf822a0ca3cf7678ef5e88dae990141c5924d57bb6fa31b9e5f9d")
+}
+
+func TestNextTrafficSecret(t *testing.T) {
+	// Update of the client application traffic secret of RFC 8448 section
+	// 3, computed with the HKDF of OpenSSL
+	secret := fromHex("9e40646ce79a7f9dc05af8889bce6552875afa0b06df0087f792ebb7c17504a5")
+	check(t, "next traffic secret", NextTrafficSecret(sha256.New256, secret), "fcdfcc72725aaee48bf64e4fd8b749cdbdbab39d90da0b26e2245ca6ea167207")
+}
+
+func TestExpandLabelTooLong(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("long label accepted")
+		}
+	}()
+
+	label := make([]byte, 250)
+	ExpandLabel(sha256.New256, make([]byte, 32), string(label), nil, 32)
+}